@@ -59,6 +59,20 @@ func (s *apply) Run() error {
 		SkipGc:       s.options.SkipGc,
 		DryRun:       s.options.DryRun,
 		ClientConfig: s.options.Client,
+
+		Strategy:       s.options.Strategy,
+		FieldManager:   s.options.FieldManager,
+		ForceConflicts: s.options.ForceConflicts,
+
+		Wait:        s.options.Wait,
+		WaitTimeout: s.options.WaitTimeout,
+
+		RollbackOnFailure: s.options.RollbackOnFailure,
+
+		Retry:       s.options.Retry,
+		Parallelism: s.options.Parallelism,
+		Inventory:   s.options.Inventory,
+		Plan:        s.options.Plan,
 	}
 
 	return c.Run(objects, "")
@@ -0,0 +1,59 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/spf13/afero"
+)
+
+// ComponentCreate creates a component in a namespace which creates an object
+// of a kind using its k8s.libsonnet constructor.
+func ComponentCreate(fs afero.Fs, nsName, name, apiVersion, kind string) error {
+	cc, err := newComponentCreate(fs, nsName, name, apiVersion, kind)
+	if err != nil {
+		return err
+	}
+
+	return cc.run()
+}
+
+type componentCreate struct {
+	nsName     string
+	name       string
+	apiVersion string
+	kind       string
+	out        io.Writer
+
+	*base
+}
+
+func newComponentCreate(fs afero.Fs, nsName, name, apiVersion, kind string) (*componentCreate, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := &componentCreate{
+		nsName:     nsName,
+		name:       name,
+		apiVersion: apiVersion,
+		kind:       kind,
+		out:        os.Stdout,
+		base:       b,
+	}
+
+	return cc, nil
+}
+
+func (cc *componentCreate) run() error {
+	c, err := component.Create(cc.app, cc.nsName, cc.name, cc.apiVersion, cc.kind)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(cc.out, "created component %s\n", c.Name(true))
+	return err
+}
@@ -1,18 +1,29 @@
 package action
 
 import (
+	"io"
 	"os"
 	"sort"
 
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/ksutil"
-	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
 
-// ComponentList create a list of components in a namespace.
-func ComponentList(fs afero.Fs, namespace, output string) error {
-	cl, err := newComponentList(fs, namespace, output)
+const (
+	// outputWide lists components with the objects they generate.
+	outputWide = "wide"
+)
+
+var (
+	componentListHeader     = []string{"component"}
+	componentListWideHeader = []string{"component", "type", "index", "apiversion", "kind", "name"}
+)
+
+// ComponentList create a list of components in a namespace. Components are
+// sorted by name unless sortBy names another column.
+func ComponentList(fs afero.Fs, namespace, output, sortBy string) error {
+	cl, err := newComponentList(fs, namespace, output, sortBy)
 	if err != nil {
 		return err
 	}
@@ -23,11 +34,13 @@ func ComponentList(fs afero.Fs, namespace, output string) error {
 type componentList struct {
 	nsName string
 	output string
+	sortBy string
+	out    io.Writer
 
 	*base
 }
 
-func newComponentList(fs afero.Fs, namespace, output string) (*componentList, error) {
+func newComponentList(fs afero.Fs, namespace, output, sortBy string) (*componentList, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
@@ -36,6 +49,8 @@ func newComponentList(fs afero.Fs, namespace, output string) (*componentList, er
 	cl := &componentList{
 		nsName: namespace,
 		output: output,
+		sortBy: sortBy,
+		out:    os.Stdout,
 		base:   b,
 	}
 
@@ -43,6 +58,11 @@ func newComponentList(fs afero.Fs, namespace, output string) (*componentList, er
 }
 
 func (cl *componentList) run() error {
+	p, err := ksutil.NewPrinter(cl.out, cl.output, outputWide)
+	if err != nil {
+		return err
+	}
+
 	ns, err := component.GetNamespace(cl.app, cl.nsName)
 	if err != nil {
 		return err
@@ -53,63 +73,88 @@ func (cl *componentList) run() error {
 		return err
 	}
 
-	switch cl.output {
-	default:
-		return errors.Errorf("invalid output option %q", cl.output)
-	case "":
-		cl.listComponents(components)
-	case "wide":
-		if err := cl.listComponentsWide(components); err != nil {
-			return err
-		}
+	if p.Format() == outputWide {
+		return cl.listComponentsWide(p, components)
 	}
 
-	return nil
+	return cl.listComponents(p, components)
 }
 
-func (cl *componentList) listComponents(components []component.Component) {
-	var list []string
+func (cl *componentList) listComponents(p *ksutil.Printer, components []component.Component) error {
+	items := make([]componentListItem, 0, len(components))
 	for _, c := range components {
-		list = append(list, c.Name(false))
+		items = append(items, componentListItem{Name: c.Name(false)})
 	}
 
-	sort.Strings(list)
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
 
-	table := ksutil.NewTable(os.Stdout)
-	table.SetHeader([]string{"component"})
-	for _, item := range list {
-		table.Append([]string{item})
+	row := func(i int) []string {
+		return []string{items[i].Name}
 	}
-	table.Render()
+
+	if err := ksutil.SortByColumn(items, componentListHeader, cl.sortBy, row); err != nil {
+		return err
+	}
+
+	return p.Print(items, func(w io.Writer) error {
+		table := ksutil.NewTable(w)
+		table.SetHeader(componentListHeader)
+		for i := range items {
+			table.Append(row(i))
+		}
+		table.Render()
+		return nil
+	})
 }
 
-func (cl *componentList) listComponentsWide(components []component.Component) error {
-	var rows [][]string
+func (cl *componentList) listComponentsWide(p *ksutil.Printer, components []component.Component) error {
+	summaries := make([]component.Summary, 0)
 	for _, c := range components {
-		summaries, err := c.Summarize()
+		cs, err := c.Summarize()
 		if err != nil {
 			return err
 		}
 
-		for _, summary := range summaries {
-			row := []string{
-				summary.ComponentName,
-				summary.Type,
-				summary.IndexStr,
-				summary.APIVersion,
-				summary.Kind,
-				summary.Name,
-			}
-
-			rows = append(rows, row)
+		summaries = append(summaries, cs...)
+	}
 
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].ComponentName != summaries[j].ComponentName {
+			return summaries[i].ComponentName < summaries[j].ComponentName
+		}
+		return summaries[i].Index < summaries[j].Index
+	})
+
+	row := func(i int) []string {
+		summary := summaries[i]
+		return []string{
+			summary.ComponentName,
+			summary.Type,
+			summary.IndexStr,
+			summary.APIVersion,
+			summary.Kind,
+			summary.Name,
 		}
 	}
 
-	table := ksutil.NewTable(os.Stdout)
-	table.SetHeader([]string{"component", "type", "index", "apiversion", "kind", "name"})
-	table.AppendBulk(rows)
-	table.Render()
+	if err := ksutil.SortByColumn(summaries, componentListWideHeader, cl.sortBy, row); err != nil {
+		return err
+	}
+
+	return p.Print(summaries, func(w io.Writer) error {
+		table := ksutil.NewTable(w)
+		table.SetHeader(componentListWideHeader)
+		for i := range summaries {
+			table.Append(row(i))
+		}
+		table.Render()
+		return nil
+	})
+}
 
-	return nil
+// componentListItem is a component in a component list.
+type componentListItem struct {
+	Name string `json:"name"`
 }
@@ -0,0 +1,62 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/spf13/afero"
+)
+
+// ComponentMove moves a component to another namespace and/or renames it.
+func ComponentMove(fs afero.Fs, from, to string) error {
+	cm, err := newComponentMove(fs, from, to)
+	if err != nil {
+		return err
+	}
+
+	return cm.run()
+}
+
+type componentMove struct {
+	from string
+	to   string
+	out  io.Writer
+
+	*base
+}
+
+func newComponentMove(fs afero.Fs, from, to string) (*componentMove, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &componentMove{
+		from: from,
+		to:   to,
+		out:  os.Stdout,
+		base: b,
+	}
+
+	return cm, nil
+}
+
+func (cm *componentMove) run() error {
+	c, envNames, err := component.Move(cm.app, cm.from, cm.to)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(cm.out, "moved component %s to %s\n", cm.from, c.Name(true)); err != nil {
+		return err
+	}
+
+	if len(envNames) > 0 {
+		_, err = fmt.Fprintf(cm.out, "updated targets for environments: %s\n", strings.Join(envNames, ", "))
+	}
+
+	return err
+}
@@ -0,0 +1,107 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/spf13/afero"
+)
+
+// ComponentRemove removes a component and its params.
+func ComponentRemove(fs afero.Fs, name string, opts ...ComponentRemoveOpt) error {
+	cr, err := newComponentRemove(fs, name, opts...)
+	if err != nil {
+		return err
+	}
+
+	return cr.run()
+}
+
+// ComponentRemoveOpt is an option for configuring ComponentRemove.
+type ComponentRemoveOpt func(*componentRemove)
+
+// ComponentRemoveWithDryRun shows the changes to params instead of removing
+// the component.
+func ComponentRemoveWithDryRun(dryRun bool) ComponentRemoveOpt {
+	return func(cr *componentRemove) {
+		cr.dryRun = dryRun
+	}
+}
+
+// ComponentRemoveWithColor sets if the dry run diff is colored.
+func ComponentRemoveWithColor(color bool) ComponentRemoveOpt {
+	return func(cr *componentRemove) {
+		cr.color = color
+	}
+}
+
+type componentRemove struct {
+	name   string
+	dryRun bool
+	color  bool
+	out    io.Writer
+
+	*base
+}
+
+func newComponentRemove(fs afero.Fs, name string, opts ...ComponentRemoveOpt) (*componentRemove, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &componentRemove{
+		name: name,
+		out:  os.Stdout,
+		base: b,
+	}
+
+	for _, opt := range opts {
+		opt(cr)
+	}
+
+	return cr, nil
+}
+
+func (cr *componentRemove) run() error {
+	r, err := component.PlanRemove(cr.app, cr.name)
+	if err != nil {
+		return err
+	}
+
+	if !cr.dryRun {
+		if err := r.Apply(); err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(cr.out, "removed component %s\n", cr.name)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(cr.out, "would remove %s\n", cr.rel(r.Source)); err != nil {
+		return err
+	}
+
+	for _, change := range r.Changes {
+		path := cr.rel(change.Path)
+		if _, err := ksutil.FprintDiff(cr.out, change.From, change.To, "a/"+path, "b/"+path, cr.color); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rel returns a path relative to the app root.
+func (cr *componentRemove) rel(path string) string {
+	rel, err := filepath.Rel(cr.app.Root(), path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
@@ -70,6 +70,7 @@ func (s *delete) Run() error {
 		Env:          s.env,
 		GracePeriod:  s.options.GracePeriod,
 		ClientConfig: s.options.Client,
+		Retry:        s.options.Retry,
 	}
 
 	return c.Run(objects)
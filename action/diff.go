@@ -0,0 +1,99 @@
+package action
+
+import (
+	"io"
+	"os"
+
+	"github.com/bryanl/woowoo/k8sutil"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/bryanl/woowoo/pkg/client"
+	"github.com/spf13/afero"
+)
+
+// Diff compares an environment with its live cluster state. It returns an error
+// if the live state has drifted.
+func Diff(fs afero.Fs, env string, config *client.Config, opts ...DiffOpt) error {
+	d, err := newDiff(fs, env, config, opts...)
+	if err != nil {
+		return err
+	}
+
+	return d.Run()
+}
+
+// DiffOpt is an option for configuring Diff.
+type DiffOpt func(*diff)
+
+// DiffWithComponents selects the components to be compared.
+func DiffWithComponents(names ...string) DiffOpt {
+	return func(d *diff) {
+		d.components = names
+	}
+}
+
+// DiffWithColor sets if the diff output is colored.
+func DiffWithColor(color bool) DiffOpt {
+	return func(d *diff) {
+		d.color = color
+	}
+}
+
+// DiffWithGcTag sets the gc tag the environment is applied with.
+func DiffWithGcTag(tag string) DiffOpt {
+	return func(d *diff) {
+		d.gcTag = tag
+	}
+}
+
+// Diff is a diff Action
+type diff struct {
+	env        string
+	components []string
+	config     *client.Config
+	color      bool
+	gcTag      string
+	out        io.Writer
+
+	*base
+}
+
+// newDiff creates an instance of diff.
+func newDiff(fs afero.Fs, env string, config *client.Config, opts ...DiffOpt) (*diff, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &diff{
+		env:    env,
+		config: config,
+		out:    os.Stdout,
+		base:   b,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d, nil
+}
+
+// Run runs the action.
+func (d *diff) Run() error {
+	p := pipeline.New(d.app, d.env)
+
+	objects, err := p.Objects(d.components)
+	if err != nil {
+		return err
+	}
+
+	c := k8sutil.DiffCmd{
+		Env:          d.env,
+		ClientConfig: d.config,
+		Out:          d.out,
+		Color:        d.color,
+		GcTag:        d.gcTag,
+	}
+
+	return c.Run(objects)
+}
@@ -1,15 +1,18 @@
 package action
 
 import (
+	"io"
 	"os"
 
+	"github.com/bryanl/woowoo/ksutil"
 	"github.com/go-yaml/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
 	"github.com/spf13/afero"
 )
 
 // EnvDescribe describes an environment by printing its configuration.
-func EnvDescribe(fs afero.Fs, envName string) error {
-	ed, err := newEnvDescribe(fs, envName)
+func EnvDescribe(fs afero.Fs, envName, output string) error {
+	ed, err := newEnvDescribe(fs, envName, output)
 	if err != nil {
 		return err
 	}
@@ -19,11 +22,13 @@ func EnvDescribe(fs afero.Fs, envName string) error {
 
 type envDescribe struct {
 	envName string
+	output  string
+	out     io.Writer
 
 	*base
 }
 
-func newEnvDescribe(fs afero.Fs, envName string) (*envDescribe, error) {
+func newEnvDescribe(fs afero.Fs, envName, output string) (*envDescribe, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
@@ -31,13 +36,27 @@ func newEnvDescribe(fs afero.Fs, envName string) (*envDescribe, error) {
 
 	ed := &envDescribe{
 		envName: envName,
+		output:  output,
+		out:     os.Stdout,
 		base:    b,
 	}
 
 	return ed, nil
 }
 
+// envDescription is an environment's configuration. The spec doesn't encode
+// the environment's name to JSON, so it is added.
+type envDescription struct {
+	Name string `json:"name"`
+	*app.EnvironmentSpec
+}
+
 func (ed *envDescribe) Run() error {
+	p, err := ksutil.NewPrinter(ed.out, ed.output)
+	if err != nil {
+		return err
+	}
+
 	env, err := ed.app.Environment(ed.envName)
 	if err != nil {
 		return err
@@ -45,5 +64,7 @@ func (ed *envDescribe) Run() error {
 
 	env.Name = ed.envName
 
-	return yaml.NewEncoder(os.Stdout).Encode(env)
+	return p.Print(envDescription{Name: ed.envName, EnvironmentSpec: env}, func(w io.Writer) error {
+		return yaml.NewEncoder(w).Encode(env)
+	})
 }
@@ -1,15 +1,20 @@
 package action
 
 import (
+	"io"
 	"os"
+	"sort"
 
 	"github.com/bryanl/woowoo/ksutil"
 	"github.com/spf13/afero"
 )
 
-// EnvList lists available namespaces
-func EnvList(fs afero.Fs) error {
-	nl, err := newEnvList(fs)
+var envListHeader = []string{"name", "kubernetes-version", "namespace", "server"}
+
+// EnvList lists available environments. Environments are sorted by name
+// unless sortBy names another column.
+func EnvList(fs afero.Fs, output, sortBy string) error {
+	nl, err := newEnvList(fs, output, sortBy)
 	if err != nil {
 		return err
 	}
@@ -18,40 +23,89 @@ func EnvList(fs afero.Fs) error {
 }
 
 type envList struct {
+	output string
+	sortBy string
+	out    io.Writer
+
 	*base
 }
 
-func newEnvList(fs afero.Fs) (*envList, error) {
+func newEnvList(fs afero.Fs, output, sortBy string) (*envList, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
 	}
 
 	nl := &envList{
-		base: b,
+		output: output,
+		sortBy: sortBy,
+		out:    os.Stdout,
+		base:   b,
 	}
 
 	return nl, nil
 }
 
+// envListItem is an environment in an environment list.
+type envListItem struct {
+	Name              string `json:"name"`
+	KubernetesVersion string `json:"kubernetesVersion"`
+	Namespace         string `json:"namespace"`
+	Server            string `json:"server"`
+}
+
 func (nl *envList) Run() error {
-	environments, err := nl.app.Environments()
+	p, err := ksutil.NewPrinter(nl.out, nl.output)
 	if err != nil {
 		return err
 	}
 
-	table := ksutil.NewTable(os.Stdout)
-	table.SetHeader([]string{"name", "kubernetes-version", "namespace", "server"})
+	environments, err := nl.app.Environments()
+	if err != nil {
+		return err
+	}
 
+	items := make([]envListItem, 0, len(environments))
 	for name, env := range environments {
-		table.Append([]string{
-			name,
-			env.KubernetesVersion,
-			env.Destination.Namespace,
-			env.Destination.Server,
-		})
+		item := envListItem{
+			Name:              name,
+			KubernetesVersion: env.KubernetesVersion,
+		}
+
+		if env.Destination != nil {
+			item.Namespace = env.Destination.Namespace
+			item.Server = env.Destination.Server
+		}
+
+		items = append(items, item)
 	}
 
-	table.Render()
-	return nil
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	row := func(i int) []string {
+		return []string{
+			items[i].Name,
+			items[i].KubernetesVersion,
+			items[i].Namespace,
+			items[i].Server,
+		}
+	}
+
+	if err := ksutil.SortByColumn(items, envListHeader, nl.sortBy, row); err != nil {
+		return err
+	}
+
+	return p.Print(items, func(w io.Writer) error {
+		table := ksutil.NewTable(w)
+		table.SetHeader(envListHeader)
+
+		for i := range items {
+			table.Append(row(i))
+		}
+
+		table.Render()
+		return nil
+	})
 }
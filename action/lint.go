@@ -0,0 +1,109 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Lint reports problems with component params. It returns an error if any
+// problems remain.
+func Lint(fs afero.Fs, opts ...LintOpt) error {
+	l, err := newLint(fs, opts...)
+	if err != nil {
+		return err
+	}
+
+	return l.run()
+}
+
+// LintOpt is an option for configuring Lint.
+type LintOpt func(*lint)
+
+// LintWithFix fixes the problems which can be fixed.
+func LintWithFix(fix bool) LintOpt {
+	return func(l *lint) {
+		l.fix = fix
+	}
+}
+
+type lint struct {
+	fix bool
+	out io.Writer
+
+	*base
+}
+
+func newLint(fs afero.Fs, opts ...LintOpt) (*lint, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &lint{
+		out:  os.Stdout,
+		base: b,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l, nil
+}
+
+func (l *lint) run() error {
+	problems, err := component.Lint(l.app)
+	if err != nil {
+		return err
+	}
+
+	var fixErr error
+	if l.fix {
+		fixErr = component.FixLint(l.app, problems)
+	}
+	fixed := l.fix && fixErr == nil
+
+	remaining := 0
+	for _, p := range problems {
+		status := ""
+		if fixed && p.Fixable {
+			status = " (fixed)"
+		} else {
+			remaining++
+		}
+
+		if _, err := fmt.Fprintf(l.out, "%s: %s%s\n", l.location(p), p.Message, status); err != nil {
+			return err
+		}
+	}
+
+	if fixErr != nil {
+		return errors.Wrap(fixErr, "fix problems")
+	}
+
+	if remaining > 0 {
+		return errors.Errorf("found %d problems", remaining)
+	}
+
+	return nil
+}
+
+// location formats a problem's location relative to the app root.
+func (l *lint) location(p component.LintProblem) string {
+	path := p.Path
+	if rel, err := filepath.Rel(l.app.Root(), path); err == nil {
+		path = rel
+	}
+
+	if p.Line == 0 {
+		return path
+	}
+
+	return fmt.Sprintf("%s:%d", path, p.Line)
+}
@@ -1,16 +1,27 @@
 package action
 
 import (
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/ksutil"
+	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
 
-// NsList lists available namespaces
-func NsList(fs afero.Fs) error {
-	nl, err := newNsList(fs)
+var (
+	nsListHeader = []string{"namespace"}
+	nsTreeHeader = []string{"namespace", "components"}
+)
+
+// NsList lists available namespaces. Namespaces are sorted by name. If tree
+// is set, nested namespaces are listed under their parents along with the
+// number of components in each namespace.
+func NsList(fs afero.Fs, output, sortBy string, tree bool) error {
+	nl, err := newNsList(fs, output, sortBy, tree)
 	if err != nil {
 		return err
 	}
@@ -19,35 +30,125 @@ func NsList(fs afero.Fs) error {
 }
 
 type nsList struct {
+	output string
+	sortBy string
+	tree   bool
+	out    io.Writer
+
 	*base
 }
 
-func newNsList(fs afero.Fs) (*nsList, error) {
+func newNsList(fs afero.Fs, output, sortBy string, tree bool) (*nsList, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
 	}
 
 	nl := &nsList{
-		base: b,
+		output: output,
+		sortBy: sortBy,
+		tree:   tree,
+		out:    os.Stdout,
+		base:   b,
 	}
 
 	return nl, nil
 }
 
+// nsListItem is a namespace in a namespace list.
+type nsListItem struct {
+	Name string `json:"name"`
+}
+
+// nsTreeItem is a namespace in a namespace tree.
+type nsTreeItem struct {
+	Name       string       `json:"name"`
+	Components int          `json:"components"`
+	Children   []nsTreeItem `json:"children,omitempty"`
+}
+
 func (nl *nsList) Run() error {
-	namespaces, err := component.Namespaces(nl.app)
+	p, err := ksutil.NewPrinter(nl.out, nl.output)
 	if err != nil {
 		return err
 	}
 
-	table := ksutil.NewTable(os.Stdout)
-	table.SetHeader([]string{"namespace"})
+	if nl.tree {
+		return nl.runTree(p)
+	}
+
+	namespaces, err := component.Namespaces(nl.app)
+	if err != nil {
+		return err
+	}
 
+	items := make([]nsListItem, 0, len(namespaces))
 	for _, ns := range namespaces {
-		table.Append([]string{ns.Name()})
+		items = append(items, nsListItem{Name: ns.Name()})
+	}
+
+	row := func(i int) []string {
+		return []string{items[i].Name}
+	}
+
+	if err := ksutil.SortByColumn(items, nsListHeader, nl.sortBy, row); err != nil {
+		return err
+	}
+
+	return p.Print(items, func(w io.Writer) error {
+		table := ksutil.NewTable(w)
+		table.SetHeader(nsListHeader)
+
+		for i := range items {
+			table.Append(row(i))
+		}
+
+		table.Render()
+		return nil
+	})
+}
+
+func (nl *nsList) runTree(p *ksutil.Printer) error {
+	if nl.sortBy != "" {
+		return errors.New("namespace trees can't be sorted")
+	}
+
+	root, err := component.NamespaceTree(nl.app)
+	if err != nil {
+		return err
+	}
+
+	item := newNsTreeItem(root)
+
+	return p.Print(item, func(w io.Writer) error {
+		table := ksutil.NewTable(w)
+		table.SetHeader(nsTreeHeader)
+		appendNsTreeRows(table, item, "", 0)
+		table.Render()
+		return nil
+	})
+}
+
+func newNsTreeItem(node *component.NamespaceNode) nsTreeItem {
+	item := nsTreeItem{
+		Name:       node.Namespace.Name(),
+		Components: node.Components,
+	}
+
+	for _, child := range node.Children {
+		item.Children = append(item.Children, newNsTreeItem(child))
 	}
 
-	table.Render()
-	return nil
+	return item
+}
+
+// appendNsTreeRows appends a row for a namespace and its children. Nested
+// namespaces are indented and named relative to their parent.
+func appendNsTreeRows(table *ksutil.Table, item nsTreeItem, parent string, depth int) {
+	name := strings.TrimPrefix(item.Name, parent+"/")
+	table.Append([]string{strings.Repeat("  ", depth) + name, strconv.Itoa(item.Components)})
+
+	for _, child := range item.Children {
+		appendNsTreeRows(table, child, item.Name, depth+1)
+	}
 }
@@ -0,0 +1,63 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/spf13/afero"
+)
+
+// NsMove moves a component namespace and rewrites environment targets which
+// refer to it.
+func NsMove(fs afero.Fs, from, to string) error {
+	nm, err := newNsMove(fs, from, to)
+	if err != nil {
+		return err
+	}
+
+	return nm.Run()
+}
+
+type nsMove struct {
+	from string
+	to   string
+	out  io.Writer
+
+	*base
+}
+
+func newNsMove(fs afero.Fs, from, to string) (*nsMove, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	nm := &nsMove{
+		from: from,
+		to:   to,
+		out:  os.Stdout,
+		base: b,
+	}
+
+	return nm, nil
+}
+
+func (nm *nsMove) Run() error {
+	envNames, err := component.MoveNamespace(nm.app, nm.from, nm.to)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(nm.out, "moved namespace %s to %s\n", nm.from, nm.to); err != nil {
+		return err
+	}
+
+	if len(envNames) > 0 {
+		_, err = fmt.Fprintf(nm.out, "updated targets for environments: %s\n", strings.Join(envNames, ", "))
+	}
+
+	return err
+}
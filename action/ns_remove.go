@@ -0,0 +1,67 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// NsRemove removes a component namespace. Namespaces which are environment
+// targets are only removed if force is set.
+func NsRemove(fs afero.Fs, nsName string, force bool) error {
+	nr, err := newNsRemove(fs, nsName, force)
+	if err != nil {
+		return err
+	}
+
+	return nr.Run()
+}
+
+type nsRemove struct {
+	nsName string
+	force  bool
+	out    io.Writer
+
+	*base
+}
+
+func newNsRemove(fs afero.Fs, nsName string, force bool) (*nsRemove, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	nr := &nsRemove{
+		nsName: nsName,
+		force:  force,
+		out:    os.Stdout,
+		base:   b,
+	}
+
+	return nr, nil
+}
+
+func (nr *nsRemove) Run() error {
+	envNames, err := component.RemoveNamespace(nr.app, nr.nsName, nr.force)
+	if err != nil {
+		if _, ok := err.(*component.TargetedError); ok {
+			return errors.Wrap(err, "use --force to remove it anyway")
+		}
+		return err
+	}
+
+	if _, err := fmt.Fprintf(nr.out, "removed namespace %s\n", nr.nsName); err != nil {
+		return err
+	}
+
+	if len(envNames) > 0 {
+		_, err = fmt.Fprintf(nr.out, "updated targets for environments: %s\n", strings.Join(envNames, ", "))
+	}
+
+	return err
+}
@@ -1,9 +1,8 @@
 package action
 
 import (
-	"strings"
-
 	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/params"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
@@ -21,6 +20,13 @@ func ParamDelete(fs afero.Fs, componentName, path string, opts ...ParamDeleteOpt
 // ParamDeleteOpt is an option for configuration ParamDelete.
 type ParamDeleteOpt func(*paramDelete)
 
+// ParamDeleteWithEnv sets the environment the param is deleted from.
+func ParamDeleteWithEnv(envName string) ParamDeleteOpt {
+	return func(pd *paramDelete) {
+		pd.envName = envName
+	}
+}
+
 // ParamDeleteWithIndex sets the index for the delete option.
 func ParamDeleteWithIndex(index int) ParamDeleteOpt {
 	return func(pd *paramDelete) {
@@ -33,6 +39,7 @@ type paramDelete struct {
 	componentName string
 	rawPath       string
 	index         int
+	envName       string
 
 	*base
 }
@@ -59,7 +66,10 @@ func newParamDelete(fs afero.Fs, componentName, path string, opts ...ParamDelete
 
 // Run runs the action.
 func (pd *paramDelete) Run() error {
-	path := strings.Split(pd.rawPath, ".")
+	path, err := params.ParsePath(pd.rawPath)
+	if err != nil {
+		return err
+	}
 
 	c, err := component.ExtractComponent(pd.app, pd.componentName)
 	if err != nil {
@@ -69,10 +79,18 @@ func (pd *paramDelete) Run() error {
 	options := component.ParamOptions{
 		Index: pd.index,
 	}
+
+	if pd.envName != "" {
+		if err := component.DeleteEnvParam(pd.app, pd.envName, c, path, options); err != nil {
+			return errors.Wrap(err, "delete environment param")
+		}
+
+		return nil
+	}
+
 	if err := c.DeleteParam(path, options); err != nil {
 		return errors.Wrap(err, "delete param")
 	}
 
 	return nil
-
 }
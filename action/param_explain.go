@@ -0,0 +1,169 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ParamExplain explains how a component parameter's value is resolved.
+func ParamExplain(fs afero.Fs, componentName, path string, opts ...ParamExplainOpt) error {
+	pe, err := newParamExplain(fs, componentName, path, opts...)
+	if err != nil {
+		return err
+	}
+
+	return pe.run()
+}
+
+// ParamExplainOpt is an option for configuring ParamExplain.
+type ParamExplainOpt func(*paramExplain)
+
+// ParamExplainWithEnv includes an environment's overrides in the explanation.
+func ParamExplainWithEnv(envName string) ParamExplainOpt {
+	return func(pe *paramExplain) {
+		pe.envName = envName
+	}
+}
+
+// ParamExplainWithOutput sets the output format.
+func ParamExplainWithOutput(output string) ParamExplainOpt {
+	return func(pe *paramExplain) {
+		pe.output = output
+	}
+}
+
+// ParamExplainWithIndex sets the index for the explain option.
+func ParamExplainWithIndex(index int) ParamExplainOpt {
+	return func(pe *paramExplain) {
+		pe.index = index
+	}
+}
+
+type paramExplain struct {
+	componentName string
+	rawPath       string
+	envName       string
+	index         int
+	output        string
+	out           io.Writer
+
+	*base
+}
+
+func newParamExplain(fs afero.Fs, componentName, path string, opts ...ParamExplainOpt) (*paramExplain, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	pe := &paramExplain{
+		componentName: componentName,
+		rawPath:       path,
+		out:           os.Stdout,
+		base:          b,
+	}
+
+	for _, opt := range opts {
+		opt(pe)
+	}
+
+	return pe, nil
+}
+
+// paramExplanation is how a param's value is resolved.
+type paramExplanation struct {
+	Component string                 `json:"component"`
+	Key       string                 `json:"key"`
+	Env       string                 `json:"env,omitempty"`
+	Layers    []component.ParamLayer `json:"layers"`
+}
+
+func (pe *paramExplain) run() error {
+	p, err := ksutil.NewPrinter(pe.out, pe.output)
+	if err != nil {
+		return err
+	}
+
+	path, err := params.ParsePath(pe.rawPath)
+	if err != nil {
+		return err
+	}
+
+	c, err := component.ExtractComponent(pe.app, pe.componentName)
+	if err != nil {
+		return errors.Wrap(err, "could not find component")
+	}
+
+	ns, _ := component.ExtractNamespacedComponent(pe.app, pe.componentName)
+
+	resolved, err := pe.resolve(ns)
+	if err != nil {
+		return err
+	}
+
+	options := component.ParamOptions{
+		Index: pe.index,
+	}
+	layers, err := ns.ExplainParam(c, options, path, pe.envName, resolved)
+	if err != nil {
+		return errors.Wrap(err, "explain param")
+	}
+
+	explanation := paramExplanation{
+		Component: c.Name(true),
+		Key:       pe.rawPath,
+		Env:       pe.envName,
+		Layers:    layers,
+	}
+
+	return p.Print(explanation, func(w io.Writer) error {
+		fmt.Fprintf(w, "COMPONENT: %s\n", explanation.Component)
+		fmt.Fprintf(w, "KEY:       %s\n", explanation.Key)
+		if explanation.Env != "" {
+			fmt.Fprintf(w, "ENV:       %s\n", explanation.Env)
+		}
+		fmt.Fprintln(w)
+
+		table := ksutil.NewTable(w)
+
+		table.SetHeader([]string{"LAYER", "VALUE"})
+		for _, layer := range layers {
+			value := layer.Value
+			if !layer.IsSet {
+				value = "(not set)"
+			}
+			table.Append([]string{layer.Source, value})
+		}
+
+		table.Render()
+		return nil
+	})
+}
+
+// resolve returns the namespace's params with all layers applied.
+func (pe *paramExplain) resolve(ns component.Namespace) (string, error) {
+	if pe.envName == "" {
+		resolved, err := ns.ResolvedParams()
+		if err != nil {
+			return "", errors.Wrap(err, "resolve params")
+		}
+
+		return resolved, nil
+	}
+
+	p := pipeline.New(pe.app, pe.envName)
+	resolved, err := p.EnvParameters(ns.Name())
+	if err != nil {
+		return "", errors.Wrapf(err, "resolve params for environment %q", pe.envName)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,93 @@
+package action
+
+import (
+	"io"
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ParamExport exports the params for a namespace with flattened keys.
+func ParamExport(fs afero.Fs, nsName string, opts ...ParamExportOpt) error {
+	pe, err := newParamExport(fs, nsName, opts...)
+	if err != nil {
+		return err
+	}
+
+	return pe.run()
+}
+
+// ParamExportOpt is an option for configuring ParamExport.
+type ParamExportOpt func(*paramExport)
+
+// ParamExportWithEnv exports an environment's overrides instead of a
+// namespace's params.
+func ParamExportWithEnv(envName string) ParamExportOpt {
+	return func(pe *paramExport) {
+		pe.envName = envName
+	}
+}
+
+// ParamExportWithFormat sets the format params are exported in. It defaults
+// to JSON.
+func ParamExportWithFormat(format string) ParamExportOpt {
+	return func(pe *paramExport) {
+		pe.format = format
+	}
+}
+
+type paramExport struct {
+	nsName  string
+	envName string
+	format  string
+	out     io.Writer
+
+	*base
+}
+
+func newParamExport(fs afero.Fs, nsName string, opts ...ParamExportOpt) (*paramExport, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	pe := &paramExport{
+		nsName: nsName,
+		format: params.FlatFormatJSON,
+		out:    os.Stdout,
+		base:   b,
+	}
+
+	for _, opt := range opts {
+		opt(pe)
+	}
+
+	return pe, nil
+}
+
+func (pe *paramExport) run() error {
+	var values map[string]interface{}
+
+	if pe.envName != "" {
+		var err error
+		values, err = component.ExportEnvParams(pe.app, pe.envName)
+		if err != nil {
+			return errors.Wrapf(err, "export params for environment %q", pe.envName)
+		}
+	} else {
+		ns, err := component.GetNamespace(pe.app, pe.nsName)
+		if err != nil {
+			return errors.Wrap(err, "could not find namespace")
+		}
+
+		values, err = ns.ExportParams()
+		if err != nil {
+			return errors.Wrap(err, "export params")
+		}
+	}
+
+	return params.WriteFlat(pe.out, pe.format, values)
+}
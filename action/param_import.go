@@ -0,0 +1,151 @@
+package action
+
+import (
+	"io"
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ParamImport imports params for a namespace from a file with flattened keys.
+// Params missing from the file are removed.
+func ParamImport(fs afero.Fs, nsName, path string, opts ...ParamImportOpt) error {
+	pi, err := newParamImport(fs, nsName, path, opts...)
+	if err != nil {
+		return err
+	}
+
+	return pi.run()
+}
+
+// ParamImportOpt is an option for configuring ParamImport.
+type ParamImportOpt func(*paramImport)
+
+// ParamImportWithEnv imports an environment's overrides instead of a
+// namespace's params.
+func ParamImportWithEnv(envName string) ParamImportOpt {
+	return func(pi *paramImport) {
+		pi.envName = envName
+	}
+}
+
+// ParamImportWithFormat sets the format of the imported file. If it is blank,
+// the format is determined by the file's extension.
+func ParamImportWithFormat(format string) ParamImportOpt {
+	return func(pi *paramImport) {
+		pi.format = format
+	}
+}
+
+// ParamImportWithForce allows params computed from expressions to be replaced
+// with imported values.
+func ParamImportWithForce(force bool) ParamImportOpt {
+	return func(pi *paramImport) {
+		pi.force = force
+	}
+}
+
+type paramImport struct {
+	nsName  string
+	path    string
+	envName string
+	format  string
+	force   bool
+	out     io.Writer
+
+	*base
+}
+
+func newParamImport(fs afero.Fs, nsName, path string, opts ...ParamImportOpt) (*paramImport, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	pi := &paramImport{
+		nsName: nsName,
+		path:   path,
+		out:    os.Stdout,
+		base:   b,
+	}
+
+	for _, opt := range opts {
+		opt(pi)
+	}
+
+	return pi, nil
+}
+
+func (pi *paramImport) run() error {
+	values, err := pi.read()
+	if err != nil {
+		return err
+	}
+
+	options := component.ParamOptions{Force: pi.force}
+
+	var changes component.ParamChanges
+	if pi.envName != "" {
+		changes, err = component.ImportEnvParams(pi.app, pi.envName, values, options)
+		if err != nil {
+			return errors.Wrapf(err, "import params for environment %q", pi.envName)
+		}
+	} else {
+		ns, err := component.GetNamespace(pi.app, pi.nsName)
+		if err != nil {
+			return errors.Wrap(err, "could not find namespace")
+		}
+
+		changes, err = ns.ImportParams(values, options)
+		if err != nil {
+			return errors.Wrap(err, "import params")
+		}
+	}
+
+	if changes.Empty() {
+		_, err := io.WriteString(pi.out, "params are up to date\n")
+		return err
+	}
+
+	table := ksutil.NewTable(pi.out)
+	table.SetHeader([]string{"CHANGE", "KEY"})
+	for _, k := range changes.Added {
+		table.Append([]string{"added", k})
+	}
+	for _, k := range changes.Changed {
+		table.Append([]string{"changed", k})
+	}
+	for _, k := range changes.Removed {
+		table.Append([]string{"removed", k})
+	}
+	table.Render()
+
+	return nil
+}
+
+func (pi *paramImport) read() (map[string]interface{}, error) {
+	format := pi.format
+	if format == "" {
+		var err error
+		if format, err = params.FlatFormatFromPath(pi.path); err != nil {
+			return nil, errors.Wrap(err, "use --format to set it")
+		}
+	}
+
+	f, err := pi.app.Fs().Open(pi.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values, err := params.ReadFlat(f, format)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s", pi.path)
+	}
+
+	return values, nil
+}
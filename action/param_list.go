@@ -1,16 +1,24 @@
 package action
 
 import (
+	"io"
 	"os"
 
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pipeline"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
 
-func ParamList(fs afero.Fs, nsName string) error {
-	pl, err := newParamList(fs, nsName)
+var (
+	paramListHeader    = []string{"COMPONENT", "INDEX", "KEY", "VALUE"}
+	paramListEnvHeader = []string{"COMPONENT", "INDEX", "KEY", "VALUE", "SOURCE"}
+)
+
+// ParamList lists parameters for a namespace.
+func ParamList(fs afero.Fs, nsName string, opts ...ParamListOpt) error {
+	pl, err := newParamList(fs, nsName, opts...)
 	if err != nil {
 		return err
 	}
@@ -18,13 +26,41 @@ func ParamList(fs afero.Fs, nsName string) error {
 	return pl.run()
 }
 
+// ParamListOpt is an option for configuring ParamList.
+type ParamListOpt func(*paramList)
+
+// ParamListWithOutput sets the output format.
+func ParamListWithOutput(output string) ParamListOpt {
+	return func(pl *paramList) {
+		pl.output = output
+	}
+}
+
+// ParamListWithSortBy sorts params by a column.
+func ParamListWithSortBy(column string) ParamListOpt {
+	return func(pl *paramList) {
+		pl.sortBy = column
+	}
+}
+
+// ParamListWithEnv lists params as they are resolved for an environment.
+func ParamListWithEnv(envName string) ParamListOpt {
+	return func(pl *paramList) {
+		pl.envName = envName
+	}
+}
+
 type paramList struct {
-	nsName string
+	nsName  string
+	envName string
+	output  string
+	sortBy  string
+	out     io.Writer
 
 	*base
 }
 
-func newParamList(fs afero.Fs, nsName string) (*paramList, error) {
+func newParamList(fs afero.Fs, nsName string, opts ...ParamListOpt) (*paramList, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
@@ -32,31 +68,123 @@ func newParamList(fs afero.Fs, nsName string) (*paramList, error) {
 
 	pl := &paramList{
 		nsName: nsName,
+		out:    os.Stdout,
 		base:   b,
 	}
 
+	for _, opt := range opts {
+		opt(pl)
+	}
+
 	return pl, nil
 }
 
 func (pl *paramList) run() error {
+	p, err := ksutil.NewPrinter(pl.out, pl.output)
+	if err != nil {
+		return err
+	}
+
 	ns, err := component.GetNamespace(pl.app, pl.nsName)
 	if err != nil {
 		return errors.Wrap(err, "could not find namespace")
 	}
 
+	if pl.envName != "" {
+		return pl.runEnv(p, ns)
+	}
+
 	paramData, err := ns.Params()
 	if err != nil {
 		return errors.Wrap(err, "could not list parameters")
 	}
 
-	table := ksutil.NewTable(os.Stdout)
+	return pl.print(p, paramData, paramListHeader, func(data component.NamespaceParameter) []string {
+		return []string{data.Component, data.Index, data.Key, displayValue(data)}
+	})
+}
+
+// runEnv lists the effective params for the namespace in an environment along
+// with where each value came from.
+func (pl *paramList) runEnv(p *ksutil.Printer, ns component.Namespace) error {
+	resolved, err := pipeline.New(pl.app, pl.envName).EnvParameters(pl.nsName)
+	if err != nil {
+		return errors.Wrapf(err, "resolve params for environment %q", pl.envName)
+	}
+
+	paramData, err := ns.EnvParams(pl.envName, resolved)
+	if err != nil {
+		return errors.Wrap(err, "could not list parameters")
+	}
+
+	return pl.print(p, paramData, paramListEnvHeader, func(data component.NamespaceParameter) []string {
+		return []string{data.Component, data.Index, data.Key, displayValue(data), data.Source}
+	})
+}
+
+// print prints params. Params are sorted by component, index and key unless
+// the list is sorted by another column.
+func (pl *paramList) print(p *ksutil.Printer, paramData []component.NamespaceParameter, header []string, columns func(component.NamespaceParameter) []string) error {
+	if paramData == nil {
+		paramData = make([]component.NamespaceParameter, 0)
+	}
 
-	table.SetHeader([]string{"COMPONENT", "INDEX", "KEY", "VALUE"})
-	for _, data := range paramData {
-		table.Append([]string{data.Component, data.Index, data.Key, data.Value})
+	row := func(i int) []string {
+		return columns(paramData[i])
+	}
+
+	if err := ksutil.SortByColumn(paramData, header, pl.sortBy, row); err != nil {
+		return err
 	}
 
-	table.Render()
+	return p.Print(paramListItems(paramData), func(w io.Writer) error {
+		table := ksutil.NewTable(w)
+
+		table.SetHeader(header)
+		for i := range paramData {
+			table.Append(row(i))
+		}
+
+		table.Render()
+		return nil
+	})
+}
+
+// paramListItem is a param in machine readable output. Its value isn't
+// formatted for display, so strings aren't quoted.
+type paramListItem struct {
+	Component string      `json:"component"`
+	Index     string      `json:"index"`
+	Key       string      `json:"key"`
+	Value     interface{} `json:"value"`
+	Source    string      `json:"source,omitempty"`
+	Computed  bool        `json:"computed,omitempty"`
+	Secret    bool        `json:"secret,omitempty"`
+}
+
+func paramListItems(paramData []component.NamespaceParameter) []paramListItem {
+	items := make([]paramListItem, 0, len(paramData))
+	for _, p := range paramData {
+		items = append(items, paramListItem{
+			Component: p.Component,
+			Index:     p.Index,
+			Key:       p.Key,
+			Value:     p.RawValue,
+			Source:    p.Source,
+			Computed:  p.Computed,
+			Secret:    p.Secret,
+		})
+	}
+
+	return items
+}
+
+// displayValue formats a param's value for display. Values set by expressions
+// are marked as computed.
+func displayValue(p component.NamespaceParameter) string {
+	if p.Computed {
+		return p.Value + " (computed)"
+	}
 
-	return nil
+	return p.Value
 }
@@ -1,8 +1,6 @@
 package action
 
 import (
-	"strings"
-
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/params"
 	"github.com/pkg/errors"
@@ -28,6 +26,29 @@ func ParamSetGlobal(isGlobal bool) ParamSetOpt {
 	}
 }
 
+// ParamSetWithEnv sets the environment the param is set in. The param is
+// written to the environment's params instead of the component's namespace.
+func ParamSetWithEnv(envName string) ParamSetOpt {
+	return func(paramSet *paramSet) {
+		paramSet.envName = envName
+	}
+}
+
+// ParamSetWithForce allows params computed from expressions to be replaced
+// with the new value.
+func ParamSetWithForce(force bool) ParamSetOpt {
+	return func(paramSet *paramSet) {
+		paramSet.force = force
+	}
+}
+
+// ParamSetSecret stores the param encrypted in the namespace's secrets.
+func ParamSetSecret(isSecret bool) ParamSetOpt {
+	return func(paramSet *paramSet) {
+		paramSet.secret = isSecret
+	}
+}
+
 // ParamSetWithIndex sets the index for the set option.
 func ParamSetWithIndex(index int) ParamSetOpt {
 	return func(paramSet *paramSet) {
@@ -42,6 +63,9 @@ type paramSet struct {
 	rawValue string
 	index    int
 	global   bool
+	envName  string
+	force    bool
+	secret   bool
 
 	*base
 }
@@ -69,45 +93,153 @@ func newParamSet(fs afero.Fs, name, path, value string, opts ...ParamSetOpt) (*p
 
 // Run runs the action.
 func (ps *paramSet) Run() error {
-	path := strings.Split(ps.rawPath, ".")
-
-	value, err := params.DecodeValue(ps.rawValue)
+	path, err := params.ParsePath(ps.rawPath)
 	if err != nil {
-		return errors.Wrap(err, "value is invalid")
+		return err
+	}
+
+	if ps.secret {
+		if ps.global || ps.envName != "" {
+			return errors.New("secret params can only be set for a component")
+		}
+
+		return ps.setSecret(path)
 	}
 
 	if ps.global {
-		return ps.setGlobal(path, value)
+		if ps.envName != "" {
+			return errors.New("global params can't be set for an environment")
+		}
+
+		return ps.setGlobal(path)
+	}
+
+	if ps.envName != "" {
+		return ps.setEnv(path)
 	}
 
-	return ps.setLocal(path, value)
+	return ps.setLocal(path)
 }
 
-func (ps *paramSet) setGlobal(path []string, value interface{}) error {
+// decodeValue decodes the raw value. If the namespace has a params schema, the
+// value is converted to the type the schema declares for it.
+func (ps *paramSet) decodeValue(ns component.Namespace, schemaPath []string) (interface{}, error) {
+	schema, err := ns.Schema()
+	if err != nil {
+		return nil, errors.Wrap(err, "read params schema")
+	}
+
+	value, err := schema.DecodeValue(schemaPath, ps.rawValue)
+	if err != nil {
+		return nil, errors.Wrap(err, "value is invalid")
+	}
+
+	return value, nil
+}
+
+func (ps *paramSet) setGlobal(path []string) error {
 	ns, err := component.GetNamespace(ps.app, ps.name)
 	if err != nil {
 		return errors.Wrap(err, "retrieve namespace")
 	}
 
-	if err := ns.SetParam(path, value); err != nil {
-		return errors.Wrap(err, "set global param")
+	value, err := ps.decodeValue(ns, append([]string{"global"}, path...))
+	if err != nil {
+		return err
+	}
+
+	options := component.ParamOptions{
+		Force: ps.force,
+	}
+	if err := ns.SetParam(path, value, options); err != nil {
+		return errors.Wrap(computedHint(err), "set global param")
 	}
 
 	return nil
 }
 
-func (ps *paramSet) setLocal(path []string, value interface{}) error {
-	c, err := component.ExtractComponent(ps.app, ps.name)
+func (ps *paramSet) setLocal(path []string) error {
+	c, value, err := ps.componentValue(path)
 	if err != nil {
-		return errors.Wrap(err, "could not find component")
+		return err
 	}
 
 	options := component.ParamOptions{
 		Index: ps.index,
+		Force: ps.force,
 	}
 	if err := c.SetParam(path, value, options); err != nil {
-		return errors.Wrap(err, "set param")
+		return errors.Wrap(computedHint(err), "set param")
 	}
 
 	return nil
 }
+
+func (ps *paramSet) setEnv(path []string) error {
+	c, value, err := ps.componentValue(path)
+	if err != nil {
+		return err
+	}
+
+	options := component.ParamOptions{
+		Index: ps.index,
+		Force: ps.force,
+	}
+	if err := component.SetEnvParam(ps.app, ps.envName, c, path, value, options); err != nil {
+		return errors.Wrap(computedHint(err), "set environment param")
+	}
+
+	return nil
+}
+
+func (ps *paramSet) setSecret(path []string) error {
+	c, value, err := ps.componentValue(path)
+	if err != nil {
+		return err
+	}
+
+	ns, err := component.GetComponentNamespace(ps.app, ps.name)
+	if err != nil {
+		return errors.Wrap(err, "retrieve namespace")
+	}
+
+	options := component.ParamOptions{
+		Index: ps.index,
+	}
+	if err := ns.SetSecretParam(c, path, value, options); err != nil {
+		return errors.Wrap(err, "set secret param")
+	}
+
+	return nil
+}
+
+// componentValue finds the component being updated and decodes the value
+// using the schema for the component's params.
+func (ps *paramSet) componentValue(path []string) (component.Component, interface{}, error) {
+	c, err := component.ExtractComponent(ps.app, ps.name)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find component")
+	}
+
+	ns, err := component.GetComponentNamespace(ps.app, ps.name)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "retrieve namespace")
+	}
+
+	key := component.ParamsKey(c, component.ParamOptions{Index: ps.index})
+	value, err := ps.decodeValue(ns, append([]string{"components", key}, path...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c, value, nil
+}
+
+// computedHint explains how to replace a computed param.
+func computedHint(err error) error {
+	if _, ok := errors.Cause(err).(*params.ComputedError); ok {
+		return errors.Errorf("%v; use --force to replace the expression with a literal value", err)
+	}
+
+	return err
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/bryanl/woowoo/action"
+	"github.com/bryanl/woowoo/k8sutil"
 	"github.com/bryanl/woowoo/pkg/client"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -13,6 +14,18 @@ const (
 	vApplyDryRun = "apply-dru-run"
 	vApplyGcTag  = "apply-gc-tag"
 	vApplySkipGc = "apply-skip-gc"
+
+	vApplyStrategy       = "apply-strategy"
+	vApplyFieldManager   = "apply-field-manager"
+	vApplyForceConflicts = "apply-force-conflicts"
+	vApplyWait           = "apply-wait"
+	vApplyWaitTimeout    = "apply-wait-timeout"
+	vApplyRollback       = "apply-rollback-on-failure"
+	vApplyRetries        = "apply-retries"
+	vApplyRetryBackoff   = "apply-retry-backoff"
+	vApplyParallelism    = "apply-parallelism"
+	vApplyInventory      = "apply-inventory"
+	vApplyOutput         = "apply-output"
 )
 
 var (
@@ -31,12 +44,43 @@ var applyCmd = &cobra.Command{
 
 		env := args[0]
 
+		strategy := client.ApplyStrategy(viper.GetString(vApplyStrategy))
+		switch strategy {
+		case client.ApplyStrategyMerge, client.ApplyStrategyStrategic, client.ApplyStrategyServerSide:
+		default:
+			return errors.Errorf("invalid apply strategy %q", strategy)
+		}
+
+		plan := viper.GetString(vApplyOutput)
+		switch plan {
+		case "", "json", "yaml":
+		default:
+			return errors.Errorf("invalid output format %q", plan)
+		}
+
 		options := client.ApplyOptions{
 			Create: viper.GetBool(vApplyCreate),
 			SkipGc: viper.GetBool(vApplySkipGc),
 			GcTag:  viper.GetString(vApplyGcTag),
 			DryRun: viper.GetBool(vApplyDryRun),
 			Client: applyClientConfig,
+
+			Strategy:       strategy,
+			FieldManager:   viper.GetString(vApplyFieldManager),
+			ForceConflicts: viper.GetBool(vApplyForceConflicts),
+
+			Wait:        viper.GetBool(vApplyWait),
+			WaitTimeout: viper.GetDuration(vApplyWaitTimeout),
+
+			RollbackOnFailure: viper.GetBool(vApplyRollback),
+
+			Retry: client.RetryOptions{
+				Retries: viper.GetInt(vApplyRetries),
+				Backoff: viper.GetDuration(vApplyRetryBackoff),
+			},
+			Parallelism: viper.GetInt(vApplyParallelism),
+			Inventory:   viper.GetBool(vApplyInventory),
+			Plan:        plan,
 		}
 
 		return action.Apply(fs, env, options)
@@ -60,4 +104,37 @@ func init() {
 
 	applyCmd.Flags().Bool(flagDryRun, false, "Option to preview the list of operations without changing the cluster state")
 	viper.BindPFlag(vApplyDryRun, applyCmd.Flags().Lookup(flagDryRun))
+
+	applyCmd.Flags().String(flagStrategy, string(client.ApplyStrategyMerge), "Strategy used to update objects (merge, strategic, server-side)")
+	viper.BindPFlag(vApplyStrategy, applyCmd.Flags().Lookup(flagStrategy))
+
+	applyCmd.Flags().String(flagFieldManager, client.DefaultFieldManager, "Field manager used for server-side apply")
+	viper.BindPFlag(vApplyFieldManager, applyCmd.Flags().Lookup(flagFieldManager))
+
+	applyCmd.Flags().Bool(flagForceConflicts, false, "Take ownership of conflicting fields during server-side apply")
+	viper.BindPFlag(vApplyForceConflicts, applyCmd.Flags().Lookup(flagForceConflicts))
+
+	applyCmd.Flags().Bool(flagWait, false, "Wait for applied objects to become ready")
+	viper.BindPFlag(vApplyWait, applyCmd.Flags().Lookup(flagWait))
+
+	applyCmd.Flags().Duration(flagWaitTimeout, k8sutil.DefaultWaitTimeout, "Maximum time to wait for applied objects to become ready")
+	viper.BindPFlag(vApplyWaitTimeout, applyCmd.Flags().Lookup(flagWaitTimeout))
+
+	applyCmd.Flags().Bool(flagRollbackOnFailure, false, "Revert applied objects if applying or waiting for readiness fails")
+	viper.BindPFlag(vApplyRollback, applyCmd.Flags().Lookup(flagRollbackOnFailure))
+
+	applyCmd.Flags().Int(flagRetries, client.DefaultRetries, "Number of times to retry requests which fail with transient errors or conflicts")
+	viper.BindPFlag(vApplyRetries, applyCmd.Flags().Lookup(flagRetries))
+
+	applyCmd.Flags().Duration(flagRetryBackoff, client.DefaultRetryBackoff, "Delay before the first retry. The delay doubles after each retry")
+	viper.BindPFlag(vApplyRetryBackoff, applyCmd.Flags().Lookup(flagRetryBackoff))
+
+	applyCmd.Flags().Int(flagParallelism, k8sutil.DefaultParallelism, "Number of objects in a dependency wave to apply concurrently")
+	viper.BindPFlag(vApplyParallelism, applyCmd.Flags().Lookup(flagParallelism))
+
+	applyCmd.Flags().Bool(flagInventory, false, "Record applied objects in an inventory ConfigMap and use it for garbage collection")
+	viper.BindPFlag(vApplyInventory, applyCmd.Flags().Lookup(flagInventory))
+
+	applyCmd.Flags().StringP(flagOutput, "o", "", "Print a plan of the changes instead of applying them. Valid options: json, yaml")
+	viper.BindPFlag(vApplyOutput, applyCmd.Flags().Lookup(flagOutput))
 }
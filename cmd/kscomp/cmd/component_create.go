@@ -0,0 +1,61 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vComponentCreateNamespace  = "component-create-ns"
+	vComponentCreateAPIVersion = "component-create-api-version"
+	vComponentCreateKind       = "component-create-kind"
+)
+
+var componentCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "component create",
+	Long:  "component create",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("component create <name>")
+		}
+
+		nsName := viper.GetString(vComponentCreateNamespace)
+		apiVersion := viper.GetString(vComponentCreateAPIVersion)
+		kind := viper.GetString(vComponentCreateKind)
+		if apiVersion == "" || kind == "" {
+			return errors.New("--api-version and --kind are required")
+		}
+
+		return action.ComponentCreate(fs, nsName, args[0], apiVersion, kind)
+	},
+}
+
+func init() {
+	componentCmd.AddCommand(componentCreateCmd)
+
+	componentCreateCmd.Flags().String(flagNamespace, "", "Component namespace")
+	viper.BindPFlag(vComponentCreateNamespace, componentCreateCmd.Flags().Lookup(flagNamespace))
+
+	componentCreateCmd.Flags().String(flagAPIVersion, "", "API version of the object, e.g. apps/v1beta2")
+	viper.BindPFlag(vComponentCreateAPIVersion, componentCreateCmd.Flags().Lookup(flagAPIVersion))
+
+	componentCreateCmd.Flags().String(flagKind, "", "Kind of the object, e.g. Deployment")
+	viper.BindPFlag(vComponentCreateKind, componentCreateCmd.Flags().Lookup(flagKind))
+}
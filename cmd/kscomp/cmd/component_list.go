@@ -9,6 +9,7 @@ import (
 const (
 	vComponentListNamespace = "component-list-ns"
 	vComponentListOutput    = "component-list-output"
+	vComponentListSortBy    = "component-list-sort-by"
 )
 
 var componentListCmd = &cobra.Command{
@@ -18,7 +19,8 @@ var componentListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		namespace := viper.GetString(vComponentListNamespace)
 		output := viper.GetString(vComponentListOutput)
-		return action.ComponentList(fs, namespace, output)
+		sortBy := viper.GetString(vComponentListSortBy)
+		return action.ComponentList(fs, namespace, output, sortBy)
 	},
 }
 
@@ -28,6 +30,9 @@ func init() {
 	componentListCmd.Flags().String(flagNamespace, "", "Component namespace")
 	viper.BindPFlag(vComponentListNamespace, componentListCmd.Flags().Lookup(flagNamespace))
 
-	componentListCmd.Flags().StringP(flagOutput, "o", "", "Output format. Valid options: wide")
+	componentListCmd.Flags().StringP(flagOutput, "o", "", outputUsage+", wide")
 	viper.BindPFlag(vComponentListOutput, componentListCmd.Flags().Lookup(flagOutput))
+
+	componentListCmd.Flags().String(flagSortBy, "", "Column to sort by")
+	viper.BindPFlag(vComponentListSortBy, componentListCmd.Flags().Lookup(flagSortBy))
 }
@@ -0,0 +1,40 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var componentMoveCmd = &cobra.Command{
+	Use:   "mv <from> <to>",
+	Short: "component mv",
+	Long: `component mv moves a component to another namespace and/or renames it.
+If <to> ends with a slash, the component is moved to that namespace and keeps
+its name. Params, secrets and environment overrides move with the component.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("component mv <from> <to>")
+		}
+
+		return action.ComponentMove(fs, args[0], args[1])
+	},
+}
+
+func init() {
+	componentCmd.AddCommand(componentMoveCmd)
+}
@@ -0,0 +1,56 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vComponentRemoveDryRun  = "component-rm-dry-run"
+	vComponentRemoveNoColor = "component-rm-no-color"
+)
+
+var componentRemoveCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "component rm",
+	Long: `component rm removes a component's source along with its params, secrets
+and environment overrides.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("component rm <name>")
+		}
+
+		useColor := !viper.GetBool(vComponentRemoveNoColor) && !color.NoColor
+
+		return action.ComponentRemove(fs, args[0],
+			action.ComponentRemoveWithDryRun(viper.GetBool(vComponentRemoveDryRun)),
+			action.ComponentRemoveWithColor(useColor))
+	},
+}
+
+func init() {
+	componentCmd.AddCommand(componentRemoveCmd)
+
+	componentRemoveCmd.Flags().Bool(flagDryRun, false, "Show the changes to params without removing the component")
+	viper.BindPFlag(vComponentRemoveDryRun, componentRemoveCmd.Flags().Lookup(flagDryRun))
+
+	componentRemoveCmd.Flags().Bool(flagNoColor, false, "Disable colored output")
+	viper.BindPFlag(vComponentRemoveNoColor, componentRemoveCmd.Flags().Lookup(flagNoColor))
+}
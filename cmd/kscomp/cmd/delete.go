@@ -9,7 +9,9 @@ import (
 )
 
 const (
-	vDeleteGracePeriod = "delete-grace-period"
+	vDeleteGracePeriod  = "delete-grace-period"
+	vDeleteRetries      = "delete-retries"
+	vDeleteRetryBackoff = "delete-retry-backoff"
 )
 
 var (
@@ -33,6 +35,10 @@ var deleteCmd = &cobra.Command{
 		options := client.DeleteOptions{
 			GracePeriod: gracePeriod,
 			Client:      deleteClientConfig,
+			Retry: client.RetryOptions{
+				Retries: viper.GetInt(vDeleteRetries),
+				Backoff: viper.GetDuration(vDeleteRetryBackoff),
+			},
 		}
 
 		return action.Delete(fs, env, options, action.DeleteWithComponents(components...))
@@ -47,4 +53,10 @@ func init() {
 
 	deleteCmd.Flags().Int64(flagGracePeriod, -1, "Number of seconds given to resources to terminate gracefully. A negative value is ignored")
 	viper.BindPFlag(vDeleteGracePeriod, deleteCmd.Flags().Lookup(flagGracePeriod))
+
+	deleteCmd.Flags().Int(flagRetries, client.DefaultRetries, "Number of times to retry requests which fail with transient errors or conflicts")
+	viper.BindPFlag(vDeleteRetries, deleteCmd.Flags().Lookup(flagRetries))
+
+	deleteCmd.Flags().Duration(flagRetryBackoff, client.DefaultRetryBackoff, "Delay before the first retry. The delay doubles after each retry")
+	viper.BindPFlag(vDeleteRetryBackoff, deleteCmd.Flags().Lookup(flagRetryBackoff))
 }
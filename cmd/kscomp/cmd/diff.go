@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/bryanl/woowoo/pkg/client"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vDiffComponent = "diff-component"
+	vDiffNoColor   = "diff-no-color"
+	vDiffGcTag     = "diff-gc-tag"
+)
+
+var (
+	diffClientConfig *client.Config
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <environment>",
+	Short: "diff an environment against the live cluster state",
+	Long: `diff an environment against the live cluster state. Exits with a non-zero
+status if the cluster differs from the environment.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("diff <environment>")
+		}
+
+		env := args[0]
+		components := viper.GetStringSlice(vDiffComponent)
+		useColor := !viper.GetBool(vDiffNoColor) && !color.NoColor
+
+		return action.Diff(fs, env, diffClientConfig,
+			action.DiffWithComponents(components...),
+			action.DiffWithColor(useColor),
+			action.DiffWithGcTag(viper.GetString(vDiffGcTag)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffClientConfig = client.NewDefaultClientConfig()
+	diffClientConfig.BindClientGoFlags(diffCmd)
+
+	diffCmd.Flags().StringSliceP(flagComponent, "c", nil, "Components to include")
+	viper.BindPFlag(vDiffComponent, diffCmd.Flags().Lookup(flagComponent))
+
+	diffCmd.Flags().Bool(flagNoColor, false, "Disable colored output")
+	viper.BindPFlag(vDiffNoColor, diffCmd.Flags().Lookup(flagNoColor))
+
+	diffCmd.Flags().String(flagGcTag, "", "The gc tag the environment is applied with")
+	viper.BindPFlag(vDiffGcTag, diffCmd.Flags().Lookup(flagGcTag))
+}
@@ -4,6 +4,11 @@ import (
 	"github.com/bryanl/woowoo/action"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vEnvDescribeOutput = "env-describe-output"
 )
 
 // envDescribeCmd represents the env describe command
@@ -18,10 +23,13 @@ var envDescribeCmd = &cobra.Command{
 
 		environment := args[0]
 
-		return action.EnvDescribe(fs, environment)
+		return action.EnvDescribe(fs, environment, viper.GetString(vEnvDescribeOutput))
 	},
 }
 
 func init() {
 	envCmd.AddCommand(envDescribeCmd)
+
+	envDescribeCmd.Flags().StringP(flagOutput, "o", "", outputUsage)
+	viper.BindPFlag(vEnvDescribeOutput, envDescribeCmd.Flags().Lookup(flagOutput))
 }
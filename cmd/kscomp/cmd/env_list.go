@@ -3,6 +3,12 @@ package cmd
 import (
 	"github.com/bryanl/woowoo/action"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vEnvListOutput = "env-list-output"
+	vEnvListSortBy = "env-list-sort-by"
 )
 
 // envListCmd represents the env list command
@@ -11,10 +17,16 @@ var envListCmd = &cobra.Command{
 	Short: "list",
 	Long:  `list`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return action.EnvList(fs)
+		return action.EnvList(fs, viper.GetString(vEnvListOutput), viper.GetString(vEnvListSortBy))
 	},
 }
 
 func init() {
 	envCmd.AddCommand(envListCmd)
+
+	envListCmd.Flags().StringP(flagOutput, "o", "", outputUsage)
+	viper.BindPFlag(vEnvListOutput, envListCmd.Flags().Lookup(flagOutput))
+
+	envListCmd.Flags().String(flagSortBy, "", "Column to sort by")
+	viper.BindPFlag(vEnvListSortBy, envListCmd.Flags().Lookup(flagSortBy))
 }
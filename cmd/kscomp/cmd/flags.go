@@ -1,13 +1,41 @@
 package cmd
 
+const (
+	// outputUsage describes the output formats supported by list and
+	// describe commands.
+	outputUsage = "Output format. Valid options: json, yaml, jsonpath=<template>, go-template=<template>"
+)
+
 var (
-	flagEnv       = "env"
-	flagFilename  = "f"
-	flagComponent = "component"
-	flagIndex     = "index"
-	flagNamespace = "ns"
-	flagOutput    = "output"
-	flagVerbose   = "verbose"
+	flagAPIVersion = "api-version"
+	flagEnv        = "env"
+	flagFilename   = "f"
+	flagFix        = "fix"
+	flagComponent  = "component"
+	flagForce      = "force"
+	flagFormat     = "format"
+	flagIndex      = "index"
+	flagKind       = "kind"
+	flagNamespace  = "ns"
+	flagOutput     = "output"
+	flagSecret     = "secret"
+	flagSecretKey  = "secret-key-file"
+	flagSortBy     = "sort-by"
+	flagTree       = "tree"
+	flagVerbose    = "verbose"
+	flagNoColor    = "no-color"
+
+	flagStrategy       = "strategy"
+	flagFieldManager   = "field-manager"
+	flagForceConflicts = "force-conflicts"
+	flagWait           = "wait"
+	flagWaitTimeout    = "wait-timeout"
+
+	flagRollbackOnFailure = "rollback-on-failure"
+	flagRetries           = "retries"
+	flagRetryBackoff      = "retry-backoff"
+	flagParallelism       = "parallelism"
+	flagInventory         = "inventory"
 
 	// these are on loan from the ksonnet app
 	flagGracePeriod = "grace-period"
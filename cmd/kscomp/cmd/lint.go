@@ -0,0 +1,42 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vLintFix = "lint-fix"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "lint component params",
+	Long: `lint reports params which don't belong to a component, YAML params for
+documents which don't exist, and components in a namespace which share a name.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return action.Lint(fs, action.LintWithFix(viper.GetBool(vLintFix)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().Bool(flagFix, false, "Remove orphaned params and params for missing YAML documents")
+	viper.BindPFlag(vLintFix, lintCmd.Flags().Lookup(flagFix))
+}
@@ -3,6 +3,13 @@ package cmd
 import (
 	"github.com/bryanl/woowoo/action"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vNsListOutput = "ns-list-output"
+	vNsListSortBy = "ns-list-sort-by"
+	vNsListTree   = "ns-list-tree"
 )
 
 // nsListCmd represents the ns list command
@@ -11,10 +18,19 @@ var nsListCmd = &cobra.Command{
 	Short: "list",
 	Long:  `list`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return action.NsList(fs)
+		return action.NsList(fs, viper.GetString(vNsListOutput), viper.GetString(vNsListSortBy), viper.GetBool(vNsListTree))
 	},
 }
 
 func init() {
 	nsCmd.AddCommand(nsListCmd)
+
+	nsListCmd.Flags().StringP(flagOutput, "o", "", outputUsage)
+	viper.BindPFlag(vNsListOutput, nsListCmd.Flags().Lookup(flagOutput))
+
+	nsListCmd.Flags().String(flagSortBy, "", "Column to sort by")
+	viper.BindPFlag(vNsListSortBy, nsListCmd.Flags().Lookup(flagSortBy))
+
+	nsListCmd.Flags().Bool(flagTree, false, "Show nested namespaces with component counts")
+	viper.BindPFlag(vNsListTree, nsListCmd.Flags().Lookup(flagTree))
 }
@@ -0,0 +1,47 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vNsRemoveForce = "ns-rm-force"
+)
+
+var nsRemoveCmd = &cobra.Command{
+	Use:   "rm <namespace>",
+	Short: "ns rm",
+	Long: `ns rm removes a namespace along with its components and nested namespaces.
+Namespaces targeted by environments are only removed with --force.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("ns rm <namespace>")
+		}
+
+		return action.NsRemove(fs, args[0], viper.GetBool(vNsRemoveForce))
+	},
+}
+
+func init() {
+	nsCmd.AddCommand(nsRemoveCmd)
+
+	nsRemoveCmd.Flags().Bool(flagForce, false, "Remove the namespace from environment targets which refer to it")
+	viper.BindPFlag(vNsRemoveForce, nsRemoveCmd.Flags().Lookup(flagForce))
+}
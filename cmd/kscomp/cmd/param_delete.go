@@ -23,6 +23,7 @@ import (
 
 const (
 	vParamDeleteIndex = "param-delete-index"
+	vParamDeleteEnv   = "param-delete-env"
 )
 
 // deleteCmd represents the delete command
@@ -36,7 +37,8 @@ var paramDeleteCmd = &cobra.Command{
 		}
 
 		indexOpt := action.ParamDeleteWithIndex(viper.GetInt(vParamDeleteIndex))
-		return action.ParamDelete(fs, args[0], args[1], indexOpt)
+		envOpt := action.ParamDeleteWithEnv(viper.GetString(vParamDeleteEnv))
+		return action.ParamDelete(fs, args[0], args[1], indexOpt, envOpt)
 	},
 }
 
@@ -45,4 +47,7 @@ func init() {
 
 	paramDeleteCmd.Flags().IntP(flagIndex, "i", 0, "Index in manifest")
 	viper.BindPFlag(vParamDeleteIndex, paramDeleteCmd.Flags().Lookup(flagIndex))
+
+	paramDeleteCmd.Flags().String(flagEnv, "", "Environment to delete the param from")
+	viper.BindPFlag(vParamDeleteEnv, paramDeleteCmd.Flags().Lookup(flagEnv))
 }
@@ -0,0 +1,60 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamExplainEnv    = "param-explain-env"
+	vParamExplainIndex  = "param-explain-index"
+	vParamExplainOutput = "param-explain-output"
+)
+
+// paramExplainCmd represents the param explain command
+var paramExplainCmd = &cobra.Command{
+	Use:   "explain <component-name> <param-key>",
+	Short: "Explain how a param's value is resolved",
+	Long: `Explain how a param's value is resolved. The value is shown for the
+component's params, namespace globals, environment overrides and the final
+result.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("explain <component-name> <param-key>")
+		}
+
+		envOpt := action.ParamExplainWithEnv(viper.GetString(vParamExplainEnv))
+		indexOpt := action.ParamExplainWithIndex(viper.GetInt(vParamExplainIndex))
+		outputOpt := action.ParamExplainWithOutput(viper.GetString(vParamExplainOutput))
+		return action.ParamExplain(fs, args[0], args[1], envOpt, indexOpt, outputOpt)
+	},
+}
+
+func init() {
+	paramCmd.AddCommand(paramExplainCmd)
+
+	paramExplainCmd.Flags().String(flagEnv, "", "Environment to resolve the param for")
+	viper.BindPFlag(vParamExplainEnv, paramExplainCmd.Flags().Lookup(flagEnv))
+
+	paramExplainCmd.Flags().IntP(flagIndex, "i", 0, "Index in manifest")
+	viper.BindPFlag(vParamExplainIndex, paramExplainCmd.Flags().Lookup(flagIndex))
+
+	paramExplainCmd.Flags().StringP(flagOutput, "o", "", outputUsage)
+	viper.BindPFlag(vParamExplainOutput, paramExplainCmd.Flags().Lookup(flagOutput))
+}
@@ -0,0 +1,58 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamExportNamespace = "param-export-ns"
+	vParamExportEnv       = "param-export-env"
+	vParamExportFormat    = "param-export-format"
+)
+
+// paramExportCmd represents the param export command
+var paramExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export params with flattened keys",
+	Long: `Export a namespace's params, or an environment's overrides, to stdout.
+Keys are flattened, e.g. components.guestbook-ui.ports[0].containerPort.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nsName := viper.GetString(vParamExportNamespace)
+
+		opts := []action.ParamExportOpt{
+			action.ParamExportWithEnv(viper.GetString(vParamExportEnv)),
+			action.ParamExportWithFormat(viper.GetString(vParamExportFormat)),
+		}
+
+		return action.ParamExport(fs, nsName, opts...)
+	},
+}
+
+func init() {
+	paramCmd.AddCommand(paramExportCmd)
+
+	paramExportCmd.Flags().String(flagNamespace, "", "Component namespace")
+	viper.BindPFlag(vParamExportNamespace, paramExportCmd.Flags().Lookup(flagNamespace))
+
+	paramExportCmd.Flags().String(flagEnv, "", "Environment to export overrides for")
+	viper.BindPFlag(vParamExportEnv, paramExportCmd.Flags().Lookup(flagEnv))
+
+	paramExportCmd.Flags().String(flagFormat, "json", "Output format (csv, json or dotenv)")
+	viper.BindPFlag(vParamExportFormat, paramExportCmd.Flags().Lookup(flagFormat))
+}
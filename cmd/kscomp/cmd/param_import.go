@@ -0,0 +1,73 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamImportFilename  = "param-import-filename"
+	vParamImportNamespace = "param-import-ns"
+	vParamImportEnv       = "param-import-env"
+	vParamImportFormat    = "param-import-format"
+	vParamImportForce     = "param-import-force"
+)
+
+// paramImportCmd represents the param import command
+var paramImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import params with flattened keys",
+	Long: `Import a namespace's params, or an environment's overrides, from a file
+written by param export. Params missing from the file are removed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileName := viper.GetString(vParamImportFilename)
+		if fileName == "" {
+			return errors.New("filename is required")
+		}
+
+		nsName := viper.GetString(vParamImportNamespace)
+
+		opts := []action.ParamImportOpt{
+			action.ParamImportWithEnv(viper.GetString(vParamImportEnv)),
+			action.ParamImportWithFormat(viper.GetString(vParamImportFormat)),
+			action.ParamImportWithForce(viper.GetBool(vParamImportForce)),
+		}
+
+		return action.ParamImport(fs, nsName, fileName, opts...)
+	},
+}
+
+func init() {
+	paramCmd.AddCommand(paramImportCmd)
+
+	paramImportCmd.Flags().StringP(flagFilename, "f", "", "File to import params from")
+	viper.BindPFlag(vParamImportFilename, paramImportCmd.Flags().Lookup(flagFilename))
+
+	paramImportCmd.Flags().String(flagNamespace, "", "Component namespace")
+	viper.BindPFlag(vParamImportNamespace, paramImportCmd.Flags().Lookup(flagNamespace))
+
+	paramImportCmd.Flags().String(flagEnv, "", "Environment to import overrides for")
+	viper.BindPFlag(vParamImportEnv, paramImportCmd.Flags().Lookup(flagEnv))
+
+	paramImportCmd.Flags().String(flagFormat, "", "File format (csv, json or dotenv); defaults to the file's extension")
+	viper.BindPFlag(vParamImportFormat, paramImportCmd.Flags().Lookup(flagFormat))
+
+	paramImportCmd.Flags().Bool(flagForce, false, "Replace params computed from expressions")
+	viper.BindPFlag(vParamImportForce, paramImportCmd.Flags().Lookup(flagForce))
+}
@@ -22,6 +22,9 @@ import (
 
 const (
 	vParamListNamespace = "param-list-ns"
+	vParamListEnv       = "param-list-env"
+	vParamListOutput    = "param-list-output"
+	vParamListSortBy    = "param-list-sort-by"
 )
 
 // listCmd represents the list command
@@ -31,7 +34,10 @@ var paramListCmd = &cobra.Command{
 	Long:  `param list`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		nsName := viper.GetString(vParamListNamespace)
-		return action.ParamList(fs, nsName)
+		envOpt := action.ParamListWithEnv(viper.GetString(vParamListEnv))
+		outputOpt := action.ParamListWithOutput(viper.GetString(vParamListOutput))
+		sortOpt := action.ParamListWithSortBy(viper.GetString(vParamListSortBy))
+		return action.ParamList(fs, nsName, envOpt, outputOpt, sortOpt)
 	},
 }
 
@@ -40,4 +46,13 @@ func init() {
 
 	paramListCmd.Flags().String(flagNamespace, "", "Component namespace")
 	viper.BindPFlag(vParamListNamespace, paramListCmd.Flags().Lookup(flagNamespace))
+
+	paramListCmd.Flags().String(flagEnv, "", "Environment to resolve params for")
+	viper.BindPFlag(vParamListEnv, paramListCmd.Flags().Lookup(flagEnv))
+
+	paramListCmd.Flags().StringP(flagOutput, "o", "", outputUsage)
+	viper.BindPFlag(vParamListOutput, paramListCmd.Flags().Lookup(flagOutput))
+
+	paramListCmd.Flags().String(flagSortBy, "", "Column to sort by")
+	viper.BindPFlag(vParamListSortBy, paramListCmd.Flags().Lookup(flagSortBy))
 }
@@ -22,7 +22,10 @@ import (
 )
 
 const (
-	vParamSetIndex = "param-set-index"
+	vParamSetIndex  = "param-set-index"
+	vParamSetEnv    = "param-set-env"
+	vParamSetForce  = "param-set-force"
+	vParamSetSecret = "param-set-secret"
 )
 
 // setCmd represents the set command
@@ -36,7 +39,10 @@ var paramSetCmd = &cobra.Command{
 		}
 
 		indexOpt := action.ParamSetWithIndex(viper.GetInt(vParamSetIndex))
-		return action.ParamSet(fs, args[0], args[1], args[2], indexOpt)
+		envOpt := action.ParamSetWithEnv(viper.GetString(vParamSetEnv))
+		forceOpt := action.ParamSetWithForce(viper.GetBool(vParamSetForce))
+		secretOpt := action.ParamSetSecret(viper.GetBool(vParamSetSecret))
+		return action.ParamSet(fs, args[0], args[1], args[2], indexOpt, envOpt, forceOpt, secretOpt)
 	},
 }
 
@@ -46,5 +52,12 @@ func init() {
 	paramSetCmd.Flags().IntP(flagIndex, "i", 0, "Index in manifest")
 	viper.BindPFlag(vParamSetIndex, paramSetCmd.Flags().Lookup(flagIndex))
 
-	// TODO: support env
+	paramSetCmd.Flags().String(flagEnv, "", "Environment to set the param in")
+	viper.BindPFlag(vParamSetEnv, paramSetCmd.Flags().Lookup(flagEnv))
+
+	paramSetCmd.Flags().Bool(flagForce, false, "Replace params computed from expressions")
+	viper.BindPFlag(vParamSetForce, paramSetCmd.Flags().Lookup(flagForce))
+
+	paramSetCmd.Flags().Bool(flagSecret, false, "Store the param encrypted in the namespace's secrets")
+	viper.BindPFlag(vParamSetSecret, paramSetCmd.Flags().Lookup(flagSecret))
 }
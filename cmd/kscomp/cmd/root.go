@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/bryanl/woowoo/component"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 
@@ -26,7 +27,8 @@ import (
 )
 
 const (
-	vRootVerbose = "root-verbose"
+	vRootVerbose   = "root-verbose"
+	vRootSecretKey = "root-secret-key-file"
 )
 
 var fs = afero.NewOsFs()
@@ -42,6 +44,10 @@ var rootCmd = &cobra.Command{
 		verbosity := viper.GetInt(vRootVerbose)
 		logrus.SetLevel(logLevel(verbosity))
 
+		if path := viper.GetString(vRootSecretKey); path != "" {
+			return os.Setenv(component.SecretKeyEnvVar, path)
+		}
+
 		return nil
 	},
 }
@@ -60,6 +66,9 @@ func init() {
 
 	rootCmd.PersistentFlags().IntP(flagVerbose, "v", 0, "Verbosity level")
 	viper.BindPFlag(vRootVerbose, rootCmd.PersistentFlags().Lookup(flagVerbose))
+
+	rootCmd.PersistentFlags().String(flagSecretKey, "", "Path to the key which encrypts secret params. It must be outside of the app. Defaults to $"+component.SecretKeyEnvVar)
+	viper.BindPFlag(vRootSecretKey, rootCmd.PersistentFlags().Lookup(flagSecretKey))
 }
 
 // initConfig reads in config file and ENV variables if set.
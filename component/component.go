@@ -14,17 +14,19 @@ import (
 // ParamOptions is options for parameters.
 type ParamOptions struct {
 	Index int
+	// Force allows params computed from expressions to be replaced.
+	Force bool
 }
 
 // Summary summarizes items found in components.
 type Summary struct {
-	ComponentName string
-	IndexStr      string
-	Index         int
-	Type          string
-	APIVersion    string
-	Kind          string
-	Name          string
+	ComponentName string `json:"component"`
+	IndexStr      string `json:"-"`
+	Index         int    `json:"index"`
+	Type          string `json:"type"`
+	APIVersion    string `json:"apiVersion"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
 }
 
 // GVK converts a summary to a group - version - kind.
@@ -69,10 +71,21 @@ func Path(a app.App, name string) (string, error) {
 
 	for _, fi := range fis {
 		if fi.IsDir() {
-			continue
+			ok, err := isPackagedComponent(a.Fs(), filepath.Join(ns.Dir(), fi.Name()))
+			if err != nil {
+				return "", err
+			}
+
+			if !ok {
+				continue
+			}
 		}
 
 		base := strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))
+		if fi.IsDir() {
+			base = fi.Name()
+		}
+
 		if _, ok := files[base]; ok {
 			return "", errors.Errorf("Found multiple component files with component name %q", name)
 		}
@@ -157,3 +170,14 @@ func MakePaths(a app.App, env string) ([]string, error) {
 
 	return cpl.Locate()
 }
+
+// isPackagedComponent reports if a directory is a component, i.e. a Helm chart or
+// a kustomization.
+func isPackagedComponent(fs afero.Fs, dir string) (bool, error) {
+	isChart, err := isHelmChart(fs, dir)
+	if err != nil || isChart {
+		return isChart, err
+	}
+
+	return isKustomization(fs, dir)
+}
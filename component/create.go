@@ -0,0 +1,210 @@
+package component
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bryanl/woowoo/node"
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Create generates a Jsonnet component in a namespace which creates an object
+// of a kind. The kind's constructor is found in k8s.libsonnet. Its arguments
+// are read from params, and an entry with their defaults is added to the
+// namespace's params. Common setters are included as comments.
+func Create(a app.App, nsName, name, apiVersion, kind string) (Component, error) {
+	ns, err := GetNamespace(a, nsName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ns.checkComponentName(name); err != nil {
+		return nil, err
+	}
+
+	ts, err := NewTypeSpec(apiVersion, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	libPath, err := a.LibPath("default")
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := jsonnetutil.ImportFromFs(filepath.Join(libPath, "k8s.libsonnet"), a.Fs())
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := ts.GVK()
+	ctor, err := node.New("k8s", obj).Constructor(gvk.Path()...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create %s %s", apiVersion, kind)
+	}
+
+	paramsData, err := ns.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	updatedParams, err := params.Update([]string{paramsComponentRoot, name}, paramsData, paramDefaults(ctor, name))
+	if err != nil {
+		return nil, errors.Wrap(err, "update params")
+	}
+
+	source := filepath.Join(ns.Dir(), name+".jsonnet")
+	if err := afero.WriteFile(a.Fs(), source, []byte(componentSource(name, ctor)), 0644); err != nil {
+		return nil, err
+	}
+
+	if err := ns.writeParams(updatedParams); err != nil {
+		return nil, err
+	}
+
+	return NewJsonnet(a, ns.Name(), source, ns.ParamsPath()), nil
+}
+
+// paramDefaults returns the initial params for a component which calls a
+// constructor. Defaults which refer to other params are resolved. Params
+// without a usable default are set to an empty array or object if the
+// constructor expects one, or null otherwise.
+func paramDefaults(ctor *node.Constructor, name string) map[string]interface{} {
+	defaults := make(map[string]interface{})
+	for _, p := range ctor.Params {
+		defaults[p.Name] = p.Default
+		if p.Name == "name" {
+			defaults[p.Name] = name
+		}
+	}
+
+	values := make(map[string]interface{})
+	for _, p := range ctor.Params {
+		v, ok := resolveDefault(defaults[p.Name], defaults, true)
+		if !ok {
+			v = nil
+		}
+
+		switch p.Type {
+		case node.ValueTypeArray:
+			if _, ok := v.([]interface{}); !ok {
+				v = make([]interface{}, 0)
+			}
+		case node.ValueTypeObject:
+			if _, ok := v.(map[string]interface{}); !ok {
+				v = make(map[string]interface{})
+			}
+		}
+
+		values[p.Name] = v
+	}
+
+	return values
+}
+
+// resolveDefault replaces references to other params in a default with their
+// defaults. Only references to defaults which don't refer to other params
+// can be resolved.
+func resolveDefault(v interface{}, defaults map[string]interface{}, follow bool) (interface{}, bool) {
+	switch t := v.(type) {
+	case node.Ref:
+		ref, ok := defaults[string(t)]
+		if !ok || !follow {
+			return nil, false
+		}
+		return resolveDefault(ref, defaults, false)
+	case []interface{}:
+		array := make([]interface{}, 0, len(t))
+		for _, elem := range t {
+			resolved, ok := resolveDefault(elem, defaults, follow)
+			if !ok {
+				return nil, false
+			}
+			array = append(array, resolved)
+		}
+		return array, true
+	case map[string]interface{}:
+		m := make(map[string]interface{})
+		for k, elem := range t {
+			resolved, ok := resolveDefault(elem, defaults, follow)
+			if !ok {
+				return nil, false
+			}
+			m[k] = resolved
+		}
+		return m, true
+	default:
+		return v, true
+	}
+}
+
+// checkComponentName returns an error if a component named name exists in the
+// namespace.
+func (n *Namespace) checkComponentName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return errors.Errorf("invalid component name %q", name)
+	}
+
+	components, err := n.Components()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range components {
+		if c.Name(false) == name {
+			return errors.Errorf("component %q already exists", c.Name(true))
+		}
+	}
+
+	return nil
+}
+
+// componentSource generates the source for a component which calls a
+// constructor.
+func componentSource(name string, ctor *node.Constructor) string {
+	local := ctor.Path[len(ctor.Path)-1]
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, `local env = std.extVar("__ksonnet/environments");`)
+	fmt.Fprintf(&buf, "local params = std.extVar(\"__ksonnet/params\").components[%q];\n", name)
+	fmt.Fprintln(&buf, `local k = import "k.libsonnet";`)
+	fmt.Fprintf(&buf, "local %s = k.%s;\n", local, strings.Join(ctor.Path, "."))
+	fmt.Fprintln(&buf)
+
+	var args []string
+	for _, p := range ctor.Params {
+		args = append(args, paramRef(p.Name))
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintf(&buf, "%s.new()\n", local)
+	} else {
+		fmt.Fprintf(&buf, "%s\n  .new(\n    %s)\n", local, strings.Join(args, ",\n    "))
+	}
+
+	if len(ctor.Setters) > 0 {
+		fmt.Fprintln(&buf, "// Add a param for each setter you use.")
+		for _, s := range ctor.Setters {
+			path := append([]string{local}, s.Path...)
+			path = append(path, s.Name)
+			fmt.Fprintf(&buf, "// + %s(%s)\n", strings.Join(path, "."), paramRef(s.Param))
+		}
+	}
+
+	return buf.String()
+}
+
+// paramRef is the Jsonnet expression for a param.
+func paramRef(name string) string {
+	if params.IsIdentifier(name) {
+		return "params." + name
+	}
+
+	return fmt.Sprintf("params[%q]", name)
+}
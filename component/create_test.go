@@ -0,0 +1,96 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/bryanl/woowoo/node"
+	"github.com/bryanl/woowoo/params"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate(t *testing.T) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+	for _, file := range []string{"k.libsonnet", "k8s.libsonnet"} {
+		stageFile(t, fs, "guestbook/"+file, "/app/lib/v1.8.7/"+file)
+		stageFile(t, fs, "guestbook/"+file, "/app/components/"+file)
+	}
+
+	c, err := Create(app, "", "web", "apps/v1beta1", "Deployment")
+	require.NoError(t, err)
+	require.Equal(t, "web", c.Name(true))
+
+	b, err := afero.ReadFile(fs, "/app/components/web.jsonnet")
+	require.NoError(t, err)
+	require.Equal(t, string(testdata(t, "create-deployment.jsonnet")), string(b))
+
+	paramsData, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := params.ToMap("web", string(paramsData), "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name":       "web",
+		"replicas":   float64(1),
+		"containers": []interface{}{},
+		"podLabels":  map[string]interface{}{"app": "web"},
+	}
+	require.Equal(t, expected, got)
+
+	objects, err := c.Objects(string(paramsData), "default")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.Equal(t, "Deployment", objects[0].GetKind())
+	require.Equal(t, "web", objects[0].GetName())
+
+	_, err = Create(app, "", "web", "apps/v1beta1", "Deployment")
+	require.Error(t, err)
+}
+
+func Test_paramDefaults(t *testing.T) {
+	ctor := &node.Constructor{
+		Params: []node.Param{
+			{Name: "name"},
+			{Name: "replicas"},
+			{Name: "containers", Type: node.ValueTypeArray},
+			{Name: "volumeClaims", Default: "", Type: node.ValueTypeArray},
+			{Name: "podLabels", Default: map[string]interface{}{"app": node.Ref("name")}, Type: node.ValueTypeObject},
+			{Name: "selector", Default: map[string]interface{}{"labels": node.Ref("podLabels")}, Type: node.ValueTypeObject},
+			{Name: "ports", Default: []interface{}{node.Ref("port")}},
+			{Name: "port", Default: float64(80)},
+			{Name: "target", Default: node.Ref("missing")},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"name":         "web",
+		"replicas":     nil,
+		"containers":   []interface{}{},
+		"volumeClaims": []interface{}{},
+		"podLabels":    map[string]interface{}{"app": "web"},
+		// podLabels refers to another param, so it can't be resolved.
+		"selector": map[string]interface{}{},
+		"ports":    []interface{}{float64(80)},
+		"port":     float64(80),
+		"target":   nil,
+	}
+
+	require.Equal(t, expected, paramDefaults(ctor, "web"))
+}
+
+func TestCreate_unknown_kind(t *testing.T) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "guestbook/k8s.libsonnet", "/app/lib/v1.8.7/k8s.libsonnet")
+
+	_, err := Create(app, "", "web", "apps/v1beta1", "Unknown")
+	require.Error(t, err)
+
+	exists, err := afero.Exists(fs, "/app/components/web.jsonnet")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
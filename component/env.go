@@ -0,0 +1,239 @@
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// ParamSourceGlobal is a param which comes from namespace globals.
+	ParamSourceGlobal = "global"
+	// ParamSourceComponent is a param which comes from the component's entry.
+	ParamSourceComponent = "component"
+	// ParamSourceEnvironment is a param which is overridden by an environment.
+	ParamSourceEnvironment = "environment"
+)
+
+// EnvParamsPath generates the path to params.libsonnet for an environment.
+func EnvParamsPath(a app.App, envName string) string {
+	return filepath.Join(a.Root(), "environments", envName, paramsFile)
+}
+
+// ParamsKey returns the key a component's params are stored under. Components
+// which generate multiple objects key their params by index.
+func ParamsKey(c Component, options ParamOptions) string {
+	switch c.(type) {
+	case *YAML, *Kustomize:
+		return fmt.Sprintf("%s-%d", c.Name(false), options.Index)
+	default:
+		return c.Name(false)
+	}
+}
+
+// SetEnvParam sets an environment override for a component param.
+func SetEnvParam(a app.App, envName string, c Component, path []string, value interface{}, options ParamOptions) error {
+	envData, err := readEnvParams(a, envName)
+	if err != nil {
+		return err
+	}
+
+	updated, err := params.SetEnv(path, envData, ParamsKey(c, options), value, params.WithForce(options.Force))
+	if err != nil {
+		return err
+	}
+
+	return writeEnvParams(a, envName, updated)
+}
+
+// DeleteEnvParam deletes an environment override for a component param.
+func DeleteEnvParam(a app.App, envName string, c Component, path []string, options ParamOptions) error {
+	envData, err := readEnvParams(a, envName)
+	if err != nil {
+		return err
+	}
+
+	updated, err := params.DeleteEnv(path, envData, ParamsKey(c, options))
+	if err != nil {
+		return err
+	}
+
+	return writeEnvParams(a, envName, updated)
+}
+
+// EnvParams returns the effective params for a namespace in an environment.
+// resolved is the namespace's params after the environment has been applied
+// as JSON. Each param records where its value came from.
+func (n *Namespace) EnvParams(envName, resolved string) ([]NamespaceParameter, error) {
+	var doc patchDoc
+	if err := json.Unmarshal([]byte(resolved), &doc); err != nil {
+		return nil, errors.Wrap(err, "decode resolved params")
+	}
+
+	paramsData, err := n.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := params.ToMap("", paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find components")
+	}
+
+	localComputed, err := params.Computed("", paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	globals, err := params.ToMap("", paramsData, "global")
+	if err != nil {
+		globals = make(map[string]interface{})
+	}
+
+	globalComputed, _ := params.Computed("", paramsData, "global")
+
+	envData, err := readEnvParams(n.app, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := params.EnvToMap("", envData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %q environment params", envName)
+	}
+
+	envComputed, err := params.EnvComputed("", envData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %q environment params", envName)
+	}
+
+	secrets, err := n.secretParams()
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := n.Components()
+	if err != nil {
+		return nil, err
+	}
+
+	var nsps []NamespaceParameter
+	for key := range local {
+		name, index, ok := paramsKeyOwner(components, key)
+		if !ok {
+			continue
+		}
+
+		values, ok := doc.Components[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		envValues, _ := overrides[key].(map[string]interface{})
+
+		for k, v := range values {
+			vStr, err := paramValue(v)
+			if err != nil {
+				return nil, err
+			}
+
+			source := ParamSourceComponent
+			computed := localComputed.Child(key).Has([]string{k})
+			secret := false
+			if _, ok := envValues[k]; ok {
+				source = ParamSourceEnvironment
+				computed = envComputed.Child(key).Has([]string{k})
+			} else if isSecret(secrets[key], []string{k}) {
+				source = ParamSourceSecret
+				computed = false
+				secret = true
+				vStr = params.SecretMask
+				v = params.SecretMask
+			} else if _, ok := globals[k]; ok {
+				source = ParamSourceGlobal
+				computed = globalComputed.Has([]string{k})
+			}
+
+			nsps = append(nsps, NamespaceParameter{
+				Component: name,
+				Index:     index,
+				Key:       k,
+				Value:     vStr,
+				RawValue:  v,
+				Source:    source,
+				Computed:  computed,
+				Secret:    secret,
+			})
+		}
+	}
+
+	sortParams(nsps)
+
+	return nsps, nil
+}
+
+// paramsKeyOwner finds the component and index a params key belongs to.
+func paramsKeyOwner(components []Component, key string) (string, string, bool) {
+	for _, c := range components {
+		name := c.Name(false)
+		if ParamsKey(c, ParamOptions{}) == name {
+			if key == name {
+				return name, "0", true
+			}
+			continue
+		}
+
+		re := regexp.MustCompile(fmt.Sprintf(`^%s-(\d+)$`, regexp.QuoteMeta(name)))
+		if match := re.FindStringSubmatch(key); match != nil {
+			return name, match[1], true
+		}
+	}
+
+	return "", "", false
+}
+
+// acceptsParamsKey reports whether a params key belongs to a component. Keys
+// for documents a YAML component doesn't have aren't accepted.
+func acceptsParamsKey(c Component, key string) (bool, error) {
+	_, index, ok := paramsKeyOwner([]Component{c}, key)
+	if !ok {
+		return false, nil
+	}
+
+	y, ok := c.(*YAML)
+	if !ok {
+		return true, nil
+	}
+
+	count, err := y.documentCount()
+	if err != nil {
+		return false, err
+	}
+
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return false, err
+	}
+
+	return i < count, nil
+}
+
+func readEnvParams(a app.App, envName string) (string, error) {
+	b, err := afero.ReadFile(a.Fs(), EnvParamsPath(a, envName))
+	if err != nil {
+		return "", errors.Wrapf(err, "read %q environment params", envName)
+	}
+
+	return string(b), nil
+}
+
+func writeEnvParams(a app.App, envName, src string) error {
+	return afero.WriteFile(a.Fs(), EnvParamsPath(a, envName), []byte(src), 0644)
+}
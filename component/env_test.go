@@ -0,0 +1,69 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEnvParam(t *testing.T) {
+	app, fs := appMock("/app")
+
+	files := []string{"guestbook-ui.jsonnet", "params.libsonnet"}
+	for _, file := range files {
+		stageFile(t, fs, "guestbook/"+file, "/app/components/"+file)
+	}
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	c := NewJsonnet(app, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+
+	err := SetEnvParam(app, "default", c, []string{"replicas"}, 3, ParamOptions{})
+	require.NoError(t, err)
+
+	b, err := afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := params.EnvToMap("guestbook-ui", string(b))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"replicas": float64(3)}, got)
+
+	err = DeleteEnvParam(app, "default", c, []string{"replicas"}, ParamOptions{})
+	require.NoError(t, err)
+
+	b, err = afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+
+	got, err = params.EnvToMap("guestbook-ui", string(b))
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestNamespace_EnvParams(t *testing.T) {
+	app, fs := appMock("/app")
+
+	files := []string{"guestbook-ui.jsonnet", "params.libsonnet"}
+	for _, file := range files {
+		stageFile(t, fs, "guestbook/"+file, "/app/components/"+file)
+	}
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	c := NewJsonnet(app, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+	err := SetEnvParam(app, "default", c, []string{"replicas"}, 3, ParamOptions{})
+	require.NoError(t, err)
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	resolved := `{"components": {"guestbook-ui": {"name": "guiroot", "replicas": 3}}}`
+	got, err := ns.EnvParams("default", resolved)
+	require.NoError(t, err)
+
+	expected := []NamespaceParameter{
+		{Component: "guestbook-ui", Index: "0", Key: "name", Value: `"guiroot"`, RawValue: "guiroot", Source: ParamSourceComponent},
+		{Component: "guestbook-ui", Index: "0", Key: "replicas", Value: "3", RawValue: float64(3), Source: ParamSourceEnvironment},
+	}
+
+	require.Equal(t, expected, got)
+}
@@ -0,0 +1,136 @@
+package component
+
+import (
+	"encoding/json"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ParamSourceEffective is the value of a param after all layers are applied.
+	ParamSourceEffective = "effective"
+)
+
+// ParamLayer is the value of a param at one layer of resolution.
+type ParamLayer struct {
+	Source string `json:"source"`
+	Value  string `json:"value,omitempty"`
+	IsSet  bool   `json:"isSet"`
+}
+
+// ExplainParam returns the value of a component param at each layer it is
+// resolved through: the component's params, namespace globals, the namespace's
+// secrets when the param is secret, and the environment's overrides when
+// envName is set. Secret values are masked. resolved is the namespace's
+// params as JSON after every layer has been applied. It provides the final
+// layer.
+func (n *Namespace) ExplainParam(c Component, options ParamOptions, path []string, envName, resolved string) ([]ParamLayer, error) {
+	if len(path) == 0 {
+		return nil, errors.New("param path is blank")
+	}
+
+	key := ParamsKey(c, options)
+
+	paramsData, err := n.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := params.ToMap(key, paramsData, paramsComponentRoot)
+	if err != nil {
+		local = make(map[string]interface{})
+	}
+
+	globals, err := params.ToMap("", paramsData, "global")
+	if err != nil {
+		globals = make(map[string]interface{})
+	}
+
+	var layers []ParamLayer
+
+	layer, err := paramLayer(ParamSourceComponent, local, path)
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, layer)
+
+	layer, err = paramLayer(ParamSourceGlobal, globals, path)
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, layer)
+
+	secrets, err := n.secretParams()
+	if err != nil {
+		return nil, err
+	}
+
+	secret := isSecret(secrets[key], path)
+	if secret {
+		layers = append(layers, ParamLayer{
+			Source: ParamSourceSecret,
+			Value:  params.SecretMask,
+			IsSet:  true,
+		})
+	}
+
+	if envName != "" {
+		envData, err := readEnvParams(n.app, envName)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides, err := params.EnvToMap(key, envData)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read %q environment params", envName)
+		}
+
+		layer, err = paramLayer(ParamSourceEnvironment, overrides, path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+
+		if layer.IsSet {
+			secret = false
+		}
+	}
+
+	var doc patchDoc
+	if err := json.Unmarshal([]byte(resolved), &doc); err != nil {
+		return nil, errors.Wrap(err, "decode resolved params")
+	}
+
+	effective, _ := doc.Components[key].(map[string]interface{})
+	layer, err = paramLayer(ParamSourceEffective, effective, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret {
+		layer = ParamLayer{Source: ParamSourceEffective, Value: params.SecretMask, IsSet: true}
+	}
+	layers = append(layers, layer)
+
+	return layers, nil
+}
+
+func paramLayer(source string, m map[string]interface{}, path []string) (ParamLayer, error) {
+	layer := ParamLayer{Source: source}
+
+	v, ok := params.LookupValue(m, path)
+	if !ok {
+		return layer, nil
+	}
+
+	s, err := paramValue(v)
+	if err != nil {
+		return ParamLayer{}, err
+	}
+
+	layer.Value = s
+	layer.IsSet = true
+
+	return layer, nil
+}
@@ -0,0 +1,64 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespace_ExplainParam(t *testing.T) {
+	app, fs := appMock("/app")
+
+	files := []string{"guestbook-ui.jsonnet", "params.libsonnet"}
+	for _, file := range files {
+		stageFile(t, fs, "guestbook/"+file, "/app/components/"+file)
+	}
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	c := NewJsonnet(app, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	require.NoError(t, ns.SetParam([]string{"replicas"}, 2, ParamOptions{}))
+	require.NoError(t, SetEnvParam(app, "default", c, []string{"replicas"}, 3, ParamOptions{}))
+
+	resolved := `{"components": {"guestbook-ui": {"replicas": 3, "obj": {"a": "b"}}}}`
+
+	cases := []struct {
+		name     string
+		path     []string
+		envName  string
+		expected []ParamLayer
+	}{
+		{
+			name:    "overridden by environment",
+			path:    []string{"replicas"},
+			envName: "default",
+			expected: []ParamLayer{
+				{Source: ParamSourceComponent, Value: "1", IsSet: true},
+				{Source: ParamSourceGlobal, Value: "2", IsSet: true},
+				{Source: ParamSourceEnvironment, Value: "3", IsSet: true},
+				{Source: ParamSourceEffective, Value: "3", IsSet: true},
+			},
+		},
+		{
+			name: "nested without environment",
+			path: []string{"obj", "a"},
+			expected: []ParamLayer{
+				{Source: ParamSourceComponent, Value: `"b"`, IsSet: true},
+				{Source: ParamSourceGlobal},
+				{Source: ParamSourceEffective, Value: `"b"`, IsSet: true},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ns.ExplainParam(c, ParamOptions{}, tc.path, tc.envName, resolved)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package component
+
+import (
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+)
+
+// ParamChanges are the keys an import added, changed and removed. Keys are
+// flattened, e.g. `components.guestbook-ui.replicas`.
+type ParamChanges struct {
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+// Empty reports whether there are no changes.
+func (pc *ParamChanges) Empty() bool {
+	return len(pc.Added) == 0 && len(pc.Changed) == 0 && len(pc.Removed) == 0
+}
+
+// ExportParams returns a namespace's params flattened with params.Flatten.
+// Keys start with `components.<component>.` or `global.`. Secrets are not
+// exported.
+func (n *Namespace) ExportParams() (map[string]interface{}, error) {
+	paramsData, err := n.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaceFlatParams(paramsData)
+}
+
+// ImportParams replaces a namespace's params with flattened params. Changes
+// are applied with params.Set and the params file is only written if all of
+// them succeed.
+func (n *Namespace) ImportParams(values map[string]interface{}, options ParamOptions) (ParamChanges, error) {
+	paramsData, err := n.readParams()
+	if err != nil {
+		return ParamChanges{}, err
+	}
+
+	current, err := namespaceFlatParams(paramsData)
+	if err != nil {
+		return ParamChanges{}, err
+	}
+
+	schema, err := n.Schema()
+	if err != nil {
+		return ParamChanges{}, err
+	}
+
+	ops := flatOps{
+		set: func(path []string, value interface{}) error {
+			root, key, rest, err := splitNamespaceKey(path)
+			if err != nil {
+				return err
+			}
+
+			paramsData, err = params.Set(rest, paramsData, key, value, root, params.WithSchema(schema), params.WithForce(options.Force))
+			return err
+		},
+		delete: func(path []string) error {
+			root, key, rest, err := splitNamespaceKey(path)
+			if err != nil {
+				return err
+			}
+
+			paramsData, err = params.Delete(rest, paramsData, key, root)
+			return err
+		},
+	}
+
+	changes, err := ops.apply(current, values)
+	if err != nil {
+		return ParamChanges{}, err
+	}
+
+	if changes.Empty() {
+		return changes, nil
+	}
+
+	if err := n.writeParams(paramsData); err != nil {
+		return ParamChanges{}, err
+	}
+
+	return changes, nil
+}
+
+// ExportEnvParams returns an environment's overrides flattened with
+// params.Flatten. Keys start with `components.<component>.`.
+func ExportEnvParams(a app.App, envName string) (map[string]interface{}, error) {
+	envData, err := readEnvParams(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	return envFlatParams(envName, envData)
+}
+
+// ImportEnvParams replaces an environment's overrides with flattened params.
+// The environment's params file is only written if all changes succeed.
+func ImportEnvParams(a app.App, envName string, values map[string]interface{}, options ParamOptions) (ParamChanges, error) {
+	envData, err := readEnvParams(a, envName)
+	if err != nil {
+		return ParamChanges{}, err
+	}
+
+	current, err := envFlatParams(envName, envData)
+	if err != nil {
+		return ParamChanges{}, err
+	}
+
+	ops := flatOps{
+		set: func(path []string, value interface{}) error {
+			key, rest, err := splitEnvKey(path)
+			if err != nil {
+				return err
+			}
+
+			envData, err = params.SetEnv(rest, envData, key, value, params.WithForce(options.Force))
+			return err
+		},
+		delete: func(path []string) error {
+			key, rest, err := splitEnvKey(path)
+			if err != nil {
+				return err
+			}
+
+			envData, err = params.DeleteEnv(rest, envData, key)
+			return err
+		},
+	}
+
+	changes, err := ops.apply(current, values)
+	if err != nil {
+		return ParamChanges{}, err
+	}
+
+	if changes.Empty() {
+		return changes, nil
+	}
+
+	if err := writeEnvParams(a, envName, envData); err != nil {
+		return ParamChanges{}, err
+	}
+
+	return changes, nil
+}
+
+func namespaceFlatParams(paramsData string) (map[string]interface{}, error) {
+	components, err := params.ToMap("", paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find components")
+	}
+
+	globals, err := params.ToMap("", paramsData, "global")
+	if err != nil {
+		globals = make(map[string]interface{})
+	}
+
+	values := params.Flatten([]string{"global"}, globals)
+	for k, v := range flattenComponents(components) {
+		values[k] = v
+	}
+
+	return values, nil
+}
+
+func envFlatParams(envName, envData string) (map[string]interface{}, error) {
+	overrides, err := params.EnvToMap("", envData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %q environment params", envName)
+	}
+
+	return flattenComponents(overrides), nil
+}
+
+// flattenComponents flattens params keyed by component. Components without
+// params are omitted.
+func flattenComponents(components map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for key, v := range components {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for k, value := range params.Flatten([]string{paramsComponentRoot, key}, m) {
+			values[k] = value
+		}
+	}
+
+	return values
+}
+
+// splitNamespaceKey splits a flattened namespace key into the root and params
+// key it is stored under and the path of the param.
+func splitNamespaceKey(path []string) (string, string, []string, error) {
+	switch {
+	case len(path) > 2 && path[0] == paramsComponentRoot:
+		return path[0], path[1], path[2:], nil
+	case len(path) > 1 && path[0] == "global":
+		return path[0], "", path[1:], nil
+	default:
+		return "", "", nil, errors.Errorf("param %q must start with components.<component>. or global.", params.FormatPath(path))
+	}
+}
+
+// splitEnvKey splits a flattened environment key into the params key it is
+// stored under and the path of the param.
+func splitEnvKey(path []string) (string, []string, error) {
+	if len(path) > 2 && path[0] == paramsComponentRoot {
+		return path[1], path[2:], nil
+	}
+
+	return "", nil, errors.Errorf("param %q must start with components.<component>.", params.FormatPath(path))
+}
+
+// flatOps applies the difference between two sets of flattened params.
+type flatOps struct {
+	set    func(path []string, value interface{}) error
+	delete func(path []string) error
+}
+
+func (o *flatOps) apply(current, desired map[string]interface{}) (ParamChanges, error) {
+	var changes ParamChanges
+
+	desiredPaths := make([][]string, 0, len(desired))
+	for _, k := range params.SortedKeys(desired) {
+		path, err := params.ParsePath(k)
+		if err != nil {
+			return ParamChanges{}, err
+		}
+		desiredPaths = append(desiredPaths, path)
+	}
+
+	// Params are removed first, deepest and highest index first, so array
+	// elements don't shift before they are removed. A param is removed at the
+	// shallowest path which has no imported params under it, so emptied
+	// objects and array elements don't linger.
+	removed := make(map[string]bool)
+	var deletes [][]string
+	for _, k := range params.SortedKeys(current) {
+		if _, ok := desired[k]; ok {
+			continue
+		}
+		changes.Removed = append(changes.Removed, k)
+
+		path, err := params.ParsePath(k)
+		if err != nil {
+			return ParamChanges{}, err
+		}
+
+		target := removalPath(path, desiredPaths)
+		if id := params.FormatPath(target); !removed[id] {
+			removed[id] = true
+			deletes = append(deletes, target)
+		}
+	}
+
+	for i := len(deletes) - 1; i >= 0; i-- {
+		if err := o.delete(deletes[i]); err != nil {
+			return ParamChanges{}, errors.Wrapf(err, "remove %s", params.FormatPath(deletes[i]))
+		}
+	}
+
+	for i, k := range params.SortedKeys(desired) {
+		cur, ok := current[k]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, k)
+		case !params.FlatEqual(cur, desired[k]):
+			changes.Changed = append(changes.Changed, k)
+		default:
+			continue
+		}
+
+		if err := o.set(desiredPaths[i], desired[k]); err != nil {
+			return ParamChanges{}, errors.Wrapf(err, "set %s", k)
+		}
+	}
+
+	return changes, nil
+}
+
+// removalPath returns the shallowest prefix of path, below the component or
+// global root, which has none of the desired paths under it.
+func removalPath(path []string, desired [][]string) []string {
+	min := 2
+	if path[0] == "global" {
+		min = 1
+	}
+
+	for n := min + 1; n < len(path); n++ {
+		prefix := path[:n]
+		used := false
+		for _, d := range desired {
+			if len(d) >= n && params.ComparePaths(d[:n], prefix) == 0 {
+				used = true
+				break
+			}
+		}
+
+		if !used {
+			return prefix
+		}
+	}
+
+	return path
+}
@@ -0,0 +1,149 @@
+package component
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespace_ExportParams(t *testing.T) {
+	app, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	got, err := ns.ExportParams()
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"components.guestbook-ui.containerPort": float64(80),
+		"components.guestbook-ui.image":         "gcr.io/heptio-images/ks-guestbook-demo:0.1",
+		"components.guestbook-ui.name":          "guiroot",
+		"components.guestbook-ui.replicas":      float64(1),
+		"components.guestbook-ui.servicePort":   float64(80),
+		"components.guestbook-ui.type":          "ClusterIP",
+		"components.guestbook-ui.obj.a":         "b",
+	}
+	require.Equal(t, expected, got)
+}
+
+func TestNamespace_ImportParams(t *testing.T) {
+	app, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	values, err := ns.ExportParams()
+	require.NoError(t, err)
+
+	delete(values, "components.guestbook-ui.obj.a")
+	values["components.guestbook-ui.replicas"] = 3
+	values["global.replicas"] = 2
+
+	changes, err := ns.ImportParams(values, ParamOptions{})
+	require.NoError(t, err)
+
+	expected := ParamChanges{
+		Added:   []string{"global.replicas"},
+		Changed: []string{"components.guestbook-ui.replicas"},
+		Removed: []string{"components.guestbook-ui.obj.a"},
+	}
+	require.Equal(t, expected, changes)
+
+	b, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(b), "// Component-level parameters"), "comments were not preserved")
+
+	got, err := params.ToMap("guestbook-ui", string(b), "components")
+	require.NoError(t, err)
+	require.Equal(t, float64(3), got["replicas"])
+	_, ok := got["obj"]
+	require.False(t, ok, "emptied object was not removed")
+
+	globals, err := params.ToMap("", string(b), "global")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"replicas": float64(2)}, globals)
+
+	changes, err = ns.ImportParams(values, ParamOptions{})
+	require.NoError(t, err)
+	require.True(t, changes.Empty())
+}
+
+func TestNamespace_ImportParams_invalid(t *testing.T) {
+	app, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+
+	before, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	values, err := ns.ExportParams()
+	require.NoError(t, err)
+
+	values["components.guestbook-ui.replicas"] = 5
+	values["unknown.key"] = 1
+
+	_, err = ns.ImportParams(values, ParamOptions{})
+	require.Error(t, err)
+
+	after, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after))
+}
+
+func TestImportEnvParams(t *testing.T) {
+	app, fs := appMock("/app")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	values := map[string]interface{}{
+		"components.guestbook-ui.replicas":         3,
+		"components.guestbook-ui.ports[0].name":    "http",
+		"components.guestbook-ui.ports[1].name":    "https",
+		"components.guestbook-ui.ports[1].enabled": false,
+	}
+
+	changes, err := ImportEnvParams(app, "default", values, ParamOptions{})
+	require.NoError(t, err)
+	require.Len(t, changes.Added, 4)
+
+	got, err := ExportEnvParams(app, "default")
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+	for k, v := range values {
+		require.True(t, params.FlatEqual(v, got[k]), k)
+	}
+
+	delete(values, "components.guestbook-ui.ports[1].name")
+	delete(values, "components.guestbook-ui.ports[1].enabled")
+	delete(values, "components.guestbook-ui.replicas")
+
+	changes, err = ImportEnvParams(app, "default", values, ParamOptions{})
+	require.NoError(t, err)
+
+	expected := []string{
+		"components.guestbook-ui.ports[1].enabled",
+		"components.guestbook-ui.ports[1].name",
+		"components.guestbook-ui.replicas",
+	}
+	require.Equal(t, expected, changes.Removed)
+
+	b, err := afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+
+	overrides, err := params.EnvToMap("guestbook-ui", string(b))
+	require.NoError(t, err)
+
+	expectedOverrides := map[string]interface{}{
+		"ports": []interface{}{
+			map[string]interface{}{"name": "http"},
+		},
+	}
+	require.Equal(t, expectedOverrides, overrides)
+}
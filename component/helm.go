@@ -0,0 +1,476 @@
+package component
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bryanl/woowoo/k8sutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	amyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// helmChartFile is the file which identifies a directory as a Helm chart.
+	helmChartFile = "Chart.yaml"
+	// helmValuesFile is the file containing a chart's default values.
+	helmValuesFile = "values.yaml"
+	// helmTemplatesDir is the directory containing a chart's templates.
+	helmTemplatesDir = "templates"
+)
+
+// isHelmChart reports if a directory is an unpacked Helm chart.
+func isHelmChart(fs afero.Fs, dir string) (bool, error) {
+	return afero.Exists(fs, filepath.Join(dir, helmChartFile))
+}
+
+// HelmChart is the metadata from a chart's Chart.yaml.
+type HelmChart struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Helm is a component based on a local, unpacked Helm chart. Templates are rendered
+// in-process and the component's entry in params.libsonnet is merged over the
+// chart's values.yaml.
+type Helm struct {
+	app        app.App
+	nsName     string
+	source     string
+	paramsPath string
+}
+
+var _ Component = (*Helm)(nil)
+
+// NewHelm creates an instance of Helm. `source` is the chart directory.
+func NewHelm(a app.App, nsName, source, paramsPath string) *Helm {
+	return &Helm{
+		app:        a,
+		nsName:     nsName,
+		source:     source,
+		paramsPath: paramsPath,
+	}
+}
+
+// Name is the name of this component. It is the name of the chart directory.
+func (h *Helm) Name(wantsNameSpaced bool) string {
+	name := filepath.Base(h.source)
+	if !wantsNameSpaced {
+		return name
+	}
+
+	return strings.TrimPrefix(path.Join(h.nsName, name), "/")
+}
+
+// Chart returns the chart metadata.
+func (h *Helm) Chart() (*HelmChart, error) {
+	b, err := afero.ReadFile(h.app.Fs(), filepath.Join(h.source, helmChartFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "read chart metadata")
+	}
+
+	var chart HelmChart
+	if err = yaml.Unmarshal(b, &chart); err != nil {
+		return nil, errors.Wrap(err, "decode chart metadata")
+	}
+
+	if chart.Name == "" {
+		chart.Name = h.Name(false)
+	}
+
+	return &chart, nil
+}
+
+// Objects renders the chart templates to a slice of apimachinery unstructured objects.
+func (h *Helm) Objects(paramsStr, envName string) ([]*unstructured.Unstructured, error) {
+	if paramsStr == "" {
+		var err error
+		if paramsStr, err = h.readParams(); err != nil {
+			return nil, err
+		}
+	}
+
+	manifests, err := h.render(paramsStr, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []runtime.Object
+	for _, manifest := range manifests {
+		objects, err := decodeManifest(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, objects...)
+	}
+
+	return k8sutil.FlattenToV1(ret)
+}
+
+// values merges the component params over the chart's default values. It returns
+// the values as a JSON encoded string.
+func (h *Helm) values(paramsStr string) (string, error) {
+	valuesJSON := []byte("{}")
+
+	valuesPath := filepath.Join(h.source, helmValuesFile)
+	exists, err := afero.Exists(h.app.Fs(), valuesPath)
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		b, err := afero.ReadFile(h.app.Fs(), valuesPath)
+		if err != nil {
+			return "", errors.Wrap(err, "read chart values")
+		}
+
+		if len(bytes.TrimSpace(b)) > 0 {
+			if valuesJSON, err = yaml.YAMLToJSON(b); err != nil {
+				return "", errors.Wrap(err, "decode chart values")
+			}
+		}
+	}
+
+	return patchJSON(string(valuesJSON), paramsStr, h.Name(false))
+}
+
+// render renders the chart templates. It returns the rendered manifests sorted
+// by template name.
+func (h *Helm) render(paramsStr, envName string) ([]string, error) {
+	chart, err := h.Chart()
+	if err != nil {
+		return nil, err
+	}
+
+	valuesStr, err := h.values(paramsStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "merge chart values")
+	}
+
+	var values map[string]interface{}
+	if err = json.Unmarshal([]byte(valuesStr), &values); err != nil {
+		return nil, errors.Wrap(err, "decode chart values")
+	}
+
+	namespace, err := h.releaseNamespace(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := h.templates()
+	if err != nil {
+		return nil, err
+	}
+
+	root := template.New(chart.Name).Option("missingkey=zero")
+	root.Funcs(helmFuncMap(root))
+
+	var names []string
+	for name, src := range templates {
+		if _, err = root.New(name).Parse(src); err != nil {
+			return nil, errors.Wrapf(err, "parse template %s", name)
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	data := map[string]interface{}{
+		"Values": values,
+		"Chart": map[string]interface{}{
+			"Name":        chart.Name,
+			"Version":     chart.Version,
+			"AppVersion":  chart.AppVersion,
+			"Description": chart.Description,
+		},
+		"Release": map[string]interface{}{
+			"Name":      h.Name(false),
+			"Namespace": namespace,
+			"Service":   "ksonnet",
+			"IsInstall": true,
+			"IsUpgrade": false,
+		},
+	}
+
+	var manifests []string
+	for _, name := range names {
+		base := filepath.Base(name)
+		if strings.HasPrefix(base, "_") || base == "NOTES.txt" {
+			continue
+		}
+
+		data["Template"] = map[string]interface{}{
+			"Name":     name,
+			"BasePath": path.Join(chart.Name, helmTemplatesDir),
+		}
+
+		var buf bytes.Buffer
+		if err = root.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, errors.Wrapf(err, "render template %s", name)
+		}
+
+		manifest := strings.Replace(buf.String(), "<no value>", "", -1)
+		if strings.TrimSpace(manifest) == "" {
+			continue
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	return manifests, nil
+}
+
+// templates reads the chart's templates. Templates are keyed by their chart relative
+// name, e.g. `mychart/templates/deployment.yaml`.
+func (h *Helm) templates() (map[string]string, error) {
+	chart, err := h.Chart()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(h.source, helmTemplatesDir)
+	exists, err := afero.DirExists(h.app.Fs(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]string)
+	if !exists {
+		return templates, nil
+	}
+
+	err = afero.Walk(h.app.Fs(), dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(h.source, path)
+		if err != nil {
+			return err
+		}
+
+		b, err := afero.ReadFile(h.app.Fs(), path)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(filepath.Join(chart.Name, rel))
+		templates[name] = string(b)
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "read chart templates")
+	}
+
+	return templates, nil
+}
+
+// releaseNamespace is the namespace of the environment's destination.
+func (h *Helm) releaseNamespace(envName string) (string, error) {
+	if envName == "" {
+		return "default", nil
+	}
+
+	env, err := h.app.Environment(envName)
+	if err != nil {
+		return "", err
+	}
+
+	if env.Destination == nil || env.Destination.Namespace == "" {
+		return "default", nil
+	}
+
+	return env.Destination.Namespace, nil
+}
+
+// decodeManifest decodes a rendered manifest which may contain multiple YAML documents.
+func decodeManifest(manifest string) ([]runtime.Object, error) {
+	decoder := amyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
+
+	var ret []runtime.Object
+	for {
+		b, err := decoder.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+
+		data, err := amyaml.ToJSON(b)
+		if err != nil {
+			return nil, err
+		}
+
+		if string(data) == "null" {
+			continue
+		}
+
+		obj, _, err := unstructured.UnstructuredJSONScheme.Decode(data, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, obj)
+	}
+
+	return ret, nil
+}
+
+// SetParam set parameter for a component.
+func (h *Helm) SetParam(path []string, value interface{}, options ParamOptions) error {
+	paramsData, err := h.readParams()
+	if err != nil {
+		return err
+	}
+
+	schema, err := readSchema(h.app.Fs(), h.paramsPath)
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Set(path, paramsData, h.Name(false), value, paramsComponentRoot, params.WithSchema(schema), params.WithForce(options.Force))
+	if err != nil {
+		return err
+	}
+
+	if err = h.writeParams(updatedParams); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteParam deletes a param.
+func (h *Helm) DeleteParam(path []string, options ParamOptions) error {
+	paramsData, err := h.readParams()
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Delete(path, paramsData, h.Name(false), paramsComponentRoot)
+	if err != nil {
+		return err
+	}
+
+	if err = h.writeParams(updatedParams); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Params returns params for a component. Chart values are nested, so params
+// are listed using their dotted path, e.g. `image.tag`.
+func (h *Helm) Params() ([]NamespaceParameter, error) {
+	paramsData, err := h.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := params.ToMap(h.Name(false), paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find components")
+	}
+
+	computed, err := params.Computed(h.Name(false), paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var params []NamespaceParameter
+	for _, pp := range mapToPaths(props, nil, nil) {
+		vStr, err := paramValue(pp.value)
+		if err != nil {
+			return nil, err
+		}
+
+		np := NamespaceParameter{
+			Component: h.Name(false),
+			Key:       strings.Join(pp.path, "."),
+			Index:     "0",
+			Value:     vStr,
+			RawValue:  pp.value,
+			Computed:  computed.Has(pp.path),
+		}
+
+		params = append(params, np)
+	}
+
+	return params, nil
+}
+
+// Summarize creates a summary for the component. It renders the chart and returns
+// a summary for each object.
+func (h *Helm) Summarize() ([]Summary, error) {
+	objects, err := h.Objects("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for i, obj := range objects {
+		summary := Summary{
+			ComponentName: h.Name(false),
+			IndexStr:      strconv.Itoa(i),
+			Index:         i,
+			Type:          "helm",
+			APIVersion:    obj.GetAPIVersion(),
+			Kind:          obj.GetKind(),
+			Name:          obj.GetName(),
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+func (h *Helm) readParams() (string, error) {
+	b, err := afero.ReadFile(h.app.Fs(), h.paramsPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (h *Helm) writeParams(src string) error {
+	return afero.WriteFile(h.app.Fs(), h.paramsPath, []byte(src), 0644)
+}
+
+// helmError is returned by the `fail` and `required` template functions.
+type helmError struct {
+	msg string
+}
+
+func (e *helmError) Error() string {
+	return fmt.Sprintf("chart template failed: %s", e.msg)
+}
@@ -0,0 +1,240 @@
+package component
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+)
+
+// helmFuncMap creates the functions available to chart templates. It implements
+// the commonly used subset of the functions Helm provides. `root` is the template
+// set used by `include` and `tpl`.
+func helmFuncMap(root *template.Template) template.FuncMap {
+	return template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var buf bytes.Buffer
+			if err := root.ExecuteTemplate(&buf, name, data); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		},
+		"tpl": func(src string, data interface{}) (string, error) {
+			t, err := root.Clone()
+			if err != nil {
+				return "", err
+			}
+			t, err = t.New("tpl").Parse(src)
+			if err != nil {
+				return "", err
+			}
+			var buf bytes.Buffer
+			if err = t.Execute(&buf, data); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		},
+		"required": func(msg string, v interface{}) (interface{}, error) {
+			if helmEmpty(v) {
+				return nil, &helmError{msg: msg}
+			}
+			return v, nil
+		},
+		"fail": func(msg string) (string, error) {
+			return "", &helmError{msg: msg}
+		},
+		"default": func(d interface{}, v ...interface{}) interface{} {
+			if len(v) == 0 || helmEmpty(v[0]) {
+				return d
+			}
+			return v[0]
+		},
+		"empty": helmEmpty,
+		"coalesce": func(v ...interface{}) interface{} {
+			for _, item := range v {
+				if !helmEmpty(item) {
+					return item
+				}
+			}
+			return nil
+		},
+		"ternary": func(t, f interface{}, cond bool) interface{} {
+			if cond {
+				return t
+			}
+			return f
+		},
+		"toYaml": func(v interface{}) string {
+			b, err := yaml.Marshal(v)
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSuffix(string(b), "\n")
+		},
+		"toJson": func(v interface{}) string {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return ""
+			}
+			return string(b)
+		},
+		"indent": func(n int, s string) string {
+			pad := strings.Repeat(" ", n)
+			return pad + strings.Replace(s, "\n", "\n"+pad, -1)
+		},
+		"nindent": func(n int, s string) string {
+			pad := strings.Repeat(" ", n)
+			return "\n" + pad + strings.Replace(s, "\n", "\n"+pad, -1)
+		},
+		"quote": func(v ...interface{}) string {
+			var out []string
+			for _, item := range v {
+				if item != nil {
+					out = append(out, strconv.Quote(helmString(item)))
+				}
+			}
+			return strings.Join(out, " ")
+		},
+		"squote": func(v ...interface{}) string {
+			var out []string
+			for _, item := range v {
+				if item != nil {
+					out = append(out, "'"+helmString(item)+"'")
+				}
+			}
+			return strings.Join(out, " ")
+		},
+		"toString": helmString,
+		"int": func(v interface{}) int {
+			switch t := v.(type) {
+			case int:
+				return t
+			case int64:
+				return int(t)
+			case float64:
+				return int(t)
+			case string:
+				i, _ := strconv.Atoi(t)
+				return i
+			default:
+				return 0
+			}
+		},
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      strings.Title,
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+		"repeat":     func(n int, s string) string { return strings.Repeat(s, n) },
+		"trunc": func(n int, s string) string {
+			if n >= 0 && len(s) > n {
+				return s[:n]
+			}
+			return s
+		},
+		"join": func(sep string, v interface{}) string {
+			var out []string
+			val := reflect.ValueOf(v)
+			if val.Kind() == reflect.Slice {
+				for i := 0; i < val.Len(); i++ {
+					out = append(out, helmString(val.Index(i).Interface()))
+				}
+			}
+			return strings.Join(out, sep)
+		},
+		"split": func(sep, s string) map[string]string {
+			m := make(map[string]string)
+			for i, part := range strings.Split(s, sep) {
+				m[fmt.Sprintf("_%d", i)] = part
+			}
+			return m
+		},
+		"list": func(v ...interface{}) []interface{} {
+			return v
+		},
+		"dict": func(v ...interface{}) map[string]interface{} {
+			m := make(map[string]interface{})
+			for i := 0; i+1 < len(v); i += 2 {
+				m[helmString(v[i])] = v[i+1]
+			}
+			return m
+		},
+		"set": func(m map[string]interface{}, key string, v interface{}) map[string]interface{} {
+			m[key] = v
+			return m
+		},
+		"hasKey": func(m map[string]interface{}, key string) bool {
+			_, ok := m[key]
+			return ok
+		},
+		"b64enc": func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		},
+		"b64dec": func(s string) string {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return err.Error()
+			}
+			return string(b)
+		},
+		"sha256sum": func(s string) string {
+			sum := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(sum[:])
+		},
+	}
+}
+
+// helmEmpty reports if a value is considered empty by chart templates.
+func helmEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return val.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return val.Float() == 0
+	case reflect.Ptr, reflect.Interface:
+		return val.IsNil()
+	default:
+		return false
+	}
+}
+
+// helmString converts a value to a string.
+func helmString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
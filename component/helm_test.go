@@ -0,0 +1,162 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func stageHelmChart(t *testing.T, fs afero.Fs, paramsFile string) {
+	files := []string{"Chart.yaml", "values.yaml", "templates/_helpers.tpl",
+		"templates/deployment.yaml", "templates/service.yaml", "templates/NOTES.txt"}
+	for _, file := range files {
+		stageFile(t, fs, "helm/redis/"+file, "/components/redis/"+file)
+	}
+
+	stageFile(t, fs, paramsFile, "/components/params.libsonnet")
+}
+
+func TestHelm_Name(t *testing.T) {
+	app, fs := appMock("/")
+	stageHelmChart(t, fs, "helm/params.libsonnet")
+
+	h := NewHelm(app, "ns1", "/components/redis", "/components/params.libsonnet")
+
+	require.Equal(t, "redis", h.Name(false))
+	require.Equal(t, "ns1/redis", h.Name(true))
+}
+
+func TestHelm_Objects(t *testing.T) {
+	app, fs := appMock("/")
+	stageHelmChart(t, fs, "helm/params.libsonnet")
+
+	h := NewHelm(app, "", "/components/redis", "/components/params.libsonnet")
+
+	list, err := h.Objects("", "")
+	require.NoError(t, err)
+
+	expected := []*unstructured.Unstructured{
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1beta2",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":      "redis-redis",
+					"namespace": "default",
+					"labels": map[string]interface{}{
+						"app":   "redis-redis",
+						"chart": "redis-1.0.0",
+					},
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "redis",
+									"image": "redis:4.0.10",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]interface{}{
+					"name": "redis-redis",
+				},
+				"spec": map[string]interface{}{
+					"type": "ClusterIP",
+					"ports": []interface{}{
+						map[string]interface{}{
+							"port": int64(6379),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, expected, list)
+}
+
+func TestHelm_Params(t *testing.T) {
+	app, fs := appMock("/")
+	stageHelmChart(t, fs, "helm/params.libsonnet")
+
+	h := NewHelm(app, "", "/components/redis", "/components/params.libsonnet")
+
+	params, err := h.Params()
+	require.NoError(t, err)
+
+	expected := []NamespaceParameter{
+		{Component: "redis", Index: "0", Key: "image.tag", Value: `"4.0.10"`, RawValue: "4.0.10"},
+		{Component: "redis", Index: "0", Key: "replicas", Value: "3", RawValue: float64(3)},
+	}
+
+	require.Equal(t, expected, params)
+}
+
+func TestHelm_SetParam(t *testing.T) {
+	app, fs := appMock("/")
+	stageHelmChart(t, fs, "params-no-entry.libsonnet")
+
+	h := NewHelm(app, "", "/components/redis", "/components/params.libsonnet")
+
+	err := h.SetParam([]string{"service", "type"}, "NodePort", ParamOptions{})
+	require.NoError(t, err)
+
+	list, err := h.Objects("", "")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	spec, ok := list[1].Object["spec"].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "NodePort", spec["type"])
+
+	err = h.DeleteParam([]string{"service", "type"}, ParamOptions{})
+	require.NoError(t, err)
+
+	params, err := h.Params()
+	require.NoError(t, err)
+	require.Empty(t, params)
+}
+
+func TestHelm_Summarize(t *testing.T) {
+	app, fs := appMock("/")
+	stageHelmChart(t, fs, "helm/params.libsonnet")
+
+	h := NewHelm(app, "", "/components/redis", "/components/params.libsonnet")
+
+	list, err := h.Summarize()
+	require.NoError(t, err)
+
+	expected := []Summary{
+		{
+			ComponentName: "redis",
+			IndexStr:      "0",
+			Type:          "helm",
+			APIVersion:    "apps/v1beta2",
+			Kind:          "Deployment",
+			Name:          "redis-redis",
+		},
+		{
+			ComponentName: "redis",
+			IndexStr:      "1",
+			Index:         1,
+			Type:          "helm",
+			APIVersion:    "v1",
+			Kind:          "Service",
+			Name:          "redis-redis",
+		},
+	}
+
+	require.Equal(t, expected, list)
+}
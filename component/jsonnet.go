@@ -171,7 +171,12 @@ func (j *Jsonnet) SetParam(path []string, value interface{}, options ParamOption
 		return err
 	}
 
-	updatedParams, err := params.Set(path, paramsData, j.Name(false), value, paramsComponentRoot)
+	schema, err := readSchema(j.app.Fs(), j.paramsPath)
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Set(path, paramsData, j.Name(false), value, paramsComponentRoot, params.WithSchema(schema), params.WithForce(options.Force))
 	if err != nil {
 		return err
 	}
@@ -214,9 +219,14 @@ func (j *Jsonnet) Params() ([]NamespaceParameter, error) {
 		return nil, errors.Wrap(err, "could not find components")
 	}
 
+	computed, err := params.Computed(j.Name(false), paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	var params []NamespaceParameter
 	for k, v := range props {
-		vStr, err := j.paramValue(v)
+		vStr, err := paramValue(v)
 		if err != nil {
 			return nil, err
 		}
@@ -225,6 +235,8 @@ func (j *Jsonnet) Params() ([]NamespaceParameter, error) {
 			Key:       k,
 			Index:     "0",
 			Value:     vStr,
+			RawValue:  v,
+			Computed:  computed.Has([]string{k}),
 		}
 
 		params = append(params, np)
@@ -237,7 +249,9 @@ func (j *Jsonnet) Params() ([]NamespaceParameter, error) {
 	return params, nil
 }
 
-func (j *Jsonnet) paramValue(v interface{}) (string, error) {
+// paramValue formats a param value for display. Strings are quoted and
+// maps and arrays are JSON encoded.
+func paramValue(v interface{}) (string, error) {
 	switch v.(type) {
 	default:
 		s := fmt.Sprintf("%v", v)
@@ -141,48 +141,96 @@ func TestJsonnet_Params(t *testing.T) {
 			Index:     "0",
 			Key:       "containerPort",
 			Value:     "80",
+			RawValue:  float64(80),
 		},
 		{
 			Component: "guestbook-ui",
 			Index:     "0",
 			Key:       "image",
 			Value:     `"gcr.io/heptio-images/ks-guestbook-demo:0.1"`,
+			RawValue:  "gcr.io/heptio-images/ks-guestbook-demo:0.1",
 		},
 		{
 			Component: "guestbook-ui",
 			Index:     "0",
 			Key:       "name",
 			Value:     `"guiroot"`,
+			RawValue:  "guiroot",
 		},
 		{
 			Component: "guestbook-ui",
 			Index:     "0",
 			Key:       "obj",
 			Value:     `{"a":"b"}`,
+			RawValue:  map[string]interface{}{"a": "b"},
 		},
 		{
 			Component: "guestbook-ui",
 			Index:     "0",
 			Key:       "replicas",
 			Value:     "1",
+			RawValue:  float64(1),
 		},
 		{
 			Component: "guestbook-ui",
 			Index:     "0",
 			Key:       "servicePort",
 			Value:     "80",
+			RawValue:  float64(80),
 		},
 		{
 			Component: "guestbook-ui",
 			Index:     "0",
 			Key:       "type",
 			Value:     `"ClusterIP"`,
+			RawValue:  "ClusterIP",
 		},
 	}
 
 	require.Equal(t, expected, params)
 }
 
+func TestJsonnet_Params_computed(t *testing.T) {
+	app, fs := appMock("/")
+
+	files := []string{"guestbook-ui.jsonnet", "k.libsonnet", "k8s.libsonnet"}
+	for _, file := range files {
+		stageFile(t, fs, "guestbook/"+file, "/components/"+file)
+	}
+	stageFile(t, fs, "guestbook/computed-params.libsonnet", "/components/params.libsonnet")
+
+	c := NewJsonnet(app, "", "/components/guestbook-ui.jsonnet", "/components/params.libsonnet")
+
+	params, err := c.Params()
+	require.NoError(t, err)
+
+	expected := []NamespaceParameter{
+		{
+			Component: "guestbook-ui",
+			Index:     "0",
+			Key:       "containerPort",
+			Value:     "80",
+			RawValue:  float64(80),
+			Computed:  true,
+		},
+		{
+			Component: "guestbook-ui",
+			Index:     "0",
+			Key:       "replicas",
+			Value:     "1",
+			RawValue:  float64(1),
+		},
+	}
+
+	require.Equal(t, expected, params)
+
+	err = c.SetParam([]string{"containerPort"}, 8080, ParamOptions{})
+	require.Error(t, err)
+
+	err = c.SetParam([]string{"containerPort"}, 8080, ParamOptions{Force: true})
+	require.NoError(t, err)
+}
+
 func TestJsonnet_Summarize(t *testing.T) {
 	app, fs := appMock("/")
 
@@ -0,0 +1,800 @@
+package component
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bryanl/woowoo/k8sutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/bryanl/woowoo/pkg/util/merge"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	amyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// kustomizationFile is the file which identifies a directory as a kustomization.
+	kustomizationFile = "kustomization.yaml"
+)
+
+// isKustomization reports if a directory contains a kustomization.
+func isKustomization(fs afero.Fs, dir string) (bool, error) {
+	return afero.Exists(fs, filepath.Join(dir, kustomizationFile))
+}
+
+// Kustomization is the contents of a kustomization.yaml.
+type Kustomization struct {
+	Resources             []string                 `json:"resources,omitempty"`
+	Bases                 []string                 `json:"bases,omitempty"`
+	PatchesStrategicMerge []string                 `json:"patchesStrategicMerge,omitempty"`
+	PatchesJSON6902       []KustomizeJSONPatch     `json:"patchesJson6902,omitempty"`
+	Namespace             string                   `json:"namespace,omitempty"`
+	NamePrefix            string                   `json:"namePrefix,omitempty"`
+	NameSuffix            string                   `json:"nameSuffix,omitempty"`
+	CommonLabels          map[string]string        `json:"commonLabels,omitempty"`
+	CommonAnnotations     map[string]string        `json:"commonAnnotations,omitempty"`
+	ConfigMapGenerator    []KustomizeConfigMapArgs `json:"configMapGenerator,omitempty"`
+	GeneratorOptions      *KustomizeGeneratorOpts  `json:"generatorOptions,omitempty"`
+}
+
+// KustomizeJSONPatch is a JSON patch (RFC 6902) which targets a single object.
+type KustomizeJSONPatch struct {
+	Target KustomizeTarget `json:"target"`
+	Path   string          `json:"path"`
+}
+
+// KustomizeTarget selects an object.
+type KustomizeTarget struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// KustomizeConfigMapArgs describes a generated ConfigMap.
+type KustomizeConfigMapArgs struct {
+	Name     string   `json:"name"`
+	Behavior string   `json:"behavior,omitempty"`
+	Literals []string `json:"literals,omitempty"`
+	Files    []string `json:"files,omitempty"`
+	Env      string   `json:"env,omitempty"`
+}
+
+// KustomizeGeneratorOpts are options for generated objects.
+type KustomizeGeneratorOpts struct {
+	DisableNameSuffixHash bool `json:"disableNameSuffixHash,omitempty"`
+}
+
+// Kustomize is a component based on a kustomization.yaml in a directory within a
+// component namespace. The overlay is evaluated locally. Like YAML components, params
+// are keyed like `name-id` where id is the position of the object in the output.
+type Kustomize struct {
+	app        app.App
+	nsName     string
+	source     string
+	paramsPath string
+}
+
+var _ Component = (*Kustomize)(nil)
+
+// NewKustomize creates an instance of Kustomize. `source` is the directory containing
+// kustomization.yaml.
+func NewKustomize(a app.App, nsName, source, paramsPath string) *Kustomize {
+	return &Kustomize{
+		app:        a,
+		nsName:     nsName,
+		source:     source,
+		paramsPath: paramsPath,
+	}
+}
+
+// Name is the name of this component. It is the name of the kustomization directory.
+func (k *Kustomize) Name(wantsNameSpaced bool) string {
+	name := filepath.Base(k.source)
+	if !wantsNameSpaced {
+		return name
+	}
+
+	return strings.TrimPrefix(path.Join(k.nsName, name), "/")
+}
+
+// Objects evaluates the kustomization and converts it to a slice of apimachinery
+// unstructured objects.
+func (k *Kustomize) Objects(paramsStr, envName string) ([]*unstructured.Unstructured, error) {
+	if paramsStr == "" {
+		var err error
+		if paramsStr, err = k.readParams(); err != nil {
+			return nil, err
+		}
+	}
+
+	objects, err := k.build(k.source)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []runtime.Object
+	for i, object := range objects {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+
+		componentName := fmt.Sprintf("%s-%d", k.Name(false), i)
+		patched, err := patchJSON(string(data), paramsStr, componentName)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, _, err := unstructured.UnstructuredJSONScheme.Decode([]byte(patched), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, obj)
+	}
+
+	return k8sutil.FlattenToV1(ret)
+}
+
+// build evaluates the kustomization in a directory. Bases are evaluated recursively.
+func (k *Kustomize) build(dir string) ([]map[string]interface{}, error) {
+	kustomization, err := k.readKustomization(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]interface{}
+	for _, resource := range append(kustomization.Bases, kustomization.Resources...) {
+		resourcePath := filepath.Join(dir, resource)
+
+		isDir, err := afero.DirExists(k.app.Fs(), resourcePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if isDir {
+			children, err := k.build(resourcePath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "build %s", resource)
+			}
+			objects = append(objects, children...)
+			continue
+		}
+
+		children, err := k.readObjects(resourcePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read resource %s", resource)
+		}
+		objects = append(objects, children...)
+	}
+
+	hashed := make(map[string]bool)
+	for _, args := range kustomization.ConfigMapGenerator {
+		if objects, err = k.generateConfigMap(dir, args, objects); err != nil {
+			return nil, errors.Wrapf(err, "generate config map %s", args.Name)
+		}
+
+		if kustomization.GeneratorOptions == nil || !kustomization.GeneratorOptions.DisableNameSuffixHash {
+			hashed[args.Name] = true
+		}
+	}
+
+	for _, patchFile := range kustomization.PatchesStrategicMerge {
+		patches, err := k.readObjects(filepath.Join(dir, patchFile))
+		if err != nil {
+			return nil, errors.Wrapf(err, "read patch %s", patchFile)
+		}
+
+		for _, patch := range patches {
+			if objects, err = applyStrategicMergePatch(objects, patch); err != nil {
+				return nil, errors.Wrapf(err, "apply patch %s", patchFile)
+			}
+		}
+	}
+
+	for _, jp := range kustomization.PatchesJSON6902 {
+		b, err := afero.ReadFile(k.app.Fs(), filepath.Join(dir, jp.Path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "read patch %s", jp.Path)
+		}
+
+		var ops []jsonPatchOperation
+		if err = yaml.Unmarshal(b, &ops); err != nil {
+			return nil, errors.Wrapf(err, "decode patch %s", jp.Path)
+		}
+
+		if objects, err = applyTargetedJSONPatch(objects, jp.Target, ops); err != nil {
+			return nil, errors.Wrapf(err, "apply patch %s", jp.Path)
+		}
+	}
+
+	renames := make(map[string]string)
+	for _, obj := range objects {
+		if kindOf(obj) != "ConfigMap" {
+			continue
+		}
+
+		name := nameOf(obj)
+		newName := name
+		if hashed[name] {
+			hash, err := configMapHash(obj)
+			if err != nil {
+				return nil, err
+			}
+			newName = fmt.Sprintf("%s-%s", name, hash)
+		}
+
+		newName = kustomization.NamePrefix + newName + kustomization.NameSuffix
+		if newName != name {
+			renames[name] = newName
+		}
+	}
+
+	for _, obj := range objects {
+		kind := kindOf(obj)
+		metadata := childMap(obj, "metadata")
+
+		if kind == "ConfigMap" {
+			if newName, ok := renames[nameOf(obj)]; ok {
+				metadata["name"] = newName
+			}
+		} else if kind != "Namespace" && kind != "CustomResourceDefinition" {
+			metadata["name"] = kustomization.NamePrefix + nameOf(obj) + kustomization.NameSuffix
+		}
+
+		if kustomization.Namespace != "" && !isClusterScoped(kind) {
+			metadata["namespace"] = kustomization.Namespace
+		}
+
+		addCommonLabels(obj, kustomization.CommonLabels)
+		addCommonAnnotations(obj, kustomization.CommonAnnotations)
+		renameConfigMapRefs(obj, renames)
+	}
+
+	return objects, nil
+}
+
+func (k *Kustomize) readKustomization(dir string) (*Kustomization, error) {
+	b, err := afero.ReadFile(k.app.Fs(), filepath.Join(dir, kustomizationFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "read kustomization")
+	}
+
+	var kustomization Kustomization
+	if err = yaml.Unmarshal(b, &kustomization); err != nil {
+		return nil, errors.Wrapf(err, "decode kustomization in %s", dir)
+	}
+
+	return &kustomization, nil
+}
+
+// readObjects reads the objects in a YAML or JSON file.
+func (k *Kustomize) readObjects(fileName string) ([]map[string]interface{}, error) {
+	f, err := k.app.Fs().Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	decoder := amyaml.NewYAMLReader(bufio.NewReader(f))
+
+	var objects []map[string]interface{}
+	for {
+		b, err := decoder.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+
+		var obj map[string]interface{}
+		if err = yaml.Unmarshal(b, &obj); err != nil {
+			return nil, err
+		}
+
+		if obj == nil {
+			continue
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// generateConfigMap generates a ConfigMap. Depending on the behavior, the ConfigMap
+// is created, or it is merged with or replaces an existing ConfigMap from a base.
+func (k *Kustomize) generateConfigMap(dir string, args KustomizeConfigMapArgs, objects []map[string]interface{}) ([]map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	for _, literal := range args.Literals {
+		parts := strings.SplitN(literal, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("literal %q is not in key=value format", literal)
+		}
+		data[parts[0]] = strings.Trim(parts[1], `"'`)
+	}
+
+	for _, file := range args.Files {
+		key, filePath := filepath.Base(file), file
+		if parts := strings.SplitN(file, "=", 2); len(parts) == 2 {
+			key, filePath = parts[0], parts[1]
+		}
+
+		b, err := afero.ReadFile(k.app.Fs(), filepath.Join(dir, filePath))
+		if err != nil {
+			return nil, err
+		}
+		data[key] = string(b)
+	}
+
+	if args.Env != "" {
+		b, err := afero.ReadFile(k.app.Fs(), filepath.Join(dir, args.Env))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range strings.Split(string(b), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				return nil, errors.Errorf("env line %q is not in key=value format", line)
+			}
+			data[parts[0]] = parts[1]
+		}
+	}
+
+	behavior := args.Behavior
+	if behavior == "" {
+		behavior = "create"
+	}
+
+	for _, obj := range objects {
+		if kindOf(obj) != "ConfigMap" || nameOf(obj) != args.Name {
+			continue
+		}
+
+		switch behavior {
+		case "create":
+			return nil, errors.Errorf("config map %q already exists", args.Name)
+		case "merge":
+			existing := childMap(obj, "data")
+			for key, value := range data {
+				existing[key] = value
+			}
+		case "replace":
+			obj["data"] = data
+		default:
+			return nil, errors.Errorf("unknown behavior %q", behavior)
+		}
+
+		return objects, nil
+	}
+
+	if behavior != "create" {
+		return nil, errors.Errorf("unable to %s config map %q: it does not exist", behavior, args.Name)
+	}
+
+	cm := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name": args.Name,
+		},
+		"data": data,
+	}
+
+	return append(objects, cm), nil
+}
+
+// Params returns params for a component.
+func (k *Kustomize) Params() ([]NamespaceParameter, error) {
+	paramsData, err := k.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := params.ToMap("", paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find components")
+	}
+
+	computed, err := params.Computed("", paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(fmt.Sprintf(`^%s-(\d+)$`, regexp.QuoteMeta(k.Name(false))))
+	if err != nil {
+		return nil, err
+	}
+
+	var params []NamespaceParameter
+	for componentName, componentValue := range props {
+		matches := re.FindStringSubmatch(componentName)
+		if len(matches) == 0 {
+			continue
+		}
+
+		m, ok := componentValue.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("component value for %q was not a map", componentName)
+		}
+
+		for _, pp := range mapToPaths(m, nil, nil) {
+			vStr, err := paramValue(pp.value)
+			if err != nil {
+				return nil, err
+			}
+
+			np := NamespaceParameter{
+				Component: k.Name(false),
+				Index:     matches[1],
+				Key:       strings.Join(pp.path, "."),
+				Value:     vStr,
+				RawValue:  pp.value,
+				Computed:  computed.Child(componentName).Has(pp.path),
+			}
+
+			params = append(params, np)
+		}
+	}
+
+	sortParams(params)
+
+	return params, nil
+}
+
+// SetParam set parameter for a component.
+func (k *Kustomize) SetParam(path []string, value interface{}, options ParamOptions) error {
+	entry := fmt.Sprintf("%s-%d", k.Name(false), options.Index)
+	paramsData, err := k.readParams()
+	if err != nil {
+		return err
+	}
+
+	schema, err := readSchema(k.app.Fs(), k.paramsPath)
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Set(path, paramsData, entry, value, paramsComponentRoot, params.WithSchema(schema), params.WithForce(options.Force))
+	if err != nil {
+		return err
+	}
+
+	return k.writeParams(updatedParams)
+}
+
+// DeleteParam deletes a param.
+func (k *Kustomize) DeleteParam(path []string, options ParamOptions) error {
+	entry := fmt.Sprintf("%s-%d", k.Name(false), options.Index)
+	paramsData, err := k.readParams()
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Delete(path, paramsData, entry, paramsComponentRoot)
+	if err != nil {
+		return err
+	}
+
+	return k.writeParams(updatedParams)
+}
+
+// Summarize generates a summary for the component. It returns a summary for
+// each object the kustomization generates.
+func (k *Kustomize) Summarize() ([]Summary, error) {
+	objects, err := k.build(k.source)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for i, obj := range objects {
+		apiVersion, _ := obj["apiVersion"].(string)
+
+		summary := Summary{
+			ComponentName: k.Name(false),
+			IndexStr:      strconv.Itoa(i),
+			Index:         i,
+			Type:          "kustomize",
+			APIVersion:    apiVersion,
+			Kind:          kindOf(obj),
+			Name:          nameOf(obj),
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+func (k *Kustomize) readParams() (string, error) {
+	b, err := afero.ReadFile(k.app.Fs(), k.paramsPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (k *Kustomize) writeParams(src string) error {
+	return afero.WriteFile(k.app.Fs(), k.paramsPath, []byte(src), 0644)
+}
+
+// applyStrategicMergePatch applies a patch to the object with the same kind and name.
+func applyStrategicMergePatch(objects []map[string]interface{}, patch map[string]interface{}) ([]map[string]interface{}, error) {
+	target := KustomizeTarget{
+		Kind:      kindOf(patch),
+		Name:      nameOf(patch),
+		Namespace: namespaceOf(patch),
+	}
+
+	i, err := findTarget(objects, target)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := merge.Strategic(objects[i], patch)
+	if err != nil {
+		return nil, err
+	}
+
+	objects[i] = patched
+	return objects, nil
+}
+
+// applyTargetedJSONPatch applies JSON patch operations to the targeted object.
+func applyTargetedJSONPatch(objects []map[string]interface{}, target KustomizeTarget, ops []jsonPatchOperation) ([]map[string]interface{}, error) {
+	i, err := findTarget(objects, target)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := applyJSONPatch(objects[i], ops)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := patched.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("patched object is not an object")
+	}
+
+	objects[i] = m
+	return objects, nil
+}
+
+func findTarget(objects []map[string]interface{}, target KustomizeTarget) (int, error) {
+	for i, obj := range objects {
+		if kindOf(obj) != target.Kind || nameOf(obj) != target.Name {
+			continue
+		}
+
+		if target.Namespace != "" && namespaceOf(obj) != target.Namespace {
+			continue
+		}
+
+		apiVersion, _ := obj["apiVersion"].(string)
+		group, version := "", apiVersion
+		if parts := strings.SplitN(apiVersion, "/", 2); len(parts) == 2 {
+			group, version = parts[0], parts[1]
+		}
+
+		if target.Group != "" && target.Group != group {
+			continue
+		}
+
+		if target.Version != "" && target.Version != version {
+			continue
+		}
+
+		return i, nil
+	}
+
+	return 0, errors.Errorf("unable to find %s %q to patch", target.Kind, target.Name)
+}
+
+// configMapHash creates a hash of the contents of a config map.
+func configMapHash(obj map[string]interface{}) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"kind": kindOf(obj),
+		"name": nameOf(obj),
+		"data": obj["data"],
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:10], nil
+}
+
+var (
+	// clusterScopedKinds are kinds which are not namespaced.
+	clusterScopedKinds = map[string]bool{
+		"APIService":                     true,
+		"ClusterRole":                    true,
+		"ClusterRoleBinding":             true,
+		"CustomResourceDefinition":       true,
+		"MutatingWebhookConfiguration":   true,
+		"Namespace":                      true,
+		"PersistentVolume":               true,
+		"PodSecurityPolicy":              true,
+		"PriorityClass":                  true,
+		"StorageClass":                   true,
+		"ValidatingWebhookConfiguration": true,
+	}
+
+	// selectorKinds are kinds with a label selector and a pod template.
+	selectorKinds = map[string]bool{
+		"DaemonSet":   true,
+		"Deployment":  true,
+		"ReplicaSet":  true,
+		"StatefulSet": true,
+	}
+)
+
+func isClusterScoped(kind string) bool {
+	return clusterScopedKinds[kind]
+}
+
+func addCommonLabels(obj map[string]interface{}, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+
+	setStrings(childMap(childMap(obj, "metadata"), "labels"), labels)
+
+	kind := kindOf(obj)
+	switch {
+	case kind == "Service":
+		setStrings(childMap(childMap(obj, "spec"), "selector"), labels)
+	case selectorKinds[kind]:
+		spec := childMap(obj, "spec")
+		setStrings(childMap(childMap(spec, "selector"), "matchLabels"), labels)
+		setStrings(childMap(childMap(childMap(spec, "template"), "metadata"), "labels"), labels)
+	case kind == "Job":
+		spec := childMap(obj, "spec")
+		setStrings(childMap(childMap(childMap(spec, "template"), "metadata"), "labels"), labels)
+	}
+}
+
+func addCommonAnnotations(obj map[string]interface{}, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+
+	setStrings(childMap(childMap(obj, "metadata"), "annotations"), annotations)
+
+	if kind := kindOf(obj); selectorKinds[kind] || kind == "Job" {
+		spec := childMap(obj, "spec")
+		setStrings(childMap(childMap(childMap(spec, "template"), "metadata"), "annotations"), annotations)
+	}
+}
+
+// renameConfigMapRefs updates references to renamed config maps in pod specs.
+func renameConfigMapRefs(obj map[string]interface{}, renames map[string]string) {
+	if len(renames) == 0 {
+		return
+	}
+
+	var podSpec map[string]interface{}
+	switch kind := kindOf(obj); {
+	case kind == "Pod":
+		podSpec = childMap(obj, "spec")
+	case kind == "CronJob":
+		podSpec = childMap(childMap(childMap(childMap(childMap(obj, "spec"), "jobTemplate"), "spec"), "template"), "spec")
+	case selectorKinds[kind] || kind == "Job":
+		podSpec = childMap(childMap(childMap(obj, "spec"), "template"), "spec")
+	default:
+		return
+	}
+
+	rename := func(m map[string]interface{}) {
+		if m == nil {
+			return
+		}
+		if name, ok := m["name"].(string); ok {
+			if newName, ok := renames[name]; ok {
+				m["name"] = newName
+			}
+		}
+	}
+
+	for _, volume := range mapSlice(podSpec["volumes"]) {
+		rename(optionalMap(volume, "configMap"))
+		for _, source := range mapSlice(optionalMap(volume, "projected")["sources"]) {
+			rename(optionalMap(source, "configMap"))
+		}
+	}
+
+	containers := append(mapSlice(podSpec["containers"]), mapSlice(podSpec["initContainers"])...)
+	for _, container := range containers {
+		for _, envFrom := range mapSlice(container["envFrom"]) {
+			rename(optionalMap(envFrom, "configMapRef"))
+		}
+		for _, env := range mapSlice(container["env"]) {
+			rename(optionalMap(optionalMap(env, "valueFrom"), "configMapKeyRef"))
+		}
+	}
+}
+
+func kindOf(obj map[string]interface{}) string {
+	kind, _ := obj["kind"].(string)
+	return kind
+}
+
+func nameOf(obj map[string]interface{}) string {
+	name, _ := optionalMap(obj, "metadata")["name"].(string)
+	return name
+}
+
+func namespaceOf(obj map[string]interface{}) string {
+	namespace, _ := optionalMap(obj, "metadata")["namespace"].(string)
+	return namespace
+}
+
+// childMap returns the map at key, creating it if it does not exist.
+func childMap(m map[string]interface{}, key string) map[string]interface{} {
+	child, ok := m[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		m[key] = child
+	}
+
+	return child
+}
+
+// optionalMap returns the map at key, or nil if it does not exist.
+func optionalMap(m map[string]interface{}, key string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	child, _ := m[key].(map[string]interface{})
+	return child
+}
+
+func mapSlice(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{})
+
+	var out []map[string]interface{}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			out = append(out, m)
+		}
+	}
+
+	return out
+}
+
+func setStrings(m map[string]interface{}, values map[string]string) {
+	for k, v := range values {
+		m[k] = v
+	}
+}
@@ -0,0 +1,277 @@
+package component
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// jsonPatchOperation is a JSON patch (RFC 6902) operation.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// applyJSONPatch applies JSON patch (RFC 6902) operations to a document.
+func applyJSONPatch(doc interface{}, ops []jsonPatchOperation) (interface{}, error) {
+	var err error
+	for _, op := range ops {
+		switch op.Op {
+		case "add":
+			doc, err = jsonPointerSet(doc, op.Path, op.Value, true)
+		case "remove":
+			doc, _, err = jsonPointerRemove(doc, op.Path)
+		case "replace":
+			if _, err = jsonPointerGet(doc, op.Path); err == nil {
+				doc, err = jsonPointerSet(doc, op.Path, op.Value, false)
+			}
+		case "move":
+			var v interface{}
+			doc, v, err = jsonPointerRemove(doc, op.From)
+			if err == nil {
+				doc, err = jsonPointerSet(doc, op.Path, v, true)
+			}
+		case "copy":
+			var v interface{}
+			v, err = jsonPointerGet(doc, op.From)
+			if err == nil {
+				doc, err = jsonPointerSet(doc, op.Path, deepCopyJSON(v), true)
+			}
+		case "test":
+			var v interface{}
+			v, err = jsonPointerGet(doc, op.Path)
+			if err == nil && !jsonEqual(v, op.Value) {
+				err = errors.Errorf("test failed at %s", op.Path)
+			}
+		default:
+			err = errors.Errorf("unknown operation %q", op.Op)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %s", op.Op, op.Path)
+		}
+	}
+
+	return doc, nil
+}
+
+// parseJSONPointer splits a JSON pointer (RFC 6901) into its reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	parts := strings.Split(pointer[1:], "/")
+	for i := range parts {
+		parts[i] = strings.Replace(parts[i], "~1", "/", -1)
+		parts[i] = strings.Replace(parts[i], "~0", "~", -1)
+	}
+
+	return parts, nil
+}
+
+func jsonPointerGet(doc interface{}, pointer string) (interface{}, error) {
+	parts, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := doc
+	for _, part := range parts {
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			v, ok := t[part]
+			if !ok {
+				return nil, errors.Errorf("%q was not found", part)
+			}
+			cur = v
+		case []interface{}:
+			i, err := arrayIndex(part, len(t), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = t[i]
+		default:
+			return nil, errors.Errorf("unable to traverse %T at %q", cur, part)
+		}
+	}
+
+	return cur, nil
+}
+
+// jsonPointerSet sets a value at a pointer. When insert is true, values are
+// inserted into arrays rather than replacing the existing element.
+func jsonPointerSet(doc interface{}, pointer string, value interface{}, insert bool) (interface{}, error) {
+	parts, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 0 {
+		return value, nil
+	}
+
+	return setPointerParts(doc, parts, value, insert)
+}
+
+func setPointerParts(cur interface{}, parts []string, value interface{}, insert bool) (interface{}, error) {
+	part := parts[0]
+	last := len(parts) == 1
+
+	switch t := cur.(type) {
+	case map[string]interface{}:
+		if last {
+			t[part] = value
+			return t, nil
+		}
+
+		child, ok := t[part]
+		if !ok {
+			return nil, errors.Errorf("%q was not found", part)
+		}
+
+		updated, err := setPointerParts(child, parts[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		t[part] = updated
+		return t, nil
+	case []interface{}:
+		if last && insert {
+			i, err := arrayIndex(part, len(t), true)
+			if err != nil {
+				return nil, err
+			}
+
+			t = append(t, nil)
+			copy(t[i+1:], t[i:])
+			t[i] = value
+			return t, nil
+		}
+
+		i, err := arrayIndex(part, len(t), false)
+		if err != nil {
+			return nil, err
+		}
+
+		if last {
+			t[i] = value
+			return t, nil
+		}
+
+		updated, err := setPointerParts(t[i], parts[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		t[i] = updated
+		return t, nil
+	default:
+		return nil, errors.Errorf("unable to traverse %T at %q", cur, part)
+	}
+}
+
+func jsonPointerRemove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	parts, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(parts) == 0 {
+		return nil, nil, errors.New("unable to remove the document root")
+	}
+
+	parentPointer := ""
+	if len(parts) > 1 {
+		parentPointer = pointer[:strings.LastIndex(pointer, "/")]
+	}
+
+	parent, err := jsonPointerGet(doc, parentPointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	part := parts[len(parts)-1]
+
+	var removed, updated interface{}
+	switch t := parent.(type) {
+	case map[string]interface{}:
+		v, ok := t[part]
+		if !ok {
+			return nil, nil, errors.Errorf("%q was not found", part)
+		}
+		removed = v
+		delete(t, part)
+		updated = t
+	case []interface{}:
+		i, err := arrayIndex(part, len(t), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		removed = t[i]
+		updated = append(t[:i:i], t[i+1:]...)
+	default:
+		return nil, nil, errors.Errorf("unable to traverse %T at %q", parent, part)
+	}
+
+	if parentPointer == "" {
+		return updated, removed, nil
+	}
+
+	doc, err = jsonPointerSet(doc, parentPointer, updated, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, removed, nil
+}
+
+// arrayIndex converts a pointer token to an array index. `-` refers to the end of
+// the array and is only valid when appending.
+func arrayIndex(part string, length int, appending bool) (int, error) {
+	if part == "-" && appending {
+		return length, nil
+	}
+
+	i, err := strconv.Atoi(part)
+	if err != nil {
+		return 0, errors.Errorf("invalid array index %q", part)
+	}
+
+	max := length - 1
+	if appending {
+		max = length
+	}
+
+	if i < 0 || i > max {
+		return 0, errors.Errorf("array index %d is out of bounds", i)
+	}
+
+	return i, nil
+}
+
+func deepCopyJSON(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+
+	return out
+}
+
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(deepCopyJSON(a), deepCopyJSON(b))
+}
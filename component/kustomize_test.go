@@ -0,0 +1,187 @@
+package component
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func stageKustomization(t *testing.T, fs afero.Fs, paramsFile string) {
+	files := []string{"kustomization.yaml", "image.yaml", "replicas.yaml",
+		"base/kustomization.yaml", "base/deployment.yaml", "base/service.yaml"}
+	for _, file := range files {
+		stageFile(t, fs, "kustomize/web/"+file, "/components/web/"+file)
+	}
+
+	stageFile(t, fs, paramsFile, "/components/params.libsonnet")
+}
+
+func TestKustomize_Name(t *testing.T) {
+	app, fs := appMock("/")
+	stageKustomization(t, fs, "kustomize/params.libsonnet")
+
+	k := NewKustomize(app, "ns1", "/components/web", "/components/params.libsonnet")
+
+	require.Equal(t, "web", k.Name(false))
+	require.Equal(t, "ns1/web", k.Name(true))
+}
+
+func TestKustomize_Objects(t *testing.T) {
+	app, fs := appMock("/")
+	stageKustomization(t, fs, "kustomize/params.libsonnet")
+
+	k := NewKustomize(app, "", "/components/web", "/components/params.libsonnet")
+
+	list, err := k.Objects("", "")
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	cm := list[2]
+	require.Equal(t, "ConfigMap", cm.GetKind())
+	require.Regexp(t, regexp.MustCompile(`^prod-web-config-[0-9a-f]{10}$`), cm.GetName())
+	require.Equal(t, "production", cm.GetNamespace())
+	require.Equal(t, map[string]string{"env": "prod"}, cm.GetLabels())
+
+	expected := map[string]interface{}{
+		"apiVersion": "apps/v1beta2",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "prod-web",
+			"namespace": "production",
+			"labels": map[string]interface{}{
+				"env": "prod",
+			},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					"app": "web",
+					"env": "prod",
+				},
+			},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"app": "web",
+						"env": "prod",
+					},
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "web",
+							"image": "nginx:1.15",
+							"envFrom": []interface{}{
+								map[string]interface{}{
+									"configMapRef": map[string]interface{}{
+										"name": cm.GetName(),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, list[0].Object)
+
+	service := list[1]
+	require.Equal(t, "prod-web", service.GetName())
+
+	spec, ok := service.Object["spec"].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "NodePort", spec["type"])
+	require.Equal(t, map[string]interface{}{"app": "web", "env": "prod"}, spec["selector"])
+}
+
+func TestKustomize_Params(t *testing.T) {
+	app, fs := appMock("/")
+	stageKustomization(t, fs, "kustomize/params.libsonnet")
+
+	k := NewKustomize(app, "", "/components/web", "/components/params.libsonnet")
+
+	params, err := k.Params()
+	require.NoError(t, err)
+
+	expected := []NamespaceParameter{
+		{Component: "web", Index: "1", Key: "spec.type", Value: `"NodePort"`, RawValue: "NodePort"},
+	}
+
+	require.Equal(t, expected, params)
+}
+
+func TestKustomize_SetParam(t *testing.T) {
+	app, fs := appMock("/")
+	stageKustomization(t, fs, "params-no-entry.libsonnet")
+
+	k := NewKustomize(app, "", "/components/web", "/components/params.libsonnet")
+
+	err := k.SetParam([]string{"spec", "replicas"}, 5, ParamOptions{Index: 0})
+	require.NoError(t, err)
+
+	list, err := k.Objects("", "")
+	require.NoError(t, err)
+
+	spec, ok := list[0].Object["spec"].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, int64(5), spec["replicas"])
+
+	err = k.DeleteParam([]string{"spec", "replicas"}, ParamOptions{Index: 0})
+	require.NoError(t, err)
+
+	params, err := k.Params()
+	require.NoError(t, err)
+	require.Empty(t, params)
+}
+
+func TestKustomize_Summarize(t *testing.T) {
+	app, fs := appMock("/")
+	stageKustomization(t, fs, "kustomize/params.libsonnet")
+
+	k := NewKustomize(app, "", "/components/web", "/components/params.libsonnet")
+
+	list, err := k.Summarize()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	require.Equal(t, Summary{
+		ComponentName: "web",
+		IndexStr:      "0",
+		Type:          "kustomize",
+		APIVersion:    "apps/v1beta2",
+		Kind:          "Deployment",
+		Name:          "prod-web",
+	}, list[0])
+	require.Equal(t, "ConfigMap", list[2].Kind)
+}
+
+func Test_applyJSONPatch(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": []interface{}{"x", "y"},
+		"b": map[string]interface{}{"c": "d"},
+	}
+
+	ops := []jsonPatchOperation{
+		{Op: "add", Path: "/a/1", Value: "z"},
+		{Op: "add", Path: "/a/-", Value: "w"},
+		{Op: "remove", Path: "/b/c"},
+		{Op: "copy", From: "/a/0", Path: "/b/e"},
+		{Op: "test", Path: "/b/e", Value: "x"},
+	}
+
+	got, err := applyJSONPatch(doc, ops)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"a": []interface{}{"x", "z", "y", "w"},
+		"b": map[string]interface{}{"e": "x"},
+	}
+	require.Equal(t, expected, got)
+
+	_, err = applyJSONPatch(got, []jsonPatchOperation{{Op: "replace", Path: "/missing", Value: 1}})
+	require.Error(t, err)
+}
@@ -0,0 +1,328 @@
+package component
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bryanl/woowoo/params"
+	utilyaml "github.com/bryanl/woowoo/pkg/util/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// LintProblem is a problem found by Lint.
+type LintProblem struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+	// Fixable is true if the problem can be fixed by FixLint.
+	Fixable bool `json:"fixable"`
+
+	fix  func(src string) (string, error)
+	perm os.FileMode
+}
+
+// Lint checks the app's namespaces for params which don't belong to a
+// component, YAML params for documents which don't exist, and components which
+// share a name. Environment overrides are checked as well. Problems are sorted
+// by path and line.
+func Lint(a app.App) ([]LintProblem, error) {
+	namespaces, err := Namespaces(a)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{app: a}
+
+	var all []Component
+	for _, ns := range namespaces {
+		components, err := ns.Components()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, components...)
+
+		if err := l.duplicates(ns); err != nil {
+			return nil, err
+		}
+
+		src, err := ns.readParams()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := l.params(ns.ParamsPath(), src, components, 0644); err != nil {
+			return nil, err
+		}
+
+		secrets, err := ns.Secrets()
+		if err != nil {
+			return nil, err
+		}
+
+		if secrets != "" {
+			if err := l.params(ns.SecretsPath(), secrets, components, 0600); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	for envName := range envs {
+		if err := l.envParams(envName, all); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		if l.problems[i].Path != l.problems[j].Path {
+			return l.problems[i].Path < l.problems[j].Path
+		}
+		return l.problems[i].Line < l.problems[j].Line
+	})
+
+	return l.problems, nil
+}
+
+// FixLint fixes the problems which are fixable. Each changed file is written
+// once.
+func FixLint(a app.App, problems []LintProblem) error {
+	var paths []string
+	byPath := make(map[string][]LintProblem)
+	for _, p := range problems {
+		if !p.Fixable {
+			continue
+		}
+
+		if _, ok := byPath[p.Path]; !ok {
+			paths = append(paths, p.Path)
+		}
+		byPath[p.Path] = append(byPath[p.Path], p)
+	}
+
+	for _, path := range paths {
+		b, err := afero.ReadFile(a.Fs(), path)
+		if err != nil {
+			return err
+		}
+
+		src := string(b)
+		for _, p := range byPath[path] {
+			if src, err = p.fix(src); err != nil {
+				return errors.Wrapf(err, "fix %s:%d", p.Path, p.Line)
+			}
+		}
+
+		if err := afero.WriteFile(a.Fs(), path, []byte(src), byPath[path][0].perm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type linter struct {
+	app      app.App
+	problems []LintProblem
+}
+
+func (l *linter) add(p LintProblem) {
+	l.problems = append(l.problems, p)
+}
+
+// duplicates finds components in a namespace with the same name. They can't
+// be looked up by name.
+func (l *linter) duplicates(ns Namespace) error {
+	fis, err := afero.ReadDir(l.app.Fs(), ns.Dir())
+	if err != nil {
+		return err
+	}
+
+	sources := make(map[string][]string)
+	var names []string
+	for _, fi := range fis {
+		path := filepath.Join(ns.Dir(), fi.Name())
+
+		name := strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))
+		if fi.IsDir() {
+			ok, err := isPackagedComponent(l.app.Fs(), path)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				continue
+			}
+			name = fi.Name()
+		} else if !isComponent(path) {
+			continue
+		}
+
+		if _, ok := sources[name]; !ok {
+			names = append(names, name)
+		}
+		sources[name] = append(sources[name], path)
+	}
+
+	for _, name := range names {
+		if len(sources[name]) < 2 {
+			continue
+		}
+
+		for _, path := range sources[name] {
+			l.add(LintProblem{
+				Path:    path,
+				Message: fmt.Sprintf("component name %q is used by %d files", name, len(sources[name])),
+			})
+		}
+	}
+
+	return nil
+}
+
+// params checks the component entries in a namespace params or secrets file.
+func (l *linter) params(path, src string, components []Component, perm os.FileMode) error {
+	lines, err := params.Lines(src, paramsComponentRoot)
+	if err != nil {
+		return errors.Wrapf(err, "read %s", path)
+	}
+
+	for _, key := range sortedLineKeys(lines) {
+		msg, err := l.check(key, components)
+		if err != nil {
+			return err
+		}
+
+		if msg == "" {
+			continue
+		}
+
+		key := key
+		l.add(LintProblem{
+			Path:    path,
+			Line:    lines[key],
+			Message: msg,
+			Fixable: true,
+			fix: func(src string) (string, error) {
+				return params.Remove([]string{paramsComponentRoot, key}, src)
+			},
+			perm: perm,
+		})
+	}
+
+	return nil
+}
+
+// envParams checks the overrides in an environment. Overrides are keyed by
+// component name, so they are checked against the components in every
+// namespace. Environments without params are skipped.
+func (l *linter) envParams(envName string, components []Component) error {
+	exists, err := afero.Exists(l.app.Fs(), EnvParamsPath(l.app, envName))
+	if err != nil || !exists {
+		return err
+	}
+
+	envData, err := readEnvParams(l.app, envName)
+	if err != nil {
+		return err
+	}
+
+	lines, err := params.EnvLines(envData)
+	if err != nil {
+		return errors.Wrapf(err, "read %q environment params", envName)
+	}
+
+	for _, key := range sortedLineKeys(lines) {
+		msg, err := l.check(key, components)
+		if err != nil {
+			return err
+		}
+
+		if msg == "" {
+			continue
+		}
+
+		key := key
+		l.add(LintProblem{
+			Path:    EnvParamsPath(l.app, envName),
+			Line:    lines[key],
+			Message: msg,
+			Fixable: true,
+			fix: func(src string) (string, error) {
+				return params.RemoveEnv(src, key)
+			},
+			perm: 0644,
+		})
+	}
+
+	return nil
+}
+
+// check checks a params key against components. It returns a description of
+// the problem, or a blank string if any of the components accepts the key.
+func (l *linter) check(key string, components []Component) (string, error) {
+	var owner *YAML
+	var index string
+	for _, c := range components {
+		ok, err := acceptsParamsKey(c, key)
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			return "", nil
+		}
+
+		if owner != nil {
+			continue
+		}
+
+		// The key is for a document the YAML component doesn't have.
+		if _, i, ok := paramsKeyOwner([]Component{c}, key); ok {
+			owner, index = c.(*YAML), i
+		}
+	}
+
+	if owner == nil {
+		return fmt.Sprintf("params for %q don't belong to a component", key), nil
+	}
+
+	count, err := owner.documentCount()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("params for %q are for document %s, but %s has %d documents",
+		key, index, filepath.Base(owner.source), count), nil
+}
+
+// documentCount returns the number of documents in a YAML component.
+func (y *YAML) documentCount() (int, error) {
+	readers, err := utilyaml.Decode(y.app.Fs(), y.source)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(readers), nil
+}
+
+func sortedLineKeys(lines map[string]int) []string {
+	keys := make([]string, 0, len(lines))
+	for k := range lines {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return lines[keys[i]] < lines[keys[j]]
+	})
+
+	return keys
+}
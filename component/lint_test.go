@@ -0,0 +1,128 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "rbac.yaml", "/app/components/rbac.yaml")
+	stageFile(t, fs, "params-mixed.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "deployment.yaml", "/app/components/ns1/web.yaml")
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/ns1/web.jsonnet")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/ns1/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	b, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+	src, err := params.Update([]string{"components", "rbac-5"}, string(b), map[string]interface{}{"replicas": 1})
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/app/components/params.libsonnet", []byte(src), 0644))
+
+	b, err = afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+	envSrc, err := params.SetEnv([]string{"replicas"}, string(b), "ghost", 2)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/app/environments/default/params.libsonnet", []byte(envSrc), 0644))
+
+	problems, err := Lint(a)
+	require.NoError(t, err)
+
+	type problem struct {
+		path    string
+		line    int
+		fixable bool
+	}
+
+	var got []problem
+	for _, p := range problems {
+		got = append(got, problem{path: p.Path, line: p.Line, fixable: p.Fixable})
+	}
+
+	expected := []problem{
+		{path: "/app/components/ns1/web.jsonnet"},
+		{path: "/app/components/ns1/web.yaml"},
+		{path: "/app/components/params.libsonnet", line: 9, fixable: true},
+		{path: "/app/components/params.libsonnet", line: 22, fixable: true},
+		{path: "/app/environments/default/params.libsonnet", line: 5, fixable: true},
+	}
+	require.Equal(t, expected, got)
+	require.Equal(t, `params for "deployment-0" don't belong to a component`, problems[2].Message)
+	require.Equal(t, `params for "rbac-5" are for document 5, but rbac.yaml has 2 documents`, problems[3].Message)
+
+	require.NoError(t, FixLint(a, problems))
+
+	problems, err = Lint(a)
+	require.NoError(t, err)
+	require.Len(t, problems, 2)
+
+	b, err = afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+
+	m, err := params.ToMap("", string(b), "components")
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	require.Contains(t, m, "rbac-1")
+}
+
+func TestLint_env_without_params(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/guestbook-ui.jsonnet")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{
+		"default": &app.EnvironmentSpec{},
+		"prod":    &app.EnvironmentSpec{},
+	}, nil)
+
+	problems, err := Lint(a)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
+
+func TestLint_yaml_namesakes(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "deployment.yaml", "/app/components/web.yaml")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "rbac.yaml", "/app/components/apps/web.yaml")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/apps/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	b, err := afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+
+	envSrc := string(b)
+	for _, key := range []string{"web-0", "web-1", "web-2"} {
+		envSrc, err = params.SetEnv([]string{"replicas"}, envSrc, key, 2)
+		require.NoError(t, err)
+	}
+	require.NoError(t, afero.WriteFile(fs, "/app/environments/default/params.libsonnet", []byte(envSrc), 0644))
+
+	// web.yaml has one document, but apps/web.yaml has two, so only web-2
+	// doesn't belong to a component.
+	problems, err := Lint(a)
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	require.Equal(t, `params for "web-2" are for document 2, but web.yaml has 1 documents`, problems[0].Message)
+
+	require.NoError(t, FixLint(a, problems))
+
+	b, err = afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+
+	overrides, err := params.EnvToMap("", string(b))
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+	require.Contains(t, overrides, "web-0")
+	require.Contains(t, overrides, "web-1")
+}
@@ -0,0 +1,564 @@
+package component
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Move moves a component to another namespace and/or renames it. from and to
+// are namespaced component names. If to ends with a slash, it names a
+// namespace and the component keeps its name. The component's params,
+// secrets and environment overrides move with it. Params set by expressions
+// are moved as their values. Environments which target the component's old
+// namespace are updated to target its new one. The names of the updated
+// environments are returned. Changes to params files are planned before the
+// component is moved, so a move which fails leaves them unchanged.
+func Move(a app.App, from, to string) (Component, []string, error) {
+	c, err := ExtractComponent(a, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	srcNs, err := GetNamespace(a, componentNamespace(from))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := c.Name(false)
+	newName := path.Base(to)
+	if strings.HasSuffix(to, "/") {
+		newName = name
+	}
+
+	dstNs, err := GetNamespace(a, componentNamespace(to))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if srcNs.path == dstNs.path && name == newName {
+		return nil, nil, errors.Errorf("component %q is already %s", c.Name(true), to)
+	}
+
+	if err := dstNs.checkComponentName(newName); err != nil {
+		return nil, nil, err
+	}
+
+	source, err := Path(a, c.Name(true))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Helm charts and kustomizations are directories without an extension.
+	dst := filepath.Join(dstNs.Dir(), newName+filepath.Ext(source))
+
+	exists, err := afero.Exists(a.Fs(), dst)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if exists {
+		return nil, nil, errors.Errorf("%s already exists", dst)
+	}
+
+	m := &componentMove{
+		app:     a,
+		c:       c,
+		srcNs:   srcNs,
+		dstNs:   dstNs,
+		newName: newName,
+	}
+
+	if err := m.moveParams(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := m.moveSecrets(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := m.renameEnvParams(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := a.Fs().Rename(source, dst); err != nil {
+		return nil, nil, errors.Wrapf(err, "move %s", source)
+	}
+
+	for _, change := range m.changes {
+		if err := afero.WriteFile(a.Fs(), change.Path, []byte(change.To), change.perm); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	envNames, err := m.retarget()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	moved, err := ExtractComponent(a, strings.TrimPrefix(path.Join(dstNs.path, newName), "/"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return moved, envNames, nil
+}
+
+// componentNamespace returns the namespace part of a namespaced component
+// name.
+func componentNamespace(name string) string {
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		return ""
+	}
+
+	return strings.Trim(name[:i], "/")
+}
+
+// componentMove plans the changes to params files which move a component
+// between namespaces.
+type componentMove struct {
+	app     app.App
+	c       Component
+	srcNs   Namespace
+	dstNs   Namespace
+	newName string
+
+	changes []FileChange
+}
+
+// change records a change to a params file. Files which don't change are
+// skipped.
+func (m *componentMove) change(path, from, to string, perm os.FileMode) {
+	if from == to {
+		return
+	}
+
+	m.changes = append(m.changes, FileChange{Path: path, From: from, To: to, perm: perm})
+}
+
+// key converts one of the component's params keys to the key for its new
+// name. ok is false if the key belongs to another component.
+func (m *componentMove) key(key string) (string, bool) {
+	_, index, ok := paramsKeyOwner([]Component{m.c}, key)
+	if !ok {
+		return "", false
+	}
+
+	if ParamsKey(m.c, ParamOptions{}) == m.c.Name(false) {
+		return m.newName, true
+	}
+
+	return fmt.Sprintf("%s-%s", m.newName, index), true
+}
+
+// moveEntries moves the component's entries under root from srcData to
+// dstData. If dstData is blank, entries are moved within srcData.
+func (m *componentMove) moveEntries(root, srcData, dstData string) (string, string, error) {
+	entries, err := params.ToMap("", srcData, root)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not find components")
+	}
+
+	keys := sortedKeys(entries)
+
+	moved := make(map[string]interface{})
+	for _, key := range keys {
+		newKey, ok := m.key(key)
+		if !ok {
+			continue
+		}
+
+		props, ok := entries[key].(map[string]interface{})
+		if !ok {
+			return "", "", errors.Errorf("params for %q are not an object", key)
+		}
+		moved[newKey] = props
+
+		if srcData, err = params.Remove([]string{root, key}, srcData); err != nil {
+			return "", "", err
+		}
+	}
+
+	if dstData == "" {
+		dstData = srcData
+	}
+
+	for _, newKey := range sortedKeys(moved) {
+		if dstData, err = params.Update([]string{root, newKey}, dstData, moved[newKey].(map[string]interface{})); err != nil {
+			return "", "", errors.Wrap(err, "update params")
+		}
+	}
+
+	return srcData, dstData, nil
+}
+
+func (m *componentMove) sameNamespace() bool {
+	return m.srcNs.path == m.dstNs.path
+}
+
+func (m *componentMove) moveParams() error {
+	srcData, err := m.srcNs.readParams()
+	if err != nil {
+		return err
+	}
+
+	var dstData string
+	if !m.sameNamespace() {
+		if dstData, err = m.dstNs.readParams(); err != nil {
+			return err
+		}
+	}
+
+	from := dstData
+	if m.sameNamespace() {
+		from = srcData
+	}
+
+	updatedSrc, updatedDst, err := m.moveEntries(paramsComponentRoot, srcData, dstData)
+	if err != nil {
+		return err
+	}
+
+	if !m.sameNamespace() {
+		m.change(m.srcNs.ParamsPath(), srcData, updatedSrc, 0644)
+	}
+
+	m.change(m.dstNs.ParamsPath(), from, updatedDst, 0644)
+	return nil
+}
+
+func (m *componentMove) moveSecrets() error {
+	srcData, err := m.srcNs.Secrets()
+	if err != nil || srcData == "" {
+		return err
+	}
+
+	dstData, from := "", srcData
+	if !m.sameNamespace() {
+		if dstData, err = m.dstNs.Secrets(); err != nil {
+			return err
+		}
+
+		if dstData == "" {
+			dstData = params.EmptySecrets
+		}
+		from = dstData
+	}
+
+	updatedSrc, updatedDst, err := m.moveEntries(paramsComponentRoot, srcData, dstData)
+	if err != nil {
+		return errors.Wrap(err, "move secrets")
+	}
+
+	if !m.sameNamespace() {
+		m.change(m.srcNs.SecretsPath(), srcData, updatedSrc, 0600)
+	}
+
+	m.change(m.dstNs.SecretsPath(), from, updatedDst, 0600)
+	return nil
+}
+
+// renameEnvParams renames the component's environment overrides. Overrides
+// are keyed by component name, so they only change if the component is
+// renamed. Overrides which are shared with a component of the same name in
+// another namespace, or which would be merged with another component's, can't
+// be renamed.
+func (m *componentMove) renameEnvParams() error {
+	if m.c.Name(false) == m.newName {
+		return nil
+	}
+
+	envs, err := m.app.Environments()
+	if err != nil {
+		return err
+	}
+
+	var envNames []string
+	for envName := range envs {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	var namesakes []string
+	for _, envName := range envNames {
+		envData, err := readEnvParams(m.app, envName)
+		if err != nil {
+			return err
+		}
+
+		overrides, err := params.EnvToMap("", envData)
+		if err != nil {
+			return errors.Wrapf(err, "read %q environment params", envName)
+		}
+
+		updated := envData
+		for _, key := range sortedKeys(overrides) {
+			newKey, ok := m.key(key)
+			if !ok {
+				continue
+			}
+
+			if namesakes == nil {
+				if namesakes, err = m.namesakes(); err != nil {
+					return err
+				}
+			}
+
+			if len(namesakes) > 1 {
+				return errors.Errorf("environment %q overrides for %q are shared by components %s; rename them by hand",
+					envName, key, strings.Join(namesakes, ", "))
+			}
+
+			if _, ok := overrides[newKey]; ok {
+				return errors.Errorf("environment %q already has overrides for %q", envName, newKey)
+			}
+
+			props, ok := overrides[key].(map[string]interface{})
+			if !ok {
+				return errors.Errorf("overrides for %q in environment %q are not an object", key, envName)
+			}
+
+			if updated, err = params.RemoveEnv(updated, key); err != nil {
+				return err
+			}
+
+			if updated, err = params.UpdateEnv(updated, newKey, props); err != nil {
+				return err
+			}
+		}
+
+		m.change(EnvParamsPath(m.app, envName), envData, updated, 0644)
+	}
+
+	return nil
+}
+
+// namesakes returns the namespaced names of the components, including the one
+// being moved, which share its name and therefore its environment overrides.
+func (m *componentMove) namesakes() ([]string, error) {
+	found, err := namesakes(m.app, m.c.Name(false))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, n := range found {
+		names = append(names, n.name())
+	}
+
+	return names, nil
+}
+
+// namesake is a component found in a namespace.
+type namesake struct {
+	ns Namespace
+	c  Component
+}
+
+// name returns the namespaced name of the component.
+func (n namesake) name() string {
+	return strings.TrimPrefix(path.Join(n.ns.path, n.c.Name(false)), "/")
+}
+
+// namesakes returns the components in every namespace which are named name.
+// Environment overrides are keyed by component name, so they are shared.
+func namesakes(a app.App, name string) ([]namesake, error) {
+	namespaces, err := Namespaces(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []namesake
+	for _, ns := range namespaces {
+		components, err := ns.Components()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range components {
+			if c.Name(false) == name {
+				found = append(found, namesake{ns: ns, c: c})
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// retarget adds the new namespace to environments which target the old one.
+// Environments without targets target the root namespace.
+func (m *componentMove) retarget() ([]string, error) {
+	if m.sameNamespace() {
+		return nil, nil
+	}
+
+	envs, err := m.app.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var updated []string
+	for _, name := range names {
+		targets := envs[name].Targets
+		if len(targets) == 0 {
+			targets = []string{"/"}
+		}
+
+		if !hasTarget(targets, m.srcNs.path) || hasTarget(targets, m.dstNs.path) {
+			continue
+		}
+
+		targets = append(targets[:len(targets):len(targets)], m.dstNs.Name())
+		if err := m.app.UpdateTargets(name, targets); err != nil {
+			return nil, err
+		}
+
+		updated = append(updated, name)
+	}
+
+	return updated, nil
+}
+
+// hasTarget reports whether targets includes a namespace.
+func hasTarget(targets []string, nsPath string) bool {
+	for _, target := range targets {
+		if t, err := cleanNsName(target); err == nil && t == nsPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MoveNamespace moves a namespace, along with the namespaces nested in it.
+// Environment targets which refer to the moved namespaces are rewritten. The
+// names of the updated environments are returned.
+func MoveNamespace(a app.App, from, to string) ([]string, error) {
+	src, err := GetNamespace(a, from)
+	if err != nil {
+		return nil, err
+	}
+
+	dstPath, err := cleanNsName(to)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case src.path == "" || dstPath == "":
+		return nil, errors.New("the root namespace can't be moved")
+	case src.path == dstPath:
+		return nil, errors.Errorf("namespace %q is already %s", src.path, to)
+	case strings.HasPrefix(dstPath, src.path+"/"):
+		return nil, errors.Errorf("namespace %q can't be moved into itself", src.path)
+	}
+
+	dst := Namespace{path: dstPath, app: a}
+
+	exists, err := afero.Exists(a.Fs(), dst.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, errors.Errorf("%s already exists", dst.Dir())
+	}
+
+	if err := a.Fs().MkdirAll(filepath.Dir(dst.Dir()), app.DefaultFolderPermissions); err != nil {
+		return nil, err
+	}
+
+	if err := a.Fs().Rename(src.Dir(), dst.Dir()); err != nil {
+		return nil, errors.Wrapf(err, "move namespace %q", src.path)
+	}
+
+	return updateTargets(a, func(target string) (string, bool) {
+		switch {
+		case target == src.path:
+			return dstPath, true
+		case strings.HasPrefix(target, src.path+"/"):
+			return dstPath + strings.TrimPrefix(target, src.path), true
+		default:
+			return target, true
+		}
+	})
+}
+
+// updateTargets rewrites the targets of each environment. rewrite returns the
+// new name of a target, or false if the target should be removed. Targets are
+// passed to rewrite cleaned, and unchanged targets are kept as written. The
+// names of environments whose targets change are returned.
+func updateTargets(a app.App, rewrite func(target string) (string, bool)) ([]string, error) {
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var updated []string
+	for _, name := range names {
+		targets := envs[name].Targets
+
+		changed := false
+		var rewritten []string
+		for _, target := range targets {
+			t, err := cleanNsName(target)
+			if err != nil {
+				return nil, errors.Wrapf(err, "environment %q", name)
+			}
+
+			newTarget, ok := rewrite(t)
+			switch {
+			case !ok:
+				changed = true
+			case newTarget != t:
+				changed = true
+				rewritten = append(rewritten, newTarget)
+			default:
+				rewritten = append(rewritten, target)
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err := a.UpdateTargets(name, rewritten); err != nil {
+			return nil, err
+		}
+
+		updated = append(updated, name)
+	}
+
+	return updated, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
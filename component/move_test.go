@@ -0,0 +1,256 @@
+package component
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bryanl/woowoo/ksutil/mocks"
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMove(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "deployment.yaml", "/app/components/deployment.yaml")
+	stageFile(t, fs, "params-deployment.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/apps/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	envs := app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}
+	a.On("Environments").Return(envs, nil)
+	a.On("UpdateTargets", "default", []string{"/", "apps"}).Return(nil)
+
+	c, err := ExtractComponent(a, "deployment")
+	require.NoError(t, err)
+	require.NoError(t, SetEnvParam(a, "default", c, []string{"replicas"}, 3, ParamOptions{}))
+
+	moved, envNames, err := Move(a, "deployment", "apps/web")
+	require.NoError(t, err)
+	require.Equal(t, "apps/web", moved.Name(true))
+	require.Equal(t, []string{"default"}, envNames)
+	a.AssertCalled(t, "UpdateTargets", "default", []string{"/", "apps"})
+
+	exists, err := afero.Exists(fs, "/app/components/deployment.yaml")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	exists, err = afero.Exists(fs, "/app/components/apps/web.yaml")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	b, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+	rootParams, err := params.ToMap("", string(b), "components")
+	require.NoError(t, err)
+	require.Empty(t, rootParams)
+
+	b, err = afero.ReadFile(fs, "/app/components/apps/params.libsonnet")
+	require.NoError(t, err)
+	appsParams, err := params.ToMap("web-0", string(b), "components")
+	require.NoError(t, err)
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				"label1": "label1",
+				"label2": "label2",
+			},
+		},
+	}
+	require.Equal(t, expected, appsParams)
+
+	b, err = afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+	overrides, err := params.EnvToMap("", string(b))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"web-0": map[string]interface{}{"replicas": float64(3)},
+	}, overrides)
+}
+
+func TestMove_rename(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/guestbook-ui.jsonnet")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	moved, envNames, err := Move(a, "guestbook-ui", "guestbook")
+	require.NoError(t, err)
+	require.Equal(t, "guestbook", moved.Name(true))
+	require.Empty(t, envNames)
+
+	b, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+	require.Contains(t, string(b), "// Component-level parameters")
+
+	m, err := params.ToMap("", string(b), "components")
+	require.NoError(t, err)
+	require.Contains(t, m, "guestbook")
+	require.NotContains(t, m, "guestbook-ui")
+}
+
+// readFiles reads files so they can be checked for changes.
+func readFiles(t *testing.T, fs afero.Fs, paths ...string) map[string]string {
+	files := make(map[string]string)
+	for _, path := range paths {
+		b, err := afero.ReadFile(fs, path)
+		require.NoError(t, err)
+		files[path] = string(b)
+	}
+
+	return files
+}
+
+func TestMove_rename_shared_overrides(t *testing.T) {
+	a, fs := appMock("/app")
+	for _, dir := range []string{"/app/components", "/app/components/apps"} {
+		stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", dir+"/guestbook-ui.jsonnet")
+		stageFile(t, fs, "guestbook/params.libsonnet", dir+"/params.libsonnet")
+	}
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	c, err := ExtractComponent(a, "guestbook-ui")
+	require.NoError(t, err)
+	require.NoError(t, SetEnvParam(a, "default", c, []string{"replicas"}, 3, ParamOptions{}))
+
+	paths := []string{
+		"/app/components/params.libsonnet",
+		"/app/components/apps/params.libsonnet",
+		"/app/environments/default/params.libsonnet",
+	}
+	before := readFiles(t, fs, paths...)
+
+	// The overrides for guestbook-ui also apply to apps/guestbook-ui.
+	_, _, err = Move(a, "guestbook-ui", "guestbook")
+	require.EqualError(t, err, `environment "default" overrides for "guestbook-ui" are shared by components guestbook-ui, apps/guestbook-ui; rename them by hand`)
+
+	require.Equal(t, before, readFiles(t, fs, paths...))
+
+	exists, err := afero.Exists(fs, "/app/components/guestbook-ui.jsonnet")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// Without overrides, the component can be renamed.
+	require.NoError(t, afero.WriteFile(fs, "/app/environments/default/params.libsonnet", testdata(t, "env-params.libsonnet"), 0644))
+	_, _, err = Move(a, "guestbook-ui", "guestbook")
+	require.NoError(t, err)
+}
+
+func TestMove_rename_existing_overrides(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/guestbook-ui.jsonnet")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	envData, err := params.UpdateEnv(string(testdata(t, "env-params.libsonnet")), "guestbook", map[string]interface{}{"replicas": 2})
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/app/environments/default/params.libsonnet", []byte(envData), 0644))
+
+	c, err := ExtractComponent(a, "guestbook-ui")
+	require.NoError(t, err)
+	require.NoError(t, SetEnvParam(a, "default", c, []string{"replicas"}, 3, ParamOptions{}))
+
+	paths := []string{"/app/components/params.libsonnet", "/app/environments/default/params.libsonnet"}
+	before := readFiles(t, fs, paths...)
+
+	_, _, err = Move(a, "guestbook-ui", "guestbook")
+	require.EqualError(t, err, `environment "default" already has overrides for "guestbook"`)
+	require.Equal(t, before, readFiles(t, fs, paths...))
+}
+
+func TestMove_failure_leaves_params(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/guestbook-ui.jsonnet")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+
+	// The environment's params can't be read.
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	before := readFiles(t, fs, "/app/components/params.libsonnet")
+
+	_, _, err := Move(a, "guestbook-ui", "guestbook")
+	require.Error(t, err)
+
+	require.Equal(t, before, readFiles(t, fs, "/app/components/params.libsonnet"))
+
+	exists, err := afero.Exists(fs, "/app/components/guestbook-ui.jsonnet")
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestMove_invalid(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/guestbook-ui.jsonnet")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+
+	cases := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "missing component", from: "missing", to: "other"},
+		{name: "missing namespace", from: "guestbook-ui", to: "missing/guestbook-ui"},
+		{name: "same name", from: "guestbook-ui", to: "guestbook-ui"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := Move(a, tc.from, tc.to)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMoveNamespace(t *testing.T) {
+	// Directories are moved with a rename, which a memory fs doesn't support.
+	dir, err := ioutil.TempDir("", "move-namespace")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fs := afero.NewBasePathFs(afero.NewOsFs(), dir)
+	a := &mocks.SuperApp{}
+	a.On("Fs").Return(fs)
+	a.On("Root").Return("/app")
+
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "params-with-entry.libsonnet", "/app/components/ns1/params.libsonnet")
+	stageFile(t, fs, "certificate-crd.yaml", "/app/components/ns1/certificate-crd.yaml")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/ns1/nested/params.libsonnet")
+
+	envs := app.EnvironmentSpecs{
+		"default": &app.EnvironmentSpec{Targets: []string{"ns1/nested", "/"}},
+		"prod":    &app.EnvironmentSpec{},
+	}
+	a.On("Environments").Return(envs, nil)
+	a.On("UpdateTargets", "default", []string{"apps/web/nested", "/"}).Return(nil)
+
+	envNames, err := MoveNamespace(a, "ns1", "apps/web")
+	require.NoError(t, err)
+	require.Equal(t, []string{"default"}, envNames)
+	a.AssertCalled(t, "UpdateTargets", "default", []string{"apps/web/nested", "/"})
+
+	for _, path := range []string{"apps/web/certificate-crd.yaml", "apps/web/nested/params.libsonnet"} {
+		exists, err := afero.Exists(fs, filepath.Join("/app/components", path))
+		require.NoError(t, err)
+		require.True(t, exists, path)
+	}
+
+	exists, err := afero.Exists(fs, "/app/components/ns1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = MoveNamespace(a, "apps", "apps/web/inner")
+	require.Error(t, err)
+
+	_, err = MoveNamespace(a, "/", "root")
+	require.Error(t, err)
+}
@@ -3,8 +3,10 @@ package component
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/bryanl/woowoo/params"
@@ -37,10 +39,33 @@ func NewNamespace(ksApp app.App, path string) Namespace {
 // ExtractNamespacedComponent extracts a namespace and a component from a path.
 func ExtractNamespacedComponent(a app.App, path string) (Namespace, string) {
 	nsPath, component := filepath.Split(path)
-	ns := Namespace{path: nsPath, app: a}
+	ns := Namespace{path: strings.Trim(nsPath, "/"), app: a}
 	return ns, component
 }
 
+// GetComponentNamespace gets the namespace of a component by the component's
+// name.
+func GetComponentNamespace(a app.App, name string) (Namespace, error) {
+	return GetNamespace(a, componentNamespace(name))
+}
+
+// cleanNsName cleans a namespace name so it can be compared with other names.
+// Leading, trailing and repeated slashes are removed, so the root namespace
+// is blank. Names can't contain `.` or `..` elements.
+func cleanNsName(nsName string) (string, error) {
+	parts := strings.FieldsFunc(nsName, func(r rune) bool {
+		return r == '/' || r == filepath.Separator
+	})
+
+	for _, part := range parts {
+		if part == "." || part == ".." {
+			return "", errors.Errorf("invalid namespace name %q", nsName)
+		}
+	}
+
+	return strings.Join(parts, "/"), nil
+}
+
 // Name returns the namespace name.
 func (n *Namespace) Name() string {
 	if n.path == "" {
@@ -51,19 +76,23 @@ func (n *Namespace) Name() string {
 
 // GetNamespace gets a namespace by path.
 func GetNamespace(a app.App, nsName string) (Namespace, error) {
-	parts := strings.Split(nsName, "/")
-	nsDir := filepath.Join(append([]string{a.Root(), componentsRoot}, parts...)...)
+	nsPath, err := cleanNsName(nsName)
+	if err != nil {
+		return Namespace{}, err
+	}
 
-	exists, err := afero.Exists(a.Fs(), nsDir)
+	ns := Namespace{path: nsPath, app: a}
+
+	exists, err := afero.Exists(a.Fs(), ns.Dir())
 	if err != nil {
 		return Namespace{}, err
 	}
 
 	if !exists {
-		return Namespace{}, errors.New(nsErrorMsg("unable to find %s", nsName))
+		return Namespace{}, errors.New(nsErrorMsg("unable to find %s", nsPath))
 	}
 
-	return Namespace{path: nsName, app: a}, nil
+	return ns, nil
 }
 
 // ParamsPath generates the path to params.libsonnet for a namespace.
@@ -72,13 +101,18 @@ func (n *Namespace) ParamsPath() string {
 }
 
 // SetParam sets params for a namespace.
-func (n *Namespace) SetParam(path []string, value interface{}) error {
+func (n *Namespace) SetParam(path []string, value interface{}, options ParamOptions) error {
 	paramsData, err := n.readParams()
 	if err != nil {
 		return err
 	}
 
-	updatedParams, err := params.Set(path, paramsData, "", value, "global")
+	schema, err := n.Schema()
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Set(path, paramsData, "", value, "global", params.WithSchema(schema), params.WithForce(options.Force))
 	if err != nil {
 		return err
 	}
@@ -90,27 +124,40 @@ func (n *Namespace) SetParam(path []string, value interface{}) error {
 	return nil
 }
 
+// Schema returns the params schema for a namespace. It returns nil if the
+// namespace does not have a schema.
+func (n *Namespace) Schema() (*params.Schema, error) {
+	return readSchema(n.app.Fs(), n.ParamsPath())
+}
+
 func (n *Namespace) writeParams(src string) error {
 	return afero.WriteFile(n.app.Fs(), n.ParamsPath(), []byte(src), 0644)
 }
 
 // Dir is the absolute directory for a namespace.
 func (n *Namespace) Dir() string {
-	parts := strings.Split(n.path, "/")
 	path := []string{n.app.Root(), componentsRoot}
-	if len(n.path) != 0 {
-		path = append(path, parts...)
-	}
+	path = append(path, strings.Split(n.path, "/")...)
 
 	return filepath.Join(path...)
 }
 
 // NamespaceParameter is a namespaced paramater.
 type NamespaceParameter struct {
-	Component string
-	Index     string
-	Key       string
-	Value     string
+	Component string `json:"component"`
+	Index     string `json:"index"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	// RawValue is the param's value before it is formatted for display.
+	RawValue interface{} `json:"-"`
+	// Source is where the value came from when params are resolved for an
+	// environment.
+	Source string `json:"source,omitempty"`
+	// Computed is true if the value is set by an expression.
+	Computed bool `json:"computed,omitempty"`
+	// Secret is true if the value is stored in the namespace's secrets. The
+	// value of a secret param is masked.
+	Secret bool `json:"secret,omitempty"`
 }
 
 // ResolvedParams resolves paramaters for a namespace. It returns a JSON encoded
@@ -143,9 +190,35 @@ func (n *Namespace) Params() ([]NamespaceParameter, error) {
 		}
 	}
 
+	nsps, err = n.maskSecrets(components, nsps)
+	if err != nil {
+		return nil, err
+	}
+
+	sortParams(nsps)
+
 	return nsps, nil
 }
 
+// sortParams sorts params by component, index and key. Indexes are compared
+// numerically.
+func sortParams(nsps []NamespaceParameter) {
+	sort.SliceStable(nsps, func(i, j int) bool {
+		if nsps[i].Component != nsps[j].Component {
+			return nsps[i].Component < nsps[j].Component
+		}
+		if nsps[i].Index != nsps[j].Index {
+			a, errA := strconv.Atoi(nsps[i].Index)
+			b, errB := strconv.Atoi(nsps[j].Index)
+			if errA == nil && errB == nil {
+				return a < b
+			}
+			return nsps[i].Index < nsps[j].Index
+		}
+		return nsps[i].Key < nsps[j].Key
+	})
+}
+
 func (n *Namespace) readParams() (string, error) {
 	b, err := afero.ReadFile(n.app.Fs(), n.ParamsPath())
 	if err != nil {
@@ -221,10 +294,56 @@ func Namespaces(a app.App) ([]Namespace, error) {
 	return namespaces, nil
 }
 
+// NamespaceNode is a namespace in a tree of nested namespaces.
+type NamespaceNode struct {
+	Namespace Namespace
+	// Components is the number of components in the namespace.
+	Components int
+	Children   []*NamespaceNode
+}
+
+// NamespaceTree returns the app's namespaces as a tree starting at the root
+// namespace. A namespace's parent is the closest namespace it is nested in.
+func NamespaceTree(a app.App) (*NamespaceNode, error) {
+	namespaces, err := Namespaces(a)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &NamespaceNode{Namespace: Namespace{app: a}}
+	nodes := map[string]*NamespaceNode{"": root}
+
+	// Namespaces are sorted by name, so parents are seen before children.
+	for _, ns := range namespaces {
+		components, err := ns.Components()
+		if err != nil {
+			return nil, err
+		}
+
+		node, ok := nodes[ns.path]
+		if !ok {
+			node = &NamespaceNode{Namespace: ns}
+			nodes[ns.path] = node
+
+			parent := root
+			for p := path.Dir(ns.path); p != "." && p != "/"; p = path.Dir(p) {
+				if n, ok := nodes[p]; ok {
+					parent = n
+					break
+				}
+			}
+			parent.Children = append(parent.Children, node)
+		}
+
+		node.Components = len(components)
+	}
+
+	return root, nil
+}
+
 // Components returns the components in a namespace.
 func (n *Namespace) Components() ([]Component, error) {
-	parts := strings.Split(n.path, "/")
-	nsDir := filepath.Join(append([]string{n.app.Root(), componentsRoot}, parts...)...)
+	nsDir := n.Dir()
 
 	fis, err := afero.ReadDir(n.app.Fs(), nsDir)
 	if err != nil {
@@ -237,6 +356,31 @@ func (n *Namespace) Components() ([]Component, error) {
 		ext := filepath.Ext(fi.Name())
 		path := filepath.Join(nsDir, fi.Name())
 
+		if fi.IsDir() {
+			isChart, err := isHelmChart(n.app.Fs(), path)
+			if err != nil {
+				return nil, err
+			}
+
+			if isChart {
+				component := NewHelm(n.app, n.Name(), path, n.ParamsPath())
+				components = append(components, component)
+				continue
+			}
+
+			isOverlay, err := isKustomization(n.app.Fs(), path)
+			if err != nil {
+				return nil, err
+			}
+
+			if isOverlay {
+				component := NewKustomize(n.app, n.Name(), path, n.ParamsPath())
+				components = append(components, component)
+			}
+
+			continue
+		}
+
 		switch ext {
 		// TODO: these should be constants
 		case ".yaml", ".json":
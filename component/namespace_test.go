@@ -45,3 +45,76 @@ func TestNamespace_Components(t *testing.T) {
 	}
 
 }
+
+func TestNamespaceTree(t *testing.T) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "certificate-crd.yaml", "/app/components/ns1/certificate-crd.yaml")
+	stageFile(t, fs, "params-with-entry.libsonnet", "/app/components/ns1/params.libsonnet")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/ns1/nested/params.libsonnet")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/dir/ns2/params.libsonnet")
+
+	root, err := NamespaceTree(app)
+	require.NoError(t, err)
+
+	require.Equal(t, "/", root.Namespace.Name())
+	require.Equal(t, 0, root.Components)
+	require.Len(t, root.Children, 2)
+
+	require.Equal(t, "dir/ns2", root.Children[0].Namespace.Name())
+	require.Empty(t, root.Children[0].Children)
+
+	ns1 := root.Children[1]
+	require.Equal(t, "ns1", ns1.Namespace.Name())
+	require.Equal(t, 1, ns1.Components)
+	require.Len(t, ns1.Children, 1)
+	require.Equal(t, "ns1/nested", ns1.Children[0].Namespace.Name())
+}
+
+func Test_cleanNsName(t *testing.T) {
+	cases := []struct {
+		nsName   string
+		expected string
+		isErr    bool
+	}{
+		{nsName: "", expected: ""},
+		{nsName: "/", expected: ""},
+		{nsName: "a", expected: "a"},
+		{nsName: "/a//b/", expected: "a/b"},
+		{nsName: "a/../b", isErr: true},
+		{nsName: "./a", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.nsName, func(t *testing.T) {
+			got, err := cleanNsName(tc.nsName)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_sortParams(t *testing.T) {
+	nsps := []NamespaceParameter{
+		{Component: "b", Index: "0", Key: "a"},
+		{Component: "a", Index: "10", Key: "a"},
+		{Component: "a", Index: "2", Key: "b"},
+		{Component: "a", Index: "2", Key: "a"},
+	}
+
+	sortParams(nsps)
+
+	expected := []NamespaceParameter{
+		{Component: "a", Index: "2", Key: "a"},
+		{Component: "a", Index: "2", Key: "b"},
+		{Component: "a", Index: "10", Key: "a"},
+		{Component: "b", Index: "0", Key: "a"},
+	}
+	require.Equal(t, expected, nsps)
+}
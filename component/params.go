@@ -1,9 +1,41 @@
 package component
 
 import (
+	"path/filepath"
+
+	"github.com/bryanl/woowoo/params"
 	jsonnet "github.com/google/go-jsonnet"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 )
 
+// readSchema reads the params schema which lives next to a params file. It
+// returns nil if there is no schema.
+func readSchema(fs afero.Fs, paramsPath string) (*params.Schema, error) {
+	schemaPath := filepath.Join(filepath.Dir(paramsPath), params.SchemaFile)
+
+	exists, err := afero.Exists(fs, schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	b, err := afero.ReadFile(fs, schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := params.ParseSchema(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s", schemaPath)
+	}
+
+	return schema, nil
+}
+
 func applyGlobals(params string) (string, error) {
 	vm := jsonnet.MakeVM()
 
@@ -0,0 +1,271 @@
+package component
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// FileChange is a change to the contents of a file.
+type FileChange struct {
+	Path string
+	From string
+	To   string
+
+	perm os.FileMode
+}
+
+// Removal is the set of changes which remove a component.
+type Removal struct {
+	// Source is the component's file or directory.
+	Source string
+	// Changes are the changes to params files.
+	Changes []FileChange
+
+	fs afero.Fs
+}
+
+// PlanRemove finds the changes which remove a component along with its
+// params, secrets and environment overrides. Nothing is changed until the
+// removal is applied.
+func PlanRemove(a app.App, name string) (*Removal, error) {
+	c, err := ExtractComponent(a, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := GetNamespace(a, componentNamespace(name))
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := Path(a, c.Name(true))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Removal{Source: source, fs: a.Fs()}
+
+	paramsData, err := ns.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.removeEntries(c, ns.ParamsPath(), paramsData, 0644); err != nil {
+		return nil, err
+	}
+
+	secrets, err := ns.Secrets()
+	if err != nil {
+		return nil, err
+	}
+
+	if secrets != "" {
+		if err := r.removeEntries(c, ns.SecretsPath(), secrets, 0600); err != nil {
+			return nil, errors.Wrap(err, "remove secrets")
+		}
+	}
+
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var envNames []string
+	for envName := range envs {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	found, err := namesakes(a, c.Name(false))
+	if err != nil {
+		return nil, err
+	}
+
+	var others []Component
+	for _, n := range found {
+		if n.ns.path != ns.path {
+			others = append(others, n.c)
+		}
+	}
+
+	for _, envName := range envNames {
+		if err := r.removeEnvOverrides(a, c, others, envName); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// removeEntries removes the component's entries from the params file at path.
+func (r *Removal) removeEntries(c Component, path, src string, perm os.FileMode) error {
+	entries, err := params.ToMap("", src, paramsComponentRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not find components")
+	}
+
+	removed := false
+	for key := range entries {
+		if ownsParamsKey(c, key) {
+			delete(entries, key)
+			removed = true
+		}
+	}
+
+	if !removed {
+		return nil
+	}
+
+	updated, err := params.Update([]string{paramsComponentRoot}, src, entries)
+	if err != nil {
+		return errors.Wrap(err, "update params")
+	}
+
+	r.Changes = append(r.Changes, FileChange{Path: path, From: src, To: updated, perm: perm})
+	return nil
+}
+
+// removeEnvOverrides removes the component's overrides from an environment.
+// Overrides are keyed by component name, so a component with the same name in
+// another namespace shares them. Overrides which belong to one of others are
+// kept.
+func (r *Removal) removeEnvOverrides(a app.App, c Component, others []Component, envName string) error {
+	envData, err := readEnvParams(a, envName)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := params.EnvToMap("", envData)
+	if err != nil {
+		return errors.Wrapf(err, "read %q environment params", envName)
+	}
+
+	updated := envData
+	for _, key := range sortedKeys(overrides) {
+		if !ownsParamsKey(c, key) {
+			continue
+		}
+
+		shared, err := acceptsAnyParamsKey(others, key)
+		if err != nil {
+			return err
+		}
+
+		if shared {
+			continue
+		}
+
+		if updated, err = params.RemoveEnv(updated, key); err != nil {
+			return err
+		}
+	}
+
+	if updated != envData {
+		r.Changes = append(r.Changes, FileChange{Path: EnvParamsPath(a, envName), From: envData, To: updated, perm: 0644})
+	}
+
+	return nil
+}
+
+// Apply removes the component's source and writes the changed params files.
+func (r *Removal) Apply() error {
+	if err := r.fs.RemoveAll(r.Source); err != nil {
+		return errors.Wrapf(err, "remove %s", r.Source)
+	}
+
+	for _, change := range r.Changes {
+		if err := afero.WriteFile(r.fs, change.Path, []byte(change.To), change.perm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// acceptsAnyParamsKey reports whether a params key belongs to any of the
+// components.
+func acceptsAnyParamsKey(components []Component, key string) (bool, error) {
+	for _, c := range components {
+		ok, err := acceptsParamsKey(c, key)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// ownsParamsKey reports whether a params key belongs to a component.
+func ownsParamsKey(c Component, key string) bool {
+	_, _, ok := paramsKeyOwner([]Component{c}, key)
+	return ok
+}
+
+// TargetedError is returned when a namespace which is targeted by
+// environments is removed.
+type TargetedError struct {
+	Namespace    string
+	Environments []string
+}
+
+func (e *TargetedError) Error() string {
+	return nsErrorMsg("%s", e.Namespace) + " is targeted by environments: " + strings.Join(e.Environments, ", ")
+}
+
+// RemoveNamespace removes a namespace, along with the namespaces nested in
+// it. If any of them are environment targets, a TargetedError is returned
+// unless force is set, in which case they are removed from the targets. An
+// environment left without targets targets the root namespace. The names of
+// the updated environments are returned.
+func RemoveNamespace(a app.App, nsName string, force bool) ([]string, error) {
+	ns, err := GetNamespace(a, nsName)
+	if err != nil {
+		return nil, err
+	}
+
+	if ns.path == "" {
+		return nil, errors.New("the root namespace can't be removed")
+	}
+
+	removed := func(target string) bool {
+		return target == ns.path || strings.HasPrefix(target, ns.path+"/")
+	}
+
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var targeted []string
+	for envName, spec := range envs {
+		for _, target := range spec.Targets {
+			if t, err := cleanNsName(target); err == nil && removed(t) {
+				targeted = append(targeted, envName)
+				break
+			}
+		}
+	}
+	sort.Strings(targeted)
+
+	if len(targeted) > 0 && !force {
+		return nil, &TargetedError{Namespace: ns.path, Environments: targeted}
+	}
+
+	if err := a.Fs().RemoveAll(ns.Dir()); err != nil {
+		return nil, errors.Wrapf(err, "remove namespace %q", ns.path)
+	}
+
+	if len(targeted) == 0 {
+		return nil, nil
+	}
+
+	return updateTargets(a, func(target string) (string, bool) {
+		return target, !removed(target)
+	})
+}
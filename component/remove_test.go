@@ -0,0 +1,136 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRemove(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "rbac.yaml", "/app/components/rbac.yaml")
+	stageFile(t, fs, "deployment.yaml", "/app/components/deployment.yaml")
+	stageFile(t, fs, "params-mixed.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	c, err := ExtractComponent(a, "rbac")
+	require.NoError(t, err)
+	require.NoError(t, SetEnvParam(a, "default", c, []string{"replicas"}, 3, ParamOptions{Index: 1}))
+
+	before, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+
+	r, err := PlanRemove(a, "rbac")
+	require.NoError(t, err)
+	require.Equal(t, "/app/components/rbac.yaml", r.Source)
+	require.Len(t, r.Changes, 2)
+	require.Equal(t, "/app/components/params.libsonnet", r.Changes[0].Path)
+	require.Equal(t, string(before), r.Changes[0].From)
+	require.Equal(t, "/app/environments/default/params.libsonnet", r.Changes[1].Path)
+
+	exists, err := afero.Exists(fs, "/app/components/rbac.yaml")
+	require.NoError(t, err)
+	require.True(t, exists, "planning a removal changed the source")
+
+	require.NoError(t, r.Apply())
+
+	exists, err = afero.Exists(fs, "/app/components/rbac.yaml")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	b, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+	require.Contains(t, string(b), "// Each object below should correspond to a component")
+
+	m, err := params.ToMap("", string(b), "components")
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	require.Contains(t, m, "deployment-0")
+
+	b, err = afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+
+	overrides, err := params.EnvToMap("", string(b))
+	require.NoError(t, err)
+	require.Empty(t, overrides)
+}
+
+func TestPlanRemove_shared_overrides(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "rbac.yaml", "/app/components/rbac.yaml")
+	stageFile(t, fs, "params-mixed.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "deployment.yaml", "/app/components/apps/rbac.yaml")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/apps/params.libsonnet")
+	stageFile(t, fs, "env-params.libsonnet", "/app/environments/default/params.libsonnet")
+
+	a.On("Environments").Return(app.EnvironmentSpecs{"default": &app.EnvironmentSpec{}}, nil)
+
+	c, err := ExtractComponent(a, "rbac")
+	require.NoError(t, err)
+	for _, index := range []int{0, 1} {
+		require.NoError(t, SetEnvParam(a, "default", c, []string{"replicas"}, 3, ParamOptions{Index: index}))
+	}
+
+	r, err := PlanRemove(a, "rbac")
+	require.NoError(t, err)
+	require.NoError(t, r.Apply())
+
+	b, err := afero.ReadFile(fs, "/app/environments/default/params.libsonnet")
+	require.NoError(t, err)
+
+	// apps/rbac has one document, so it shares the overrides for rbac-0.
+	overrides, err := params.EnvToMap("", string(b))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"rbac-0": map[string]interface{}{"replicas": float64(3)},
+	}, overrides)
+}
+
+func TestPlanRemove_missing(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "params-mixed.libsonnet", "/app/components/params.libsonnet")
+
+	_, err := PlanRemove(a, "missing")
+	require.Error(t, err)
+}
+
+func TestRemoveNamespace(t *testing.T) {
+	a, fs := appMock("/app")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/ns1/params.libsonnet")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/ns1/nested/params.libsonnet")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/ns2/params.libsonnet")
+
+	envs := app.EnvironmentSpecs{
+		"default": &app.EnvironmentSpec{Targets: []string{"ns1/nested", "ns2"}},
+	}
+	a.On("Environments").Return(envs, nil)
+	a.On("UpdateTargets", "default", []string{"ns2"}).Return(nil)
+
+	_, err := RemoveNamespace(a, "ns1", false)
+	require.Error(t, err)
+	targetedErr, ok := err.(*TargetedError)
+	require.True(t, ok)
+	require.Equal(t, []string{"default"}, targetedErr.Environments)
+
+	exists, err := afero.Exists(fs, "/app/components/ns1/nested/params.libsonnet")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	envNames, err := RemoveNamespace(a, "ns1", true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"default"}, envNames)
+	a.AssertCalled(t, "UpdateTargets", "default", []string{"ns2"})
+
+	exists, err = afero.Exists(fs, "/app/components/ns1/params.libsonnet")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = RemoveNamespace(a, "/", true)
+	require.Error(t, err)
+}
@@ -0,0 +1,251 @@
+package component
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// SecretKeyEnvVar sets the location of the secret key file.
+	SecretKeyEnvVar = "KSCOMP_SECRET_KEY_FILE"
+
+	// ParamSourceSecret is the source of params stored in a namespace's
+	// secrets.
+	ParamSourceSecret = "secret"
+)
+
+// SecretKeyPath returns the path to the key which encrypts secret params. It
+// is set with the KSCOMP_SECRET_KEY_FILE environment variable. The key can't be
+// stored in the app, so it isn't committed with the secrets it encrypts.
+func SecretKeyPath(a app.App) (string, error) {
+	path := os.Getenv(SecretKeyEnvVar)
+	if path == "" {
+		return "", errors.Errorf("secret key file is not set; set %s to a path outside of the app", SecretKeyEnvVar)
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(a.Root(), path)
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("secret key file %s is inside the app; it must be stored outside of the app", path)
+	}
+
+	return path, nil
+}
+
+// ReadSecretKey reads the key which encrypts secret params.
+func ReadSecretKey(a app.App) ([]byte, error) {
+	path, err := SecretKeyPath(a)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := afero.ReadFile(a.Fs(), path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read secret key %s", path)
+	}
+
+	key, err := params.DecodeSecretKey(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read secret key %s", path)
+	}
+
+	return key, nil
+}
+
+// ensureSecretKey reads the secret key, generating one if it doesn't exist.
+func ensureSecretKey(a app.App) ([]byte, error) {
+	path, err := SecretKeyPath(a)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := afero.Exists(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return ReadSecretKey(a)
+	}
+
+	key, err := params.GenerateSecretKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Fs().MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.Wrap(err, "create secret key directory")
+	}
+
+	if err := afero.WriteFile(a.Fs(), path, params.EncodeSecretKey(key), 0600); err != nil {
+		return nil, errors.Wrap(err, "write secret key")
+	}
+
+	return key, nil
+}
+
+// SecretsPath generates the path to secrets.libsonnet for a namespace.
+func (n *Namespace) SecretsPath() string {
+	return filepath.Join(n.Dir(), params.SecretsFile)
+}
+
+// Secrets returns the contents of the namespace's secrets file. Values in the
+// file are encrypted. It returns an empty string if the namespace has no
+// secrets.
+func (n *Namespace) Secrets() (string, error) {
+	exists, err := afero.Exists(n.app.Fs(), n.SecretsPath())
+	if err != nil {
+		return "", err
+	}
+
+	if !exists {
+		return "", nil
+	}
+
+	b, err := afero.ReadFile(n.app.Fs(), n.SecretsPath())
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// SetSecretParam encrypts a component param and stores it in the namespace's
+// secrets. A plain text value for the param is removed from params. Params
+// inside arrays can't be secret.
+func (n *Namespace) SetSecretParam(c Component, path []string, value interface{}, options ParamOptions) error {
+	if params.HasIndex(path) {
+		return errors.Errorf("secret param %s is inside an array", params.FormatPath(path))
+	}
+
+	key, err := ensureSecretKey(n.app)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := params.EncryptValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	src, err := n.Secrets()
+	if err != nil {
+		return err
+	}
+
+	if src == "" {
+		src = params.EmptySecrets
+	}
+
+	updated, err := params.Set(path, src, ParamsKey(c, options), encrypted, paramsComponentRoot)
+	if err != nil {
+		return errors.Wrap(err, "update secrets")
+	}
+
+	if err := afero.WriteFile(n.app.Fs(), n.SecretsPath(), []byte(updated), 0600); err != nil {
+		return err
+	}
+
+	// The param may not have a plain text value.
+	if err := c.DeleteParam(path, options); err != nil && !params.IsNotFound(err) {
+		return errors.Wrap(err, "remove plain text param")
+	}
+
+	return nil
+}
+
+// secretParams returns the secret param paths in the namespace keyed by params
+// key.
+func (n *Namespace) secretParams() (map[string][]string, error) {
+	src, err := n.Secrets()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string][]string)
+	if src == "" {
+		return secrets, nil
+	}
+
+	m, err := params.ToMap("", src, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "read secrets")
+	}
+
+	for key, v := range m {
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, pp := range mapToPaths(values, nil, nil) {
+			secrets[key] = append(secrets[key], strings.Join(pp.path, "."))
+		}
+	}
+
+	return secrets, nil
+}
+
+// maskSecrets replaces listed params which are secret with a mask. Secrets
+// which aren't listed are added.
+func (n *Namespace) maskSecrets(components []Component, nsps []NamespaceParameter) ([]NamespaceParameter, error) {
+	secrets, err := n.secretParams()
+	if err != nil {
+		return nil, err
+	}
+
+	for key, paths := range secrets {
+		name, index, ok := paramsKeyOwner(components, key)
+		if !ok {
+			continue
+		}
+
+		for _, path := range paths {
+			np := NamespaceParameter{
+				Component: name,
+				Index:     index,
+				Key:       path,
+				Value:     params.SecretMask,
+				RawValue:  params.SecretMask,
+				Secret:    true,
+			}
+
+			found := false
+			for i := range nsps {
+				if nsps[i].Component == name && nsps[i].Index == index && nsps[i].Key == path {
+					np.Source = nsps[i].Source
+					nsps[i] = np
+					found = true
+				}
+			}
+
+			if !found {
+				nsps = append(nsps, np)
+			}
+		}
+	}
+
+	return nsps, nil
+}
+
+// isSecret reports whether the param at path, or a value inside it, is secret.
+func isSecret(paths []string, path []string) bool {
+	p := strings.Join(path, ".")
+	for _, secret := range paths {
+		if secret == p || strings.HasPrefix(secret, p+".") || strings.HasPrefix(p, secret+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,150 @@
+package component
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// setSecretKeyPath sets the secret key location for a test.
+func setSecretKeyPath(t *testing.T, path string) {
+	require.NoError(t, os.Setenv(SecretKeyEnvVar, path))
+}
+
+func TestSecretKeyPath(t *testing.T) {
+	defer os.Unsetenv(SecretKeyEnvVar)
+	app, _ := appMock("/app")
+
+	cases := []struct {
+		name     string
+		path     string
+		expected string
+		isErr    bool
+	}{
+		{name: "not set", isErr: true},
+		{name: "outside of the app", path: "/keys/app.key", expected: "/keys/app.key"},
+		{name: "sibling of the app", path: "/app-keys/app.key", expected: "/app-keys/app.key"},
+		{name: "inside the app", path: "/app/.kscomp/secret.key", isErr: true},
+		{name: "inside the app after cleaning", path: "/keys/../app/secret.key", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setSecretKeyPath(t, tc.path)
+
+			got, err := SecretKeyPath(app)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestNamespace_SetSecretParam(t *testing.T) {
+	setSecretKeyPath(t, "/keys/app.key")
+	defer os.Unsetenv(SecretKeyEnvVar)
+
+	app, fs := appMock("/app")
+
+	files := []string{"guestbook-ui.jsonnet", "params.libsonnet"}
+	for _, file := range files {
+		stageFile(t, fs, "guestbook/"+file, "/app/components/"+file)
+	}
+
+	c := NewJsonnet(app, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	require.NoError(t, ns.SetSecretParam(c, []string{"image"}, "private/image:1", ParamOptions{}))
+
+	key, err := ReadSecretKey(app)
+	require.NoError(t, err)
+
+	src, err := ns.Secrets()
+	require.NoError(t, err)
+	require.False(t, strings.Contains(src, "private/image:1"))
+
+	decrypted, err := params.DecryptSecrets(key, src)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"components": map[string]interface{}{
+			"guestbook-ui": map[string]interface{}{
+				"image": "private/image:1",
+			},
+		},
+	}
+	require.Equal(t, expected, decrypted)
+
+	b, err := afero.ReadFile(fs, "/app/components/params.libsonnet")
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(b), "image:"), "plain text value was not removed")
+
+	nsps, err := ns.Params()
+	require.NoError(t, err)
+
+	var found bool
+	for _, p := range nsps {
+		if p.Key == "image" {
+			found = true
+			require.True(t, p.Secret)
+			require.Equal(t, params.SecretMask, p.Value)
+		}
+	}
+	require.True(t, found)
+
+	// A param without a plain text value can be secret.
+	require.NoError(t, ns.SetSecretParam(c, []string{"db", "password"}, "secret", ParamOptions{}))
+
+	exists, err := afero.Exists(fs, "/app/.kscomp/secret.key")
+	require.NoError(t, err)
+	require.False(t, exists, "secret key was written in the app")
+}
+
+func TestNamespace_SetSecretParam_index(t *testing.T) {
+	setSecretKeyPath(t, "/keys/app.key")
+	defer os.Unsetenv(SecretKeyEnvVar)
+
+	app, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+
+	c := NewJsonnet(app, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	err = ns.SetSecretParam(c, []string{"ports", "[0]", "password"}, "secret", ParamOptions{})
+	require.EqualError(t, err, "secret param ports[0].password is inside an array")
+
+	exists, err := afero.Exists(fs, ns.SecretsPath())
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestNamespace_SetSecretParam_noKeyPath(t *testing.T) {
+	os.Unsetenv(SecretKeyEnvVar)
+
+	app, fs := appMock("/app")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/params.libsonnet")
+
+	c := NewJsonnet(app, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+
+	ns, err := GetNamespace(app, "")
+	require.NoError(t, err)
+
+	err = ns.SetSecretParam(c, []string{"image"}, "private/image:1", ParamOptions{})
+	require.Error(t, err)
+
+	exists, err := afero.Exists(fs, ns.SecretsPath())
+	require.NoError(t, err)
+	require.False(t, exists)
+}
@@ -0,0 +1,6 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components +: {
+  },
+}
@@ -0,0 +1,12 @@
+local port = 80;
+
+{
+  global: {
+  },
+  components: {
+    "guestbook-ui": {
+      containerPort: port,
+      replicas: 1,
+    },
+  },
+}
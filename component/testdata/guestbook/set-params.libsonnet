@@ -1,5 +1,7 @@
 {
   global: {
+    // User-defined global parameters; accessible to all component and environments, Ex:
+    // replicas: 4,
   },
   components: {
     // Component-level parameters, defined initially from 'ks prototype use ...'
@@ -8,12 +10,10 @@
       containerPort: 80,
       image: "gcr.io/heptio-images/ks-guestbook-demo:0.1",
       name: "guiroot",
-      obj: {
-        a: "b",
-      },
       replicas: 4,
       servicePort: 80,
       type: "ClusterIP",
+      obj: {a: "b"},
     },
   },
-}
\ No newline at end of file
+}
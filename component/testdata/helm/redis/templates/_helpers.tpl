@@ -0,0 +1,8 @@
+{{- define "redis.fullname" -}}
+{{- printf "%s-%s" .Release.Name .Chart.Name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{- define "redis.labels" -}}
+app: {{ template "redis.fullname" . }}
+chart: {{ .Chart.Name }}-{{ .Chart.Version }}
+{{- end -}}
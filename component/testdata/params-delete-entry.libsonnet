@@ -1,5 +1,7 @@
 {
   global: {
+    // User-defined global parameters; accessible to all component and environments, Ex:
+    // replicas: 4,
   },
   components: {
     // Component-level parameters, defined initially from 'ks prototype use ...'
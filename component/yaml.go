@@ -120,6 +120,11 @@ func (y *YAML) Params() ([]NamespaceParameter, error) {
 		return nil, errors.Wrap(err, "could not find components")
 	}
 
+	computed, err := params.Computed("", paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	re, err := regexp.Compile(fmt.Sprintf(`^%s-(\d+)$`, y.Name(false)))
 	if err != nil {
 		return nil, err
@@ -155,7 +160,7 @@ func (y *YAML) Params() ([]NamespaceParameter, error) {
 				return nil, errors.Errorf("component value for %q was not a map", componentName)
 			}
 
-			childParams, err := y.paramValues(y.Name(false), index, valueMap, m, nil)
+			childParams, err := y.paramValues(y.Name(false), index, valueMap, m, nil, computed.Child(componentName))
 			if err != nil {
 				return nil, err
 			}
@@ -164,6 +169,8 @@ func (y *YAML) Params() ([]NamespaceParameter, error) {
 		}
 	}
 
+	sortParams(params)
+
 	return params, nil
 }
 
@@ -178,7 +185,7 @@ func isLeaf(path []string, key string, valueMap map[string]Values) (string, bool
 	return "", false
 }
 
-func (y *YAML) paramValues(componentName, index string, valueMap map[string]Values, m map[string]interface{}, path []string) ([]NamespaceParameter, error) {
+func (y *YAML) paramValues(componentName, index string, valueMap map[string]Values, m map[string]interface{}, path []string, computed params.ComputedParams) ([]NamespaceParameter, error) {
 	var params []NamespaceParameter
 
 	for k, v := range m {
@@ -192,6 +199,8 @@ func (y *YAML) paramValues(componentName, index string, valueMap map[string]Valu
 					Index:     index,
 					Key:       childPath,
 					Value:     s,
+					RawValue:  v,
+					Computed:  computed.Has(append(path, k)),
 				}
 				params = append(params, p)
 			}
@@ -208,11 +217,13 @@ func (y *YAML) paramValues(componentName, index string, valueMap map[string]Valu
 					Index:     index,
 					Key:       childPath,
 					Value:     s,
+					RawValue:  v,
+					Computed:  computed.Has(append(path, k)),
 				}
 				params = append(params, p)
 			} else {
 				childPath := append(path, k)
-				childParams, err := y.paramValues(componentName, index, valueMap, t, childPath)
+				childParams, err := y.paramValues(componentName, index, valueMap, t, childPath, computed)
 				if err != nil {
 					return nil, err
 				}
@@ -231,6 +242,8 @@ func (y *YAML) paramValues(componentName, index string, valueMap map[string]Valu
 					Index:     index,
 					Key:       childPath,
 					Value:     s,
+					RawValue:  v,
+					Computed:  computed.Has(append(path, k)),
 				}
 				params = append(params, p)
 			}
@@ -268,7 +281,12 @@ func (y *YAML) SetParam(path []string, value interface{}, options ParamOptions)
 		return err
 	}
 
-	updatedParams, err := params.Set(path, paramsData, entry, value, paramsComponentRoot)
+	schema, err := readSchema(y.app.Fs(), y.paramsPath)
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Set(path, paramsData, entry, value, paramsComponentRoot, params.WithSchema(schema), params.WithForce(options.Force))
 	if err != nil {
 		return err
 	}
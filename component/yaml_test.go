@@ -63,6 +63,7 @@ func TestYAML_Params(t *testing.T) {
 		Index:     "0",
 		Key:       "metadata.labels",
 		Value:     `{"label1":"label1","label2":"label2"}`,
+		RawValue:  map[string]interface{}{"label1": "label1", "label2": "label2"},
 	}
 	require.Equal(t, expected, param)
 }
@@ -86,6 +87,7 @@ func TestYAML_Params_literal(t *testing.T) {
 		Index:     "1",
 		Key:       "metadata.name",
 		Value:     "cert-manager2",
+		RawValue:  "cert-manager2",
 	}
 	require.Equal(t, expected, param)
 }
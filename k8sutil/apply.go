@@ -1,9 +1,10 @@
 package k8sutil
 
 import (
-	"encoding/json"
 	"fmt"
-	"sort"
+	"io"
+	"os"
+	"time"
 
 	"github.com/bryanl/woowoo/pkg/client"
 	"github.com/ksonnet/ksonnet/utils"
@@ -14,8 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
 	kdiff "k8s.io/apimachinery/pkg/util/diff"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
@@ -46,6 +47,31 @@ type ApplyCmd struct {
 	GcTag        string
 	SkipGc       bool
 	DryRun       bool
+
+	Strategy       client.ApplyStrategy
+	FieldManager   string
+	ForceConflicts bool
+
+	// Wait waits for applied objects to become ready.
+	Wait        bool
+	WaitTimeout time.Duration
+	Out         io.Writer
+
+	// RollbackOnFailure reverts applied objects if applying or waiting fails.
+	RollbackOnFailure bool
+
+	Retry client.RetryOptions
+
+	// Parallelism is the number of objects in a dependency wave applied concurrently.
+	Parallelism int
+
+	// Inventory records applied objects in an inventory ConfigMap. Objects in the
+	// previous inventory which are no longer applied are garbage collected.
+	Inventory bool
+
+	// Plan is the format (json or yaml) of a plan describing the changes which
+	// would be made. Setting it implies a dry run.
+	Plan string
 }
 
 // Run applies the components to the designated environment cluster.
@@ -55,62 +81,159 @@ func (c ApplyCmd) Run(apiObjects []*unstructured.Unstructured, wd string) error
 		return err
 	}
 
+	var plan *Plan
+	if c.Plan != "" {
+		c.DryRun = true
+		plan = &Plan{Environment: c.Env, Objects: []PlanObject{}}
+	}
+
 	dryRunText := ""
 	if c.DryRun {
 		dryRunText = " (dry-run)"
 	}
 
-	sort.Sort(utils.DependencyOrder(apiObjects))
+	var ssa *serverSideApplier
+	if c.Strategy == client.ApplyStrategyServerSide {
+		ssa, err = newServerSideApplier(c.ClientConfig, discovery, namespace, c.FieldManager, c.ForceConflicts)
+		if err != nil {
+			return err
+		}
+	}
 
 	seenUids := sets.NewString()
+	var targets []*readyTarget
+
+	var rb *rollback
+	if c.RollbackOnFailure && !c.DryRun {
+		rb = &rollback{retry: c.Retry}
+	}
 
 	for _, obj := range apiObjects {
-		if c.GcTag != "" {
-			utils.SetMetaDataAnnotation(obj, AnnotationGcTag, c.GcTag)
+		setGcTag(obj, c.GcTag)
+
+		if err = SetLastApplied(obj); err != nil {
+			return err
 		}
+	}
 
-		desc := fmt.Sprintf("%s %s", utils.ResourceNameFor(discovery, obj), utils.FqName(obj))
-		log.Info("Updating ", desc, dryRunText)
+	apply := func(obj *unstructured.Unstructured, logger log.FieldLogger) applyResult {
+		result := applyResult{
+			desc: fmt.Sprintf("%s %s", utils.ResourceNameFor(discovery, obj), utils.FqName(obj)),
+		}
+		logger.Info("Updating ", result.desc, dryRunText)
 
-		rc, err := utils.ClientForResource(clientPool, discovery, obj, namespace)
-		if err != nil {
-			return err
+		result.rc, result.err = utils.ClientForResource(clientPool, discovery, obj, namespace)
+		if result.err != nil {
+			logger.Error(result.err)
+			return result
 		}
 
-		asPatch, err := json.Marshal(obj)
-		if err != nil {
-			return err
+		if rb != nil {
+			if result.live, result.err = rb.take(result.rc, obj.GetName(), logger); result.err != nil {
+				result.err = fmt.Errorf("Error fetching %s: %s", result.desc, result.err)
+				logger.Error(result.err)
+				return result
+			}
 		}
-		var newobj metav1.Object
-		if !c.DryRun {
-			newobj, err = rc.Patch(obj.GetName(), types.MergePatchType, asPatch)
-			log.Debugf("Patch(%s) returned (%v, %v)", obj.GetName(), newobj, err)
-		} else {
-			newobj, err = rc.Get(obj.GetName(), metav1.GetOptions{})
+
+		result.newobj, result.err = c.applyObject(result.rc, ssa, obj, result.desc, dryRunText, logger)
+		if result.err != nil {
+			result.err = fmt.Errorf("Error updating %s: %s", result.desc, result.err)
+			logger.Error(result.err)
+			return result
 		}
-		if c.Create && errors.IsNotFound(err) {
-			log.Info(" Creating non-existent ", desc, dryRunText)
-			if !c.DryRun {
-				newobj, err = rc.Create(obj)
-				log.Debugf("Create(%s) returned (%v, %v)", obj.GetName(), newobj, err)
-			} else {
-				newobj = obj
-				err = nil
+
+		logger.Debug("Updated object: ", kdiff.ObjectDiff(obj, result.newobj))
+
+		if plan != nil {
+			entry, err := planObject(result.rc, obj)
+			if err != nil {
+				result.err = fmt.Errorf("Error planning %s: %s", result.desc, err)
+				logger.Error(result.err)
+				return result
 			}
+			result.plan = &entry
 		}
-		if err != nil {
-			// TODO: retry
-			return fmt.Errorf("Error updating %s: %s", desc, err)
+
+		return result
+	}
+
+	// Failures are collected so the remaining objects are still applied. With
+	// rollback enabled, the run stops after the first wave with a failure.
+	var errs []error
+	var refs []ObjectRef
+	for _, wave := range dependencyWaves(apiObjects) {
+		results := runWave(wave, c.Parallelism, apply)
+
+		for i, result := range results {
+			if result.err != nil {
+				errs = append(errs, result.err)
+				continue
+			}
+
+			if rb != nil {
+				rb.record(result.desc, wave[i].GetName(), result.rc, result.live)
+			}
+
+			// Some objects appear under multiple kinds
+			// (eg: Deployment is both extensions/v1beta1
+			// and apps/v1beta1).  UID is the only stable
+			// identifier that links these two views of
+			// the same object.
+			seenUids.Insert(string(result.newobj.GetUID()))
+
+			ref := ObjectRef{
+				APIVersion: wave[i].GetAPIVersion(),
+				Kind:       wave[i].GetKind(),
+				Namespace:  result.newobj.GetNamespace(),
+				Name:       wave[i].GetName(),
+			}
+			refs = append(refs, ref)
+
+			if result.plan != nil {
+				plan.Objects = append(plan.Objects, *result.plan)
+			}
+
+			if c.Wait && !c.DryRun {
+				targets = append(targets, &readyTarget{obj: wave[i], rc: result.rc})
+			}
+		}
+
+		if len(errs) > 0 && rb != nil {
+			return rollbackOnFailure(rb, utilerrors.NewAggregate(errs))
+		}
+	}
+
+	if len(errs) > 0 {
+		log.Info("Skipping garbage collection and readiness checks because objects failed to apply")
+		return utilerrors.NewAggregate(errs)
+	}
+
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	// Objects are pruned and the inventory is updated only once the applied
+	// objects are ready, so a rollback restores the previous state.
+	if len(targets) > 0 {
+		log.Info("Waiting for objects to become ready")
+		if err := waitForReady(out, targets, c.WaitTimeout); err != nil {
+			return rollbackOnFailure(rb, err)
 		}
+	}
 
-		log.Debug("Updated object: ", kdiff.ObjectDiff(obj, newobj))
+	var inv *inventory
+	var previous []ObjectRef
+	hasInventory := false
+	if c.Inventory {
+		if inv, err = newInventory(clientPool, namespace, c.Env); err != nil {
+			return err
+		}
 
-		// Some objects appear under multiple kinds
-		// (eg: Deployment is both extensions/v1beta1
-		// and apps/v1beta1).  UID is the only stable
-		// identifier that links these two views of
-		// the same object.
-		seenUids.Insert(string(newobj.GetUID()))
+		if previous, hasInventory, err = inv.read(); err != nil {
+			return err
+		}
 	}
 
 	if c.GcTag != "" && !c.SkipGc {
@@ -119,33 +242,130 @@ func (c ApplyCmd) Run(apiObjects []*unstructured.Unstructured, wd string) error
 			return err
 		}
 
-		err = walkObjects(clientPool, discovery, metav1.ListOptions{}, func(o runtime.Object) error {
-			meta, err := meta.Accessor(o)
-			if err != nil {
-				return err
-			}
-			gvk := o.GetObjectKind().GroupVersionKind()
-			desc := fmt.Sprintf("%s %s (%s)", utils.ResourceNameFor(discovery, o), utils.FqName(meta), gvk.GroupVersion())
-			log.Debugf("Considering %v for gc", desc)
-			if eligibleForGc(meta, c.GcTag) && !seenUids.Has(string(meta.GetUID())) {
-				log.Info("Garbage collecting ", desc, dryRunText)
-				if !c.DryRun {
-					err := gcDelete(clientPool, discovery, &version, o)
-					if err != nil {
-						return err
-					}
-				}
+		collect := func(ref ObjectRef) {
+			if plan != nil {
+				plan.Objects = append(plan.Objects, PlanObject{
+					APIVersion: ref.APIVersion,
+					Kind:       ref.Kind,
+					Namespace:  ref.Namespace,
+					Name:       ref.Name,
+					Action:     PlanGarbageCollect,
+				})
 			}
-			return nil
-		})
+		}
+
+		if hasInventory {
+			err = pruneInventory(clientPool, discovery, &version, namespace, previous, refs, seenUids, c.GcTag, dryRunText, c.DryRun, collect)
+		} else {
+			err = c.gcWalk(clientPool, discovery, &version, seenUids, dryRunText, collect)
+		}
 		if err != nil {
 			return err
 		}
 	}
 
+	if inv != nil && !c.DryRun {
+		if c.GcTag == "" || c.SkipGc {
+			// Objects which were not pruned stay in the inventory so they can be
+			// pruned by a later run.
+			refs = mergeRefs(previous, refs)
+		}
+
+		if err := inv.write(refs); err != nil {
+			return fmt.Errorf("Error updating inventory: %s", err)
+		}
+	}
+
+	if plan != nil {
+		return plan.Write(out, c.Plan)
+	}
+
 	return nil
 }
 
+// gcWalk garbage collects objects by listing objects with the garbage collection
+// tag. Objects applied before the tag label was added only have the tag
+// annotation, so objects without the label are listed as well and matched by
+// annotation. If the tag can't be used as a label selector, all objects are
+// listed. collect is called for each object which is garbage collected.
+func (c ApplyCmd) gcWalk(clientPool dynamic.ClientPool, discovery discovery.DiscoveryInterface, version *utils.ServerVersion, seenUids sets.String, dryRunText string, collect func(ObjectRef)) error {
+	selectors := []string{""}
+	if selector, ok := gcLabelSelector(c.GcTag); ok {
+		selectors = []string{selector, "!" + LabelGcTag}
+	} else {
+		log.Warnf("Garbage collection tag %q is not a valid label value, listing all objects", c.GcTag)
+	}
+
+	visit := c.gcVisitor(clientPool, discovery, version, seenUids, dryRunText, collect)
+	for _, selector := range selectors {
+		listOpts := metav1.ListOptions{LabelSelector: selector}
+		if err := walkObjects(clientPool, discovery, listOpts, visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gcVisitor returns a callback for walkObjects which garbage collects objects
+// with the garbage collection tag which weren't applied.
+func (c ApplyCmd) gcVisitor(clientPool dynamic.ClientPool, discovery discovery.DiscoveryInterface, version *utils.ServerVersion, seenUids sets.String, dryRunText string, collect func(ObjectRef)) func(runtime.Object) error {
+	return func(o runtime.Object) error {
+		meta, err := meta.Accessor(o)
+		if err != nil {
+			return err
+		}
+		gvk := o.GetObjectKind().GroupVersionKind()
+		desc := fmt.Sprintf("%s %s (%s)", utils.ResourceNameFor(discovery, o), utils.FqName(meta), gvk.GroupVersion())
+		log.Debugf("Considering %v for gc", desc)
+		if eligibleForGc(meta, c.GcTag) && !seenUids.Has(string(meta.GetUID())) {
+			log.Info("Garbage collecting ", desc, dryRunText)
+			collect(ObjectRef{
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+				Namespace:  meta.GetNamespace(),
+				Name:       meta.GetName(),
+			})
+			if !c.DryRun {
+				err := gcDelete(clientPool, discovery, version, o)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// applyObject updates an object, creating it if it does not exist. Transient
+// errors and conflicts are retried.
+func (c ApplyCmd) applyObject(rc dynamic.ResourceInterface, ssa *serverSideApplier, obj *unstructured.Unstructured, desc, dryRunText string, logger log.FieldLogger) (metav1.Object, error) {
+	var newobj metav1.Object
+	err := withRetry(c.Retry, logger, desc, func() error {
+		var err error
+		if !c.DryRun {
+			newobj, err = patchObject(rc, ssa, c.Strategy, obj, logger)
+			logger.Debugf("Patch(%s) returned (%v, %v)", obj.GetName(), newobj, err)
+		} else {
+			newobj, err = rc.Get(obj.GetName(), metav1.GetOptions{})
+		}
+		if c.Create && errors.IsNotFound(err) {
+			logger.Info(" Creating non-existent ", desc, dryRunText)
+			if !c.DryRun {
+				newobj, err = rc.Create(obj)
+				logger.Debugf("Create(%s) returned (%v, %v)", obj.GetName(), newobj, err)
+			} else {
+				newobj = obj
+				err = nil
+			}
+		}
+
+		return err
+	})
+
+	return newobj, err
+}
+
 func stringListContains(list []string, value string) bool {
 	for _, item := range list {
 		if item == value {
@@ -0,0 +1,68 @@
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestApplyCmd_gcWalk(t *testing.T) {
+	cases := []struct {
+		name      string
+		tag       string
+		dryRun    bool
+		collected []ObjectRef
+		deleted   []string
+	}{
+		{
+			name:      "labeled and annotated objects",
+			tag:       "prod",
+			collected: []ObjectRef{ref("removed"), ref("legacy")},
+			deleted:   []string{"removed", "legacy"},
+		},
+		{
+			name:      "dry run",
+			tag:       "prod",
+			dryRun:    true,
+			collected: []ObjectRef{ref("removed"), ref("legacy")},
+		},
+		{
+			name:      "tag which isn't a label value",
+			tag:       "prod env",
+			collected: []ObjectRef{ref("spaced")},
+			deleted:   []string{"spaced"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := newFakeCluster()
+			rc := cluster.add(deploymentGVK, "deployments", true,
+				gcObject("applied", "prod", true),
+				gcObject("legacy", "prod", false),
+				gcObject("other", "staging", true),
+				gcObject("removed", "prod", true),
+				gcObject("spaced", "prod env", false),
+				gcObject("untagged", "", false),
+			)
+
+			seen := sets.NewString(string(rc.object("applied").GetUID()))
+			version := cluster.serverVersion()
+
+			c := ApplyCmd{GcTag: tc.tag, DryRun: tc.dryRun}
+
+			var collected []ObjectRef
+			err := c.gcWalk(cluster, cluster.disco, &version, seen, "", func(r ObjectRef) {
+				collected = append(collected, r)
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.collected, collected)
+
+			deleted := sets.NewString(tc.deleted...)
+			for _, name := range []string{"applied", "legacy", "other", "removed", "spaced", "untagged"} {
+				require.Equal(t, deleted.Has(name), rc.object(name) == nil, name)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/bryanl/woowoo/pkg/client"
 	"github.com/ksonnet/ksonnet/utils"
@@ -33,6 +34,7 @@ type DeleteCmd struct {
 	ClientConfig *client.Config
 	Env          string
 	GracePeriod  int64
+	Retry        client.RetryOptions
 }
 
 func (c DeleteCmd) Run(apiObjects []*unstructured.Unstructured) error {
@@ -62,22 +64,30 @@ func (c DeleteCmd) Run(apiObjects []*unstructured.Unstructured) error {
 		deleteOpts.GracePeriodSeconds = &c.GracePeriod
 	}
 
+	var errs []error
 	for _, obj := range apiObjects {
 		desc := fmt.Sprintf("%s %s", utils.ResourceNameFor(discovery, obj), utils.FqName(obj))
 		log.Info("Deleting ", desc)
 
 		client, err := utils.ClientForResource(clientPool, discovery, obj, namespace)
 		if err != nil {
-			return err
+			log.Error(err)
+			errs = append(errs, err)
+			continue
 		}
 
-		err = client.Delete(obj.GetName(), &deleteOpts)
+		err = withRetry(c.Retry, log.StandardLogger(), desc, func() error {
+			return client.Delete(obj.GetName(), &deleteOpts)
+		})
 		if err != nil && !errors.IsNotFound(err) {
-			return fmt.Errorf("Error deleting %s: %s", desc, err)
+			err = fmt.Errorf("Error deleting %s: %s", desc, err)
+			log.Error(err)
+			errs = append(errs, err)
+			continue
 		}
 
 		log.Debugf("Deleted object: ", obj)
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
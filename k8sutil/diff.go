@@ -0,0 +1,226 @@
+package k8sutil
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pkg/client"
+	"github.com/bryanl/woowoo/pkg/util/merge"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/utils"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// AnnotationLastApplied contains the configuration of the object the last
+	// time it was applied. It is used to detect fields which have been removed.
+	AnnotationLastApplied = "kubecfg.ksonnet.io/last-applied-configuration"
+)
+
+// DiffCmd compares objects with their live versions in a cluster.
+type DiffCmd struct {
+	ClientConfig *client.Config
+	Env          string
+	Out          io.Writer
+	Color        bool
+
+	// GcTag is the gc tag objects are applied with. It is added to the
+	// desired objects so tagged live objects aren't reported as changed.
+	GcTag string
+}
+
+// Run prints a diff for each object which differs from the live object in the
+// environment cluster. It returns an error if any object has drifted.
+func (c DiffCmd) Run(apiObjects []*unstructured.Unstructured) error {
+	clientPool, discovery, namespace, err := c.ClientConfig.RestClient(&c.Env)
+	if err != nil {
+		return err
+	}
+
+	sort.Sort(utils.DependencyOrder(apiObjects))
+
+	drifted := 0
+	for _, obj := range apiObjects {
+		desc := fmt.Sprintf("%s %s", utils.ResourceNameFor(discovery, obj), utils.FqName(obj))
+		log.Debugf("Comparing %s", desc)
+
+		rc, err := utils.ClientForResource(clientPool, discovery, obj, namespace)
+		if err != nil {
+			return err
+		}
+
+		var live *unstructured.Unstructured
+		existing, err := rc.Get(obj.GetName(), metav1.GetOptions{})
+		if err == nil {
+			live = existing
+		} else if !errors.IsNotFound(err) {
+			return fmt.Errorf("Error fetching %s: %s", desc, err)
+		}
+
+		desired := obj.DeepCopy()
+		setGcTag(desired, c.GcTag)
+
+		from, to, err := DiffObjects(desired, live)
+		if err != nil {
+			return fmt.Errorf("Error comparing %s: %s", desc, err)
+		}
+
+		changed, err := ksutil.FprintDiff(c.Out, from, to, "live/"+desc, "desired/"+desc, c.Color)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			drifted++
+		}
+	}
+
+	if drifted > 0 {
+		return fmt.Errorf("%d of %d objects differ from environment %q", drifted, len(apiObjects), c.Env)
+	}
+
+	return nil
+}
+
+// DiffObjects returns YAML representations of a live object and of what the live
+// object will be after the desired object is applied. Fields which were present
+// in the last applied configuration but are absent from the desired object are
+// removed. If the live object is nil, the object will be created.
+func DiffObjects(desired, live *unstructured.Unstructured) (string, string, error) {
+	if live == nil {
+		to, err := objectYAML(desired.Object)
+		return "", to, err
+	}
+
+	liveObject, expected, err := expectedObject(desired, live)
+	if err != nil {
+		return "", "", err
+	}
+
+	from, err := objectYAML(liveObject)
+	if err != nil {
+		return "", "", err
+	}
+
+	to, err := objectYAML(expected)
+	if err != nil {
+		return "", "", err
+	}
+
+	return from, to, nil
+}
+
+// expectedObject returns copies of the live object and of the live object after
+// the desired object is applied. Registered kinds are merged strategically, so
+// fields the server set inside list items are kept. The last applied annotation
+// is removed from both.
+func expectedObject(desired, live *unstructured.Unstructured) (map[string]interface{}, map[string]interface{}, error) {
+	liveObject := merge.DeepCopy(live.Object)
+
+	lastApplied, err := lastAppliedConfiguration(live)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if lastApplied != nil {
+		lastApplied = foldStringData(lastApplied)
+	}
+
+	expected, err := merge.ThreeWay(lastApplied, foldStringData(desired.Object), liveObject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, m := range []map[string]interface{}{liveObject, expected} {
+		removeLastApplied(m)
+	}
+
+	return liveObject, expected, nil
+}
+
+// foldStringData returns a copy of a Secret with its stringData encoded into
+// data, as the server stores it. Other objects are returned unchanged.
+func foldStringData(obj map[string]interface{}) map[string]interface{} {
+	stringData, ok := obj["stringData"].(map[string]interface{})
+	if kind, _ := obj["kind"].(string); kind != "Secret" || !ok {
+		return obj
+	}
+
+	folded := merge.DeepCopy(obj)
+	delete(folded, "stringData")
+
+	data, ok := folded["data"].(map[string]interface{})
+	if !ok {
+		data = make(map[string]interface{})
+		folded["data"] = data
+	}
+
+	for k, v := range stringData {
+		s, _ := v.(string)
+		data[k] = base64.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	return folded
+}
+
+// SetLastApplied records the configuration of an object in its last applied
+// annotation.
+func SetLastApplied(obj *unstructured.Unstructured) error {
+	o := merge.DeepCopy(obj.Object)
+	removeLastApplied(o)
+
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	utils.SetMetaDataAnnotation(obj, AnnotationLastApplied, string(data))
+	return nil
+}
+
+func lastAppliedConfiguration(obj *unstructured.Unstructured) (map[string]interface{}, error) {
+	data, ok := obj.GetAnnotations()[AnnotationLastApplied]
+	if !ok || data == "" {
+		return nil, nil
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %s", AnnotationLastApplied, err)
+	}
+
+	return m, nil
+}
+
+func removeLastApplied(m map[string]interface{}) {
+	metadata, ok := m["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	delete(annotations, AnnotationLastApplied)
+	if len(annotations) == 0 {
+		delete(metadata, "annotations")
+	}
+}
+
+func objectYAML(m map[string]interface{}) (string, error) {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
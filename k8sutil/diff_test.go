@@ -0,0 +1,132 @@
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+func decodeObject(t *testing.T, s string) *unstructured.Unstructured {
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &m))
+	return &unstructured.Unstructured{Object: m}
+}
+
+const desiredDeployment = `{
+  "apiVersion": "apps/v1beta2",
+  "kind": "Deployment",
+  "metadata": {"name": "web", "namespace": "default"},
+  "spec": {
+    "replicas": 2,
+    "template": {
+      "metadata": {"labels": {"app": "web"}},
+      "spec": {
+        "containers": [
+          {"name": "web", "image": "nginx:1.13", "ports": [{"containerPort": 80}]}
+        ]
+      }
+    }
+  }
+}`
+
+// liveDeployment returns desiredDeployment as the server returns it after it
+// was applied.
+func liveDeployment(t *testing.T) *unstructured.Unstructured {
+	live := decodeObject(t, `{
+  "apiVersion": "apps/v1beta2",
+  "kind": "Deployment",
+  "metadata": {"name": "web", "namespace": "default", "uid": "1234", "resourceVersion": "5", "generation": 1},
+  "spec": {
+    "replicas": 2,
+    "template": {
+      "metadata": {"labels": {"app": "web"}},
+      "spec": {
+        "containers": [
+          {
+            "name": "web",
+            "image": "nginx:1.13",
+            "imagePullPolicy": "IfNotPresent",
+            "terminationMessagePath": "/dev/termination-log",
+            "ports": [{"containerPort": 80, "protocol": "TCP"}]
+          }
+        ],
+        "restartPolicy": "Always"
+      }
+    }
+  },
+  "status": {"replicas": 2}
+}`)
+
+	lastApplied := decodeObject(t, desiredDeployment)
+	require.NoError(t, SetLastApplied(lastApplied))
+	live.SetAnnotations(lastApplied.GetAnnotations())
+
+	return live
+}
+
+// container returns the first container in a deployment.
+func container(obj *unstructured.Unstructured) map[string]interface{} {
+	spec := obj.Object["spec"].(map[string]interface{})
+	podSpec := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	return podSpec["containers"].([]interface{})[0].(map[string]interface{})
+}
+
+func TestDiffObjects(t *testing.T) {
+	t.Run("unchanged deployment", func(t *testing.T) {
+		from, to, err := DiffObjects(decodeObject(t, desiredDeployment), liveDeployment(t))
+		require.NoError(t, err)
+		require.Equal(t, from, to)
+		require.NotContains(t, from, AnnotationLastApplied)
+	})
+
+	t.Run("gc tagged deployment", func(t *testing.T) {
+		lastApplied := decodeObject(t, desiredDeployment)
+		setGcTag(lastApplied, "prod")
+		require.NoError(t, SetLastApplied(lastApplied))
+
+		live := liveDeployment(t)
+		live.SetLabels(lastApplied.GetLabels())
+		live.SetAnnotations(lastApplied.GetAnnotations())
+
+		desired := decodeObject(t, desiredDeployment)
+		from, to, err := DiffObjects(desired, live)
+		require.NoError(t, err)
+		require.NotEqual(t, from, to, "untagged objects drop the tag")
+
+		setGcTag(desired, "prod")
+		from, to, err = DiffObjects(desired, live)
+		require.NoError(t, err)
+		require.Equal(t, from, to)
+		require.Contains(t, to, LabelGcTag+": prod")
+	})
+
+	t.Run("changed image", func(t *testing.T) {
+		desired := decodeObject(t, desiredDeployment)
+		container(desired)["image"] = "nginx:1.14"
+
+		from, to, err := DiffObjects(desired, liveDeployment(t))
+		require.NoError(t, err)
+		require.Contains(t, from, "image: nginx:1.13")
+		require.Contains(t, to, "image: nginx:1.14")
+		require.Contains(t, to, "imagePullPolicy: IfNotPresent")
+	})
+
+	t.Run("removed field", func(t *testing.T) {
+		desired := decodeObject(t, desiredDeployment)
+		delete(desired.Object["spec"].(map[string]interface{}), "replicas")
+
+		from, to, err := DiffObjects(desired, liveDeployment(t))
+		require.NoError(t, err)
+		require.Contains(t, from, "replicas: 2\n  template")
+		require.NotContains(t, to, "replicas: 2\n  template")
+	})
+
+	t.Run("new object", func(t *testing.T) {
+		from, to, err := DiffObjects(decodeObject(t, desiredDeployment), nil)
+		require.NoError(t, err)
+		require.Equal(t, "", from)
+		require.Contains(t, to, "name: web")
+	})
+}
@@ -0,0 +1,338 @@
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bryanl/woowoo/pkg/util/merge"
+	"github.com/ksonnet/ksonnet/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// fakeResource is an in-memory dynamic.ResourceInterface for a single
+// resource. Objects are keyed by name.
+type fakeResource struct {
+	mu      sync.Mutex
+	gr      schema.GroupResource
+	objects map[string]*unstructured.Unstructured
+	// errs are returned by successive calls to a verb before it runs.
+	errs map[string][]error
+	// calls records each call as "verb name".
+	calls   []string
+	version int
+}
+
+var _ dynamic.ResourceInterface = (*fakeResource)(nil)
+
+func newFakeResource(resource string, objects ...*unstructured.Unstructured) *fakeResource {
+	f := &fakeResource{
+		gr:      schema.GroupResource{Resource: resource},
+		objects: make(map[string]*unstructured.Unstructured),
+		errs:    make(map[string][]error),
+	}
+
+	for _, obj := range objects {
+		f.store(obj.DeepCopy())
+	}
+
+	return f
+}
+
+// fail makes the next calls to a verb return errs.
+func (f *fakeResource) fail(verb string, errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[verb] = append(f.errs[verb], errs...)
+}
+
+// object returns a copy of a stored object, or nil if it doesn't exist.
+func (f *fakeResource) object(name string) *unstructured.Unstructured {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if obj, ok := f.objects[name]; ok {
+		return obj.DeepCopy()
+	}
+
+	return nil
+}
+
+func (f *fakeResource) called(verb, name string) error {
+	f.calls = append(f.calls, fmt.Sprintf("%s %s", verb, name))
+
+	if errs := f.errs[verb]; len(errs) > 0 {
+		f.errs[verb] = errs[1:]
+		return errs[0]
+	}
+
+	return nil
+}
+
+// store saves an object, assigning a uid and a new resource version.
+func (f *fakeResource) store(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	f.version++
+	if obj.GetUID() == "" {
+		obj.SetUID(types.UID("uid-" + obj.GetName()))
+	}
+	obj.SetResourceVersion(fmt.Sprint(f.version))
+
+	f.objects[obj.GetName()] = obj
+	return obj.DeepCopy()
+}
+
+func (f *fakeResource) List(opts metav1.ListOptions) (runtime.Object, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.called("list", ""); err != nil {
+		return nil, err
+	}
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{}
+	for _, name := range sortedNames(f.objects) {
+		obj := f.objects[name]
+		if selector.Matches(labels.Set(obj.GetLabels())) {
+			list.Items = append(list.Items, *obj.DeepCopy())
+		}
+	}
+
+	return list, nil
+}
+
+func (f *fakeResource) Get(name string, opts metav1.GetOptions) (*unstructured.Unstructured, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.called("get", name); err != nil {
+		return nil, err
+	}
+
+	obj, ok := f.objects[name]
+	if !ok {
+		return nil, errors.NewNotFound(f.gr, name)
+	}
+
+	return obj.DeepCopy(), nil
+}
+
+func (f *fakeResource) Delete(name string, opts *metav1.DeleteOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.called("delete", name); err != nil {
+		return err
+	}
+
+	if _, ok := f.objects[name]; !ok {
+		return errors.NewNotFound(f.gr, name)
+	}
+
+	delete(f.objects, name)
+	return nil
+}
+
+func (f *fakeResource) DeleteCollection(deleteOptions *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (f *fakeResource) Create(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.called("create", obj.GetName()); err != nil {
+		return nil, err
+	}
+
+	if _, ok := f.objects[obj.GetName()]; ok {
+		return nil, errors.NewAlreadyExists(f.gr, obj.GetName())
+	}
+
+	return f.store(obj.DeepCopy()), nil
+}
+
+func (f *fakeResource) Update(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.called("update", obj.GetName()); err != nil {
+		return nil, err
+	}
+
+	existing, ok := f.objects[obj.GetName()]
+	if !ok {
+		return nil, errors.NewNotFound(f.gr, obj.GetName())
+	}
+
+	if rv := obj.GetResourceVersion(); rv != "" && rv != existing.GetResourceVersion() {
+		return nil, errors.NewConflict(f.gr, obj.GetName(), fmt.Errorf("resource version %s is stale", rv))
+	}
+
+	updated := obj.DeepCopy()
+	updated.SetUID(existing.GetUID())
+	return f.store(updated), nil
+}
+
+func (f *fakeResource) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeResource) Patch(name string, pt types.PatchType, data []byte) (*unstructured.Unstructured, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.called("patch", name); err != nil {
+		return nil, err
+	}
+
+	existing, ok := f.objects[name]
+	if !ok {
+		return nil, errors.NewNotFound(f.gr, name)
+	}
+
+	var patched map[string]interface{}
+	switch pt {
+	case types.MergePatchType:
+		var patch map[string]interface{}
+		if err := json.Unmarshal(data, &patch); err != nil {
+			return nil, err
+		}
+		patched = merge.JSON(merge.DeepCopy(existing.Object), patch).(map[string]interface{})
+	case types.StrategicMergePatchType:
+		dataStruct, err := scheme.Scheme.New(existing.GroupVersionKind())
+		if err != nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("unsupported patch type %s", pt))
+		}
+
+		current, err := json.Marshal(existing.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := strategicpatch.StrategicMergePatch(current, data, dataStruct)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(b, &patched); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("unsupported patch type %s", pt))
+	}
+
+	return f.store(&unstructured.Unstructured{Object: patched}), nil
+}
+
+func sortedNames(objects map[string]*unstructured.Unstructured) []string {
+	var names []string
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fakeCluster is a client pool and discovery for a set of fake resources.
+type fakeCluster struct {
+	resources map[schema.GroupVersionKind]*fakeResource
+	disco     *fake.FakeDiscovery
+}
+
+var _ dynamic.ClientPool = (*fakeCluster)(nil)
+
+func newFakeCluster() *fakeCluster {
+	return &fakeCluster{
+		resources: make(map[schema.GroupVersionKind]*fakeResource),
+		disco: &fake.FakeDiscovery{
+			Fake:               &ktesting.Fake{},
+			FakedServerVersion: &version.Info{Major: "1", Minor: "8", GitVersion: "v1.8.0"},
+		},
+	}
+}
+
+// add adds a listable resource to the cluster and returns its client.
+func (c *fakeCluster) add(gvk schema.GroupVersionKind, resource string, namespaced bool, objects ...*unstructured.Unstructured) *fakeResource {
+	rc := newFakeResource(resource, objects...)
+	c.resources[gvk] = rc
+
+	apiResource := metav1.APIResource{
+		Name:       resource,
+		Namespaced: namespaced,
+		Kind:       gvk.Kind,
+		Verbs:      []string{"get", "list", "create", "update", "patch", "delete"},
+	}
+
+	gv := gvk.GroupVersion().String()
+	for _, list := range c.disco.Resources {
+		if list.GroupVersion == gv {
+			list.APIResources = append(list.APIResources, apiResource)
+			return rc
+		}
+	}
+
+	c.disco.Resources = append(c.disco.Resources, &metav1.APIResourceList{
+		GroupVersion: gv,
+		APIResources: []metav1.APIResource{apiResource},
+	})
+
+	return rc
+}
+
+func (c *fakeCluster) serverVersion() utils.ServerVersion {
+	v, err := utils.FetchVersion(c.disco)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (c *fakeCluster) ClientForGroupVersionResource(resource schema.GroupVersionResource) (dynamic.Interface, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *fakeCluster) ClientForGroupVersionKind(gvk schema.GroupVersionKind) (dynamic.Interface, error) {
+	rc, ok := c.resources[gvk]
+	if !ok {
+		return nil, fmt.Errorf("no client for %s", gvk)
+	}
+
+	return &fakeClient{rc: rc}, nil
+}
+
+// fakeClient returns the same resource client for every namespace.
+type fakeClient struct {
+	rc *fakeResource
+}
+
+func (c *fakeClient) GetRateLimiter() flowcontrol.RateLimiter {
+	return nil
+}
+
+func (c *fakeClient) Resource(resource *metav1.APIResource, namespace string) dynamic.ResourceInterface {
+	return c.rc
+}
+
+func (c *fakeClient) ParameterCodec(parameterCodec runtime.ParameterCodec) dynamic.Interface {
+	return c
+}
@@ -0,0 +1,240 @@
+package k8sutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/utils"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// LabelGcTag is a label containing the garbage collection tag. It allows
+	// garbage collection candidates to be listed with a label selector.
+	LabelGcTag = "kubecfg.ksonnet.io/garbage-collect-tag"
+
+	// LabelInventory identifies inventory ConfigMaps.
+	LabelInventory = "kubecfg.ksonnet.io/inventory"
+
+	// inventoryPrefix is the name prefix for inventory ConfigMaps.
+	inventoryPrefix = "kscomp-inventory-"
+	// inventoryKey is the ConfigMap data key containing the object references.
+	inventoryKey = "objects"
+)
+
+var (
+	configMapGVK      = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	configMapResource = metav1.APIResource{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"}
+)
+
+// gcLabelSelector returns a label selector for objects with a garbage collection
+// tag. It returns false if the tag can't be used as a label value.
+func gcLabelSelector(gcTag string) (string, bool) {
+	if len(validation.IsValidLabelValue(gcTag)) > 0 {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s=%s", LabelGcTag, gcTag), true
+}
+
+// setGcTag adds the gc tag annotation to an object. The tag is also added as a
+// label if it is a valid label value. Nothing is set if the tag is empty.
+func setGcTag(obj *unstructured.Unstructured, gcTag string) {
+	if gcTag == "" {
+		return
+	}
+
+	utils.SetMetaDataAnnotation(obj, AnnotationGcTag, gcTag)
+
+	if _, ok := gcLabelSelector(gcTag); ok {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[LabelGcTag] = gcTag
+		obj.SetLabels(labels)
+	}
+}
+
+// ObjectRef is a reference to an applied object.
+type ObjectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (r ObjectRef) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Name)
+	}
+
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// inventory records the objects applied to an environment in a ConfigMap. The
+// previous inventory is used to find objects which should be pruned.
+type inventory struct {
+	name string
+	rc   dynamic.ResourceInterface
+}
+
+// inventoryName creates the inventory ConfigMap name for an environment.
+func inventoryName(env string) string {
+	name := strings.ToLower(strings.Replace(env, "/", "-", -1))
+	return inventoryPrefix + name
+}
+
+func newInventory(pool dynamic.ClientPool, namespace, env string) (*inventory, error) {
+	client, err := pool.ClientForGroupVersionKind(configMapGVK)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := configMapResource
+	return &inventory{
+		name: inventoryName(env),
+		rc:   client.Resource(&resource, namespace),
+	}, nil
+}
+
+// read reads the object references in the inventory. It returns false if the
+// inventory does not exist.
+func (i *inventory) read() ([]ObjectRef, bool, error) {
+	cm, err := i.rc.Get(i.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	data, _ := cm.Object["data"].(map[string]interface{})
+	s, _ := data[inventoryKey].(string)
+	if s == "" {
+		return nil, true, nil
+	}
+
+	var refs []ObjectRef
+	if err := json.Unmarshal([]byte(s), &refs); err != nil {
+		return nil, false, fmt.Errorf("invalid inventory %s: %s", i.name, err)
+	}
+
+	return refs, true, nil
+}
+
+// write replaces the object references in the inventory.
+func (i *inventory) write(refs []ObjectRef) error {
+	sort.Slice(refs, func(a, b int) bool {
+		return refs[a].String() < refs[b].String()
+	})
+
+	data, err := json.Marshal(refs)
+	if err != nil {
+		return err
+	}
+
+	cm := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": i.name,
+				"labels": map[string]interface{}{
+					LabelInventory: "true",
+				},
+			},
+			"data": map[string]interface{}{
+				inventoryKey: string(data),
+			},
+		},
+	}
+
+	existing, err := i.rc.Get(i.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = i.rc.Create(cm)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	cm.SetResourceVersion(existing.GetResourceVersion())
+	_, err = i.rc.Update(cm)
+	return err
+}
+
+// mergeRefs returns the union of two sets of object references.
+func mergeRefs(a, b []ObjectRef) []ObjectRef {
+	seen := make(map[ObjectRef]bool)
+
+	var out []ObjectRef
+	for _, ref := range append(append([]ObjectRef{}, a...), b...) {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		out = append(out, ref)
+	}
+
+	return out
+}
+
+// pruneInventory deletes objects which were in the previous inventory but were not
+// applied in this run. Objects are only deleted if they are still eligible for
+// garbage collection. collect is called for each object which is deleted.
+func pruneInventory(pool dynamic.ClientPool, disco discovery.DiscoveryInterface, version *utils.ServerVersion, namespace string, previous, current []ObjectRef, seenUids sets.String, gcTag, dryRunText string, dryRun bool, collect func(ObjectRef)) error {
+	applied := make(map[ObjectRef]bool)
+	for _, ref := range current {
+		applied[ref] = true
+	}
+
+	for _, ref := range previous {
+		if applied[ref] {
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		u.SetNamespace(ref.Namespace)
+		u.SetName(ref.Name)
+
+		rc, err := utils.ClientForResource(pool, disco, u, namespace)
+		if err != nil {
+			log.Debugf("Unable to find client for %s: %s", ref, err)
+			continue
+		}
+
+		live, err := rc.Get(ref.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if !eligibleForGc(live, gcTag) || seenUids.Has(string(live.GetUID())) {
+			log.Debugf("%s is not eligible for gc", ref)
+			continue
+		}
+
+		desc := fmt.Sprintf("%s %s", utils.ResourceNameFor(disco, live), utils.FqName(live))
+		log.Info("Garbage collecting ", desc, dryRunText)
+		collect(ref)
+		if !dryRun {
+			if err := gcDelete(pool, disco, version, live); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var deploymentGVK = schema.GroupVersionKind{Group: "apps", Version: "v1beta2", Kind: "Deployment"}
+
+// gcObject creates a deployment with a garbage collection tag. If labeled is
+// set, the tag label is set as well as the annotation.
+func gcObject(name, tag string, labeled bool) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1beta2")
+	obj.SetKind("Deployment")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+
+	if tag != "" {
+		obj.SetAnnotations(map[string]string{AnnotationGcTag: tag})
+		if labeled {
+			obj.SetLabels(map[string]string{LabelGcTag: tag})
+		}
+	}
+
+	return obj
+}
+
+func ref(name string) ObjectRef {
+	return ObjectRef{APIVersion: "apps/v1beta2", Kind: "Deployment", Namespace: "default", Name: name}
+}
+
+func Test_gcLabelSelector(t *testing.T) {
+	selector, ok := gcLabelSelector("prod")
+	require.True(t, ok)
+	require.Equal(t, LabelGcTag+"=prod", selector)
+
+	_, ok = gcLabelSelector("not a label value")
+	require.False(t, ok)
+}
+
+func Test_inventoryName(t *testing.T) {
+	require.Equal(t, "kscomp-inventory-us-west-prod", inventoryName("us-west/Prod"))
+}
+
+func Test_inventory(t *testing.T) {
+	rc := newFakeResource("configmaps")
+	inv := &inventory{name: inventoryName("prod"), rc: rc}
+
+	refs, ok, err := inv.read()
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Nil(t, refs)
+
+	require.NoError(t, inv.write([]ObjectRef{ref("b"), ref("a")}))
+
+	refs, ok, err = inv.read()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []ObjectRef{ref("a"), ref("b")}, refs)
+
+	cm := rc.object(inv.name)
+	require.Equal(t, map[string]string{LabelInventory: "true"}, cm.GetLabels())
+
+	require.NoError(t, inv.write([]ObjectRef{ref("c")}))
+
+	refs, _, err = inv.read()
+	require.NoError(t, err)
+	require.Equal(t, []ObjectRef{ref("c")}, refs)
+	require.Equal(t, []string{"get kscomp-inventory-prod", "get kscomp-inventory-prod", "create kscomp-inventory-prod",
+		"get kscomp-inventory-prod", "get kscomp-inventory-prod", "update kscomp-inventory-prod", "get kscomp-inventory-prod"}, rc.calls)
+}
+
+func Test_inventory_invalid(t *testing.T) {
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "kscomp-inventory-prod"},
+		"data":       map[string]interface{}{inventoryKey: "{"},
+	}}
+
+	inv := &inventory{name: "kscomp-inventory-prod", rc: newFakeResource("configmaps", cm)}
+
+	_, _, err := inv.read()
+	require.Error(t, err)
+}
+
+func Test_mergeRefs(t *testing.T) {
+	a := []ObjectRef{ref("a"), ref("b")}
+	b := []ObjectRef{ref("b"), ref("c"), ref("a")}
+
+	require.Equal(t, []ObjectRef{ref("a"), ref("b"), ref("c")}, mergeRefs(a, b))
+	require.Equal(t, []ObjectRef{ref("a"), ref("b")}, a)
+	require.Nil(t, mergeRefs(nil, nil))
+}
+
+func Test_pruneInventory(t *testing.T) {
+	cluster := newFakeCluster()
+
+	ignored := gcObject("ignored", "prod", true)
+	ignored.SetAnnotations(map[string]string{AnnotationGcTag: "prod", AnnotationGcStrategy: GcStrategyIgnore})
+
+	rc := cluster.add(deploymentGVK, "deployments", true,
+		gcObject("applied", "prod", true),
+		gcObject("removed", "prod", true),
+		gcObject("legacy", "prod", false),
+		gcObject("other", "staging", true),
+		ignored,
+	)
+
+	previous := []ObjectRef{ref("applied"), ref("removed"), ref("legacy"), ref("other"), ref("ignored"), ref("missing")}
+	current := []ObjectRef{ref("applied")}
+
+	version := cluster.serverVersion()
+
+	t.Run("dry run", func(t *testing.T) {
+		var collected []ObjectRef
+		err := pruneInventory(cluster, cluster.disco, &version, "default", previous, current, sets.NewString(), "prod", " (dry-run)", true, func(r ObjectRef) {
+			collected = append(collected, r)
+		})
+		require.NoError(t, err)
+		require.Equal(t, []ObjectRef{ref("removed"), ref("legacy")}, collected)
+		require.NotNil(t, rc.object("removed"))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		var collected []ObjectRef
+		err := pruneInventory(cluster, cluster.disco, &version, "default", previous, current, sets.NewString(), "prod", "", false, func(r ObjectRef) {
+			collected = append(collected, r)
+		})
+		require.NoError(t, err)
+		require.Equal(t, []ObjectRef{ref("removed"), ref("legacy")}, collected)
+
+		for _, name := range []string{"removed", "legacy"} {
+			require.Nil(t, rc.object(name), name)
+		}
+		for _, name := range []string{"applied", "other", "ignored"} {
+			require.NotNil(t, rc.object(name), name)
+		}
+	})
+}
+
+func Test_pruneInventory_seen(t *testing.T) {
+	cluster := newFakeCluster()
+	rc := cluster.add(deploymentGVK, "deployments", true, gcObject("renamed", "prod", true))
+
+	// The object was applied under another kind, so it was seen by UID.
+	seen := sets.NewString(string(rc.object("renamed").GetUID()))
+	version := cluster.serverVersion()
+
+	err := pruneInventory(cluster, cluster.disco, &version, "default", []ObjectRef{ref("renamed")}, nil, seen, "prod", "", false, func(ObjectRef) {
+		t.Fatal("unexpected garbage collection")
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rc.object("renamed"))
+}
+
+func Test_eligibleForGc(t *testing.T) {
+	controller := true
+	owned := gcObject("owned", "prod", true)
+	owned.SetOwnerReferences([]metav1.OwnerReference{{Name: "parent", Controller: &controller}})
+
+	cases := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		expected bool
+	}{
+		{name: "tagged", obj: gcObject("a", "prod", true), expected: true},
+		{name: "annotation only", obj: gcObject("a", "prod", false), expected: true},
+		{name: "other tag", obj: gcObject("a", "staging", true)},
+		{name: "untagged", obj: gcObject("a", "", false)},
+		{name: "controlled", obj: owned},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, eligibleForGc(tc.obj, "prod"))
+		})
+	}
+}
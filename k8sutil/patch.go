@@ -0,0 +1,241 @@
+package k8sutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/bryanl/woowoo/pkg/client"
+	"github.com/bryanl/woowoo/pkg/util/merge"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// applyPatchType is the patch type for server-side apply.
+	applyPatchType types.PatchType = "application/apply-patch+yaml"
+)
+
+// patchObject updates a live object using a strategy.
+func patchObject(rc dynamic.ResourceInterface, ssa *serverSideApplier, strategy client.ApplyStrategy, obj *unstructured.Unstructured, logger log.FieldLogger) (*unstructured.Unstructured, error) {
+	switch strategy {
+	case "", client.ApplyStrategyMerge:
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		return rc.Patch(obj.GetName(), types.MergePatchType, data)
+	case client.ApplyStrategyStrategic:
+		return strategicApply(rc, obj, logger)
+	case client.ApplyStrategyServerSide:
+		return ssa.apply(obj)
+	default:
+		return nil, fmt.Errorf("unknown apply strategy %q", strategy)
+	}
+}
+
+// strategicApply updates an object with a three-way patch computed from the last applied
+// configuration, the live object, and the desired object. Kinds without a registered
+// type (e.g. custom resources) are updated with a JSON merge patch which removes fields
+// that are no longer desired.
+func strategicApply(rc dynamic.ResourceInterface, obj *unstructured.Unstructured, logger log.FieldLogger) (*unstructured.Unstructured, error) {
+	live, err := rc.Get(obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	lastApplied, err := lastAppliedConfiguration(live)
+	if err != nil {
+		return nil, err
+	}
+
+	dataStruct, err := scheme.Scheme.New(obj.GroupVersionKind())
+	if err != nil {
+		logger.Debugf("%s is not a registered type, using a merge patch", obj.GroupVersionKind())
+
+		patch := merge.DeepCopy(obj.Object)
+		if lastApplied != nil {
+			addRemovedFields(patch, lastApplied)
+		}
+
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return nil, err
+		}
+
+		return rc.Patch(obj.GetName(), types.MergePatchType, data)
+	}
+
+	original := []byte("{}")
+	if lastApplied != nil {
+		if original, err = json.Marshal(lastApplied); err != nil {
+			return nil, err
+		}
+	}
+
+	modified, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := json.Marshal(live)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current, dataStruct, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.Patch(obj.GetName(), types.StrategicMergePatchType, patch)
+}
+
+// addRemovedFields sets fields which were in the last applied configuration but
+// are not in a merge patch to null, so they are removed.
+func addRemovedFields(patch, lastApplied map[string]interface{}) {
+	for k, v := range lastApplied {
+		patchValue, ok := patch[k]
+		if !ok {
+			patch[k] = nil
+			continue
+		}
+
+		lastMap, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if patchMap, ok := patchValue.(map[string]interface{}); ok {
+			addRemovedFields(patchMap, lastMap)
+		}
+	}
+}
+
+// serverSideApplier applies objects using server-side apply. The dynamic client
+// does not support query parameters, so requests are made with a REST client.
+type serverSideApplier struct {
+	config         *rest.Config
+	disco          discovery.DiscoveryInterface
+	namespace      string
+	fieldManager   string
+	forceConflicts bool
+
+	mu      sync.Mutex
+	clients map[schema.GroupVersion]*rest.RESTClient
+}
+
+func newServerSideApplier(config *client.Config, disco discovery.DiscoveryInterface, namespace, fieldManager string, forceConflicts bool) (*serverSideApplier, error) {
+	restConfig, err := config.Config.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if fieldManager == "" {
+		fieldManager = client.DefaultFieldManager
+	}
+
+	return &serverSideApplier{
+		config:         restConfig,
+		disco:          disco,
+		namespace:      namespace,
+		fieldManager:   fieldManager,
+		forceConflicts: forceConflicts,
+		clients:        make(map[schema.GroupVersion]*rest.RESTClient),
+	}, nil
+}
+
+func (s *serverSideApplier) apply(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	gvk := obj.GroupVersionKind()
+
+	resource, err := s.resource(gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := s.client(gvk.GroupVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = s.namespace
+	}
+
+	req := rc.Patch(applyPatchType).
+		NamespaceIfScoped(namespace, resource.Namespaced).
+		Resource(resource.Name).
+		Name(obj.GetName()).
+		Param("fieldManager", s.fieldManager).
+		Body(data)
+
+	if s.forceConflicts {
+		req = req.Param("force", "true")
+	}
+
+	raw, err := req.Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := &unstructured.Unstructured{}
+	if err = applied.UnmarshalJSON(raw); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func (s *serverSideApplier) resource(gvk schema.GroupVersionKind) (*metav1.APIResource, error) {
+	resources, err := s.disco.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range resources.APIResources {
+		if r.Kind == gvk.Kind {
+			return &r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Server is unable to handle %s", gvk)
+}
+
+func (s *serverSideApplier) client(gv schema.GroupVersion) (*rest.RESTClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rc, ok := s.clients[gv]; ok {
+		return rc, nil
+	}
+
+	conf := *s.config
+	conf.ContentConfig = dynamic.ContentConfig()
+	conf.GroupVersion = &gv
+	conf.APIPath = "/apis"
+	if gv.Group == "" {
+		conf.APIPath = "/api"
+	}
+
+	rc, err := rest.RESTClientFor(&conf)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clients[gv] = rc
+	return rc, nil
+}
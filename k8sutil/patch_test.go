@@ -0,0 +1,222 @@
+package k8sutil
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bryanl/woowoo/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/rest"
+)
+
+func Test_addRemovedFields(t *testing.T) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "w"},
+		"spec":     map[string]interface{}{"a": int64(2), "list": []interface{}{"x"}},
+	}
+	lastApplied := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "w", "labels": map[string]interface{}{"app": "w"}},
+		"spec":     map[string]interface{}{"a": int64(1), "b": int64(2), "list": []interface{}{"x", "y"}},
+		"extra":    "value",
+	}
+
+	addRemovedFields(patch, lastApplied)
+
+	require.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "w", "labels": nil},
+		"spec":     map[string]interface{}{"a": int64(2), "b": nil, "list": []interface{}{"x"}},
+		"extra":    nil,
+	}, patch)
+}
+
+// appliedDeployment returns a live deployment which was applied with a label
+// which is no longer desired.
+func appliedDeployment(t *testing.T) *unstructured.Unstructured {
+	live := liveDeployment(t)
+
+	lastApplied := decodeObject(t, desiredDeployment)
+	lastApplied.SetLabels(map[string]string{"team": "web"})
+	require.NoError(t, SetLastApplied(lastApplied))
+
+	live.SetLabels(map[string]string{"team": "web"})
+	live.SetAnnotations(lastApplied.GetAnnotations())
+	return live
+}
+
+func Test_strategicApply(t *testing.T) {
+	t.Run("registered kind", func(t *testing.T) {
+		rc := newFakeResource("deployments", appliedDeployment(t))
+
+		desired := decodeObject(t, desiredDeployment)
+		container(desired)["image"] = "nginx:1.14"
+		require.NoError(t, SetLastApplied(desired))
+
+		applied, err := strategicApply(rc, desired, log.StandardLogger())
+		require.NoError(t, err)
+		require.Equal(t, []string{"get web", "patch web"}, rc.calls)
+
+		c := container(applied)
+		require.Equal(t, "nginx:1.14", c["image"])
+		require.Equal(t, "IfNotPresent", c["imagePullPolicy"], "server defaults in list items are kept")
+		require.Empty(t, applied.GetLabels(), "removed labels are deleted")
+		require.Equal(t, desired.GetAnnotations(), applied.GetAnnotations())
+	})
+
+	t.Run("unregistered kind", func(t *testing.T) {
+		live := widget(map[string]interface{}{"size": int64(1), "color": "red"})
+		lastApplied := widget(map[string]interface{}{"size": int64(1), "color": "red"})
+		require.NoError(t, SetLastApplied(lastApplied))
+		live.SetAnnotations(lastApplied.GetAnnotations())
+		live.Object["status"] = map[string]interface{}{"phase": "Ready"}
+
+		rc := newFakeResource("widgets", live)
+
+		desired := widget(map[string]interface{}{"size": int64(2)})
+		applied, err := strategicApply(rc, desired, log.StandardLogger())
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]interface{}{"size": int64(2)}, applied.Object["spec"])
+		require.Equal(t, map[string]interface{}{"phase": "Ready"}, applied.Object["status"])
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		rc := newFakeResource("deployments")
+
+		_, err := strategicApply(rc, decodeObject(t, desiredDeployment), log.StandardLogger())
+		require.True(t, errors.IsNotFound(err))
+	})
+}
+
+func widget(spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "w", "namespace": "default"},
+		"spec":       spec,
+	}}
+}
+
+func Test_patchObject(t *testing.T) {
+	t.Run("merge", func(t *testing.T) {
+		rc := newFakeResource("deployments", appliedDeployment(t))
+
+		desired := decodeObject(t, desiredDeployment)
+		container(desired)["image"] = "nginx:1.14"
+
+		applied, err := patchObject(rc, nil, client.ApplyStrategyMerge, desired, log.StandardLogger())
+		require.NoError(t, err)
+
+		// A merge patch replaces lists and doesn't remove fields.
+		require.Equal(t, map[string]interface{}{
+			"name":  "web",
+			"image": "nginx:1.14",
+			"ports": []interface{}{map[string]interface{}{"containerPort": int64(80)}},
+		}, container(applied))
+		require.Equal(t, map[string]string{"team": "web"}, applied.GetLabels())
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		_, err := patchObject(newFakeResource("deployments"), nil, "replace", decodeObject(t, desiredDeployment), log.StandardLogger())
+		require.EqualError(t, err, `unknown apply strategy "replace"`)
+	})
+}
+
+func Test_serverSideApplier(t *testing.T) {
+	type request struct {
+		method      string
+		path        string
+		query       map[string][]string
+		contentType string
+		body        map[string]interface{}
+	}
+
+	var requests []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &m))
+
+		requests = append(requests, request{
+			method:      r.Method,
+			path:        r.URL.Path,
+			query:       r.URL.Query(),
+			contentType: r.Header.Get("Content-Type"),
+			body:        m,
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/namespaces/default/configmaps/conflicted" {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "Conflict", "code": 409, "message": "conflict with kubectl"}`))
+			return
+		}
+
+		var applied map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &applied))
+		applied["metadata"].(map[string]interface{})["uid"] = "1234"
+
+		data, err := json.Marshal(applied)
+		require.NoError(t, err)
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	cluster := newFakeCluster()
+	cluster.add(deploymentGVK, "deployments", true)
+	cluster.add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, "configmaps", true)
+	cluster.add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, "namespaces", false)
+
+	s := &serverSideApplier{
+		config:         &rest.Config{Host: srv.URL},
+		disco:          cluster.disco,
+		namespace:      "default",
+		fieldManager:   "kscomp",
+		forceConflicts: true,
+		clients:        make(map[schema.GroupVersion]*rest.RESTClient),
+	}
+
+	desired := decodeObject(t, desiredDeployment)
+	applied, err := s.apply(desired)
+	require.NoError(t, err)
+	require.Equal(t, "1234", string(applied.GetUID()))
+
+	cm := configMap("settings", "a")
+	cm.SetNamespace("")
+	_, err = s.apply(cm)
+	require.NoError(t, err)
+
+	ns := kindObject("v1", "Namespace", "team")
+	_, err = s.apply(ns)
+	require.NoError(t, err)
+
+	_, err = s.apply(configMap("conflicted", "a"))
+	require.True(t, errors.IsConflict(err), "unexpected error %v", err)
+
+	_, err = s.apply(kindObject("example.com/v1", "Widget", "w"))
+	require.Error(t, err)
+
+	require.Len(t, requests, 4)
+
+	query := map[string][]string{"fieldManager": {"kscomp"}, "force": {"true"}}
+	require.Equal(t, request{
+		method:      "PATCH",
+		path:        "/apis/apps/v1beta2/namespaces/default/deployments/web",
+		query:       query,
+		contentType: string(applyPatchType),
+		body:        desired.Object,
+	}, requests[0])
+
+	require.Equal(t, "/api/v1/namespaces/default/configmaps/settings", requests[1].path)
+	require.Equal(t, "/api/v1/namespaces/team", requests[2].path)
+
+	require.Len(t, s.clients, 2, "clients are cached by group version")
+}
@@ -0,0 +1,214 @@
+package k8sutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// PlanCreate is the plan action for an object which will be created.
+	PlanCreate = "create"
+	// PlanUpdate is the plan action for an object which will be updated.
+	PlanUpdate = "update"
+	// PlanUnchanged is the plan action for an object which will not change.
+	PlanUnchanged = "unchanged"
+	// PlanGarbageCollect is the plan action for an object which will be garbage collected.
+	PlanGarbageCollect = "garbage-collect"
+
+	// sensitiveValue replaces values which should not be displayed in a plan.
+	sensitiveValue = "(sensitive)"
+)
+
+// Plan describes the changes applying an environment will make.
+type Plan struct {
+	Environment string       `json:"environment"`
+	Objects     []PlanObject `json:"objects"`
+}
+
+// PlanObject is an object and the action which will be taken on it.
+type PlanObject struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Namespace  string        `json:"namespace,omitempty"`
+	Name       string        `json:"name"`
+	Action     string        `json:"action"`
+	Changes    []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is a change to a field. Old is not set for added fields, and New is
+// not set for removed fields.
+type FieldChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Write writes the plan to a writer in a format (json or yaml).
+func (p *Plan) Write(out io.Writer, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	case "yaml":
+		data, err := yaml.Marshal(p)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unknown plan format: %s", format)
+	}
+}
+
+// planObject creates a plan entry for an object by comparing it with the live object.
+func planObject(rc dynamic.ResourceInterface, obj *unstructured.Unstructured) (PlanObject, error) {
+	entry := PlanObject{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+
+	live, err := rc.Get(obj.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		entry.Action = PlanCreate
+		return entry, nil
+	} else if err != nil {
+		return entry, err
+	}
+
+	entry.Namespace = live.GetNamespace()
+
+	from, to, err := expectedObject(obj, live)
+	if err != nil {
+		return entry, err
+	}
+
+	entry.Changes = fieldChanges("", from, to, nil)
+	if obj.GetKind() == "Secret" {
+		maskSecretChanges(entry.Changes)
+	}
+
+	entry.Action = PlanUnchanged
+	if len(entry.Changes) > 0 {
+		entry.Action = PlanUpdate
+	}
+
+	return entry, nil
+}
+
+// fieldChanges returns the changes between two values. Paths use dots for object
+// fields and brackets for array indexes, e.g. `spec.containers[0].image`.
+func fieldChanges(path string, from, to interface{}, changes []FieldChange) []FieldChange {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := make(map[string]bool)
+		for k := range fromMap {
+			keys[k] = true
+		}
+		for k := range toMap {
+			keys[k] = true
+		}
+
+		var sorted []string
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			childPath := fieldPath(path, k)
+			fromValue, inFrom := fromMap[k]
+			toValue, inTo := toMap[k]
+
+			switch {
+			case !inFrom:
+				changes = append(changes, FieldChange{Path: childPath, New: toValue})
+			case !inTo:
+				changes = append(changes, FieldChange{Path: childPath, Old: fromValue})
+			default:
+				changes = fieldChanges(childPath, fromValue, toValue, changes)
+			}
+		}
+
+		return changes
+	}
+
+	fromSlice, fromIsSlice := from.([]interface{})
+	toSlice, toIsSlice := to.([]interface{})
+	if fromIsSlice && toIsSlice {
+		for i := 0; i < len(fromSlice) || i < len(toSlice); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+
+			switch {
+			case i >= len(fromSlice):
+				changes = append(changes, FieldChange{Path: childPath, New: toSlice[i]})
+			case i >= len(toSlice):
+				changes = append(changes, FieldChange{Path: childPath, Old: fromSlice[i]})
+			default:
+				changes = fieldChanges(childPath, fromSlice[i], toSlice[i], changes)
+			}
+		}
+
+		return changes
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		changes = append(changes, FieldChange{Path: path, Old: from, New: to})
+	}
+
+	return changes
+}
+
+// fieldPath appends a field to a path. Fields containing dots are quoted.
+func fieldPath(path, field string) string {
+	if strings.Contains(field, ".") {
+		return fmt.Sprintf("%s[%q]", path, field)
+	}
+
+	if path == "" {
+		return field
+	}
+
+	return path + "." + field
+}
+
+// maskSecretChanges hides secret values in changes.
+func maskSecretChanges(changes []FieldChange) {
+	for i := range changes {
+		c := &changes[i]
+		if !inField(c.Path, "data") && !inField(c.Path, "stringData") {
+			continue
+		}
+
+		if c.Old != nil {
+			c.Old = sensitiveValue
+		}
+		if c.New != nil {
+			c.New = sensitiveValue
+		}
+	}
+}
+
+// inField reports whether a path is a top level field or is nested in it.
+func inField(path, field string) bool {
+	if !strings.HasPrefix(path, field) {
+		return false
+	}
+
+	rest := path[len(field):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
@@ -0,0 +1,170 @@
+package k8sutil
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func secret(data map[string]interface{}, stringData map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "creds", "namespace": "default"},
+		"type":       "Opaque",
+	}}
+
+	if data != nil {
+		obj.Object["data"] = data
+	}
+	if stringData != nil {
+		obj.Object["stringData"] = stringData
+	}
+
+	return obj
+}
+
+func encoded(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func Test_planObject(t *testing.T) {
+	t.Run("missing object is created", func(t *testing.T) {
+		rc := newFakeResource("deployments")
+
+		entry, err := planObject(rc, decodeObject(t, desiredDeployment))
+		require.NoError(t, err)
+		require.Equal(t, PlanObject{
+			APIVersion: "apps/v1beta2",
+			Kind:       "Deployment",
+			Namespace:  "default",
+			Name:       "web",
+			Action:     PlanCreate,
+		}, entry)
+	})
+
+	t.Run("unchanged deployment", func(t *testing.T) {
+		rc := newFakeResource("deployments", liveDeployment(t))
+
+		entry, err := planObject(rc, decodeObject(t, desiredDeployment))
+		require.NoError(t, err)
+		require.Equal(t, PlanUnchanged, entry.Action)
+		require.Empty(t, entry.Changes)
+	})
+
+	t.Run("changed deployment", func(t *testing.T) {
+		rc := newFakeResource("deployments", liveDeployment(t))
+
+		desired := decodeObject(t, desiredDeployment)
+		container(desired)["image"] = "nginx:1.14"
+
+		entry, err := planObject(rc, desired)
+		require.NoError(t, err)
+		require.Equal(t, PlanUpdate, entry.Action)
+		require.Equal(t, []FieldChange{
+			{Path: "spec.template.spec.containers[0].image", Old: "nginx:1.13", New: "nginx:1.14"},
+		}, entry.Changes)
+	})
+
+	t.Run("secret changes are masked", func(t *testing.T) {
+		// Without a last applied configuration, user isn't known to be
+		// managed, so it is kept.
+		live := secret(map[string]interface{}{"password": encoded("old"), "user": encoded("admin")}, nil)
+		rc := newFakeResource("secrets", live)
+
+		desired := secret(map[string]interface{}{"password": encoded("new"), "token": encoded("t")}, nil)
+
+		entry, err := planObject(rc, desired)
+		require.NoError(t, err)
+		require.Equal(t, PlanUpdate, entry.Action)
+		require.Equal(t, []FieldChange{
+			{Path: "data.password", Old: sensitiveValue, New: sensitiveValue},
+			{Path: "data.token", New: sensitiveValue},
+		}, entry.Changes)
+	})
+
+	t.Run("secret string data matches encoded data", func(t *testing.T) {
+		live := secret(map[string]interface{}{"password": encoded("s3cret")}, nil)
+		rc := newFakeResource("secrets", live)
+
+		entry, err := planObject(rc, secret(nil, map[string]interface{}{"password": "s3cret"}))
+		require.NoError(t, err)
+		require.Equal(t, PlanUnchanged, entry.Action)
+	})
+}
+
+func Test_fieldChanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		from     interface{}
+		to       interface{}
+		expected []FieldChange
+	}{
+		{
+			name: "equal",
+			from: map[string]interface{}{"a": []interface{}{"x"}},
+			to:   map[string]interface{}{"a": []interface{}{"x"}},
+		},
+		{
+			name: "added, removed and changed fields",
+			from: map[string]interface{}{"a": "1", "b": map[string]interface{}{"c": int64(2)}},
+			to:   map[string]interface{}{"b": map[string]interface{}{"c": int64(3)}, "d": true},
+			expected: []FieldChange{
+				{Path: "a", Old: "1"},
+				{Path: "b.c", Old: int64(2), New: int64(3)},
+				{Path: "d", New: true},
+			},
+		},
+		{
+			name: "array items",
+			from: map[string]interface{}{"l": []interface{}{"a", "b"}},
+			to:   map[string]interface{}{"l": []interface{}{"a", "c", "d"}},
+			expected: []FieldChange{
+				{Path: "l[1]", Old: "b", New: "c"},
+				{Path: "l[2]", New: "d"},
+			},
+		},
+		{
+			name: "fields with dots are quoted",
+			from: map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{"example.com/a": "1"}}},
+			to:   map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{"example.com/a": "2"}}},
+			expected: []FieldChange{
+				{Path: `metadata.annotations["example.com/a"]`, Old: "1", New: "2"},
+			},
+		},
+		{
+			name: "type change",
+			from: map[string]interface{}{"a": map[string]interface{}{"b": "c"}},
+			to:   map[string]interface{}{"a": "b"},
+			expected: []FieldChange{
+				{Path: "a", Old: map[string]interface{}{"b": "c"}, New: "b"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, fieldChanges("", tc.from, tc.to, nil))
+		})
+	}
+}
+
+func Test_maskSecretChanges(t *testing.T) {
+	changes := []FieldChange{
+		{Path: "data", New: map[string]interface{}{"a": "b"}},
+		{Path: "stringData.password", Old: "x"},
+		{Path: "dataVersion", Old: "1", New: "2"},
+		{Path: "metadata.labels.data", Old: "1", New: "2"},
+	}
+
+	maskSecretChanges(changes)
+
+	require.Equal(t, []FieldChange{
+		{Path: "data", New: sensitiveValue},
+		{Path: "stringData.password", Old: sensitiveValue},
+		{Path: "dataVersion", Old: "1", New: "2"},
+		{Path: "metadata.labels.data", Old: "1", New: "2"},
+	}, changes)
+}
@@ -0,0 +1,63 @@
+package k8sutil
+
+import (
+	"time"
+
+	"github.com/bryanl/woowoo/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// retryable reports if an API error is a conflict or is transient, and the
+// request can be retried.
+func retryable(err error) bool {
+	return errors.IsConflict(err) ||
+		errors.IsTooManyRequests(err) ||
+		errors.IsServerTimeout(err) ||
+		errors.IsTimeout(err)
+}
+
+// backoffFor creates a backoff from retry options. Negative retries and an unset
+// backoff use the defaults.
+func backoffFor(opts client.RetryOptions) wait.Backoff {
+	backoff := wait.Backoff{
+		Steps:    opts.Retries + 1,
+		Duration: opts.Backoff,
+		Factor:   2,
+		Jitter:   0.1,
+	}
+
+	if opts.Retries < 0 {
+		backoff.Steps = client.DefaultRetries + 1
+	}
+
+	if opts.Backoff <= 0 {
+		backoff.Duration = client.DefaultRetryBackoff
+	}
+
+	return backoff
+}
+
+// withRetry calls fn until it succeeds, it returns an error which can't be retried,
+// or the retries are exhausted. fn is expected to re-fetch any state it depends on,
+// so conflicts are resolved by repeating the request against the latest object.
+func withRetry(opts client.RetryOptions, logger log.FieldLogger, desc string, fn func() error) error {
+	backoff := backoffFor(opts)
+
+	var err error
+	for i := 0; i < backoff.Steps; i++ {
+		if i > 0 {
+			delay := wait.Jitter(backoff.Duration, backoff.Jitter)
+			logger.Infof("Retrying %s in %s: %s", desc, delay.Round(time.Millisecond), err)
+			time.Sleep(delay)
+			backoff.Duration = time.Duration(float64(backoff.Duration) * backoff.Factor)
+		}
+
+		if err = fn(); err == nil || !retryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,131 @@
+package k8sutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bryanl/woowoo/pkg/client"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var configMapsResource = schema.GroupResource{Resource: "configmaps"}
+
+func Test_retryable(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "conflict", err: errors.NewConflict(configMapsResource, "a", fmt.Errorf("changed")), expected: true},
+		{name: "too many requests", err: errors.NewTooManyRequests("slow down", 1), expected: true},
+		{name: "server timeout", err: errors.NewServerTimeout(configMapsResource, "get", 1), expected: true},
+		{name: "timeout", err: errors.NewTimeoutError("timed out", 1), expected: true},
+		{name: "already exists", err: errors.NewAlreadyExists(configMapsResource, "a")},
+		{name: "internal error", err: errors.NewInternalError(fmt.Errorf("boom"))},
+		{name: "service unavailable", err: errors.NewServiceUnavailable("down")},
+		{name: "not found", err: errors.NewNotFound(configMapsResource, "a")},
+		{name: "invalid", err: errors.NewBadRequest("invalid")},
+		{name: "other error", err: fmt.Errorf("boom")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, retryable(tc.err))
+		})
+	}
+}
+
+func Test_backoffFor(t *testing.T) {
+	require.Equal(t, wait.Backoff{Steps: 4, Duration: time.Second, Factor: 2, Jitter: 0.1},
+		backoffFor(client.RetryOptions{Retries: 3, Backoff: time.Second}))
+
+	require.Equal(t, wait.Backoff{Steps: 1, Duration: client.DefaultRetryBackoff, Factor: 2, Jitter: 0.1},
+		backoffFor(client.RetryOptions{}), "zero retries make a single attempt")
+
+	require.Equal(t, wait.Backoff{Steps: client.DefaultRetries + 1, Duration: client.DefaultRetryBackoff, Factor: 2, Jitter: 0.1},
+		backoffFor(client.RetryOptions{Retries: -1}))
+}
+
+func Test_withRetry(t *testing.T) {
+	conflict := errors.NewConflict(configMapsResource, "a", fmt.Errorf("changed"))
+
+	cases := []struct {
+		name  string
+		errs  []error
+		calls int
+		err   error
+		// minDelay is the least time the retries take.
+		minDelay time.Duration
+	}{
+		{
+			name:  "success",
+			calls: 1,
+		},
+		{
+			name:     "succeeds after retries",
+			errs:     []error{conflict, conflict},
+			calls:    3,
+			minDelay: 30 * time.Millisecond,
+		},
+		{
+			name:  "error which can't be retried",
+			errs:  []error{errors.NewAlreadyExists(configMapsResource, "a")},
+			calls: 1,
+			err:   errors.NewAlreadyExists(configMapsResource, "a"),
+		},
+		{
+			name:     "retries exhausted",
+			errs:     []error{conflict, conflict, conflict, conflict},
+			calls:    3,
+			err:      conflict,
+			minDelay: 30 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, recorder := newBufferedLogger()
+			opts := client.RetryOptions{Retries: 2, Backoff: 10 * time.Millisecond}
+
+			calls := 0
+			start := time.Now()
+			err := withRetry(opts, logger, "configmap a", func() error {
+				calls++
+				if calls <= len(tc.errs) {
+					return tc.errs[calls-1]
+				}
+				return nil
+			})
+
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.calls, calls)
+			require.Len(t, recorder.entries, tc.calls-1)
+
+			// The delay doubles after each retry: 10ms, then 20ms.
+			require.True(t, time.Since(start) >= tc.minDelay, "retried after %s", time.Since(start))
+			for _, entry := range recorder.entries {
+				require.Contains(t, entry.Message, "Retrying configmap a in")
+			}
+		})
+	}
+}
+
+func Test_withRetry_noRetries(t *testing.T) {
+	conflict := errors.NewConflict(configMapsResource, "a", fmt.Errorf("changed"))
+
+	logger, recorder := newBufferedLogger()
+
+	calls := 0
+	err := withRetry(client.RetryOptions{}, logger, "configmap a", func() error {
+		calls++
+		return conflict
+	})
+
+	require.Equal(t, conflict, err)
+	require.Equal(t, 1, calls)
+	require.Empty(t, recorder.entries)
+}
@@ -0,0 +1,145 @@
+package k8sutil
+
+import (
+	"fmt"
+
+	"github.com/bryanl/woowoo/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+)
+
+// snapshot is the live state of an object before it was applied.
+type snapshot struct {
+	desc string
+	name string
+	rc   dynamic.ResourceInterface
+
+	// live is the object before it was applied. It is nil if the object was created.
+	live *unstructured.Unstructured
+}
+
+// rollback restores objects to their state before they were applied.
+type rollback struct {
+	snapshots []*snapshot
+	retry     client.RetryOptions
+}
+
+// take captures the live state of an object. It returns nil if the object
+// does not exist.
+func (r *rollback) take(rc dynamic.ResourceInterface, name string, logger log.FieldLogger) (*unstructured.Unstructured, error) {
+	var live *unstructured.Unstructured
+	err := withRetry(r.retry, logger, name, func() error {
+		var err error
+		live, err = rc.Get(name, metav1.GetOptions{})
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return live, nil
+}
+
+// record records an object which was applied.
+func (r *rollback) record(desc, name string, rc dynamic.ResourceInterface, live *unstructured.Unstructured) {
+	r.snapshots = append(r.snapshots, &snapshot{
+		desc: desc,
+		name: name,
+		rc:   rc,
+		live: live,
+	})
+}
+
+// run reverts applied objects in the reverse order they were applied. Objects
+// which were created are deleted, and objects which were updated are restored.
+func (r *rollback) run() error {
+	var errs []error
+	for i := len(r.snapshots) - 1; i >= 0; i-- {
+		s := r.snapshots[i]
+
+		if s.live == nil {
+			log.Info("Rolling back: deleting created ", s.desc)
+
+			fg := metav1.DeletePropagationForeground
+			err := withRetry(r.retry, log.StandardLogger(), s.desc, func() error {
+				return s.rc.Delete(s.name, &metav1.DeleteOptions{PropagationPolicy: &fg})
+			})
+			if err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("delete %s: %s", s.desc, err))
+			}
+			continue
+		}
+
+		log.Info("Rolling back: restoring ", s.desc)
+		if err := withRetry(r.retry, log.StandardLogger(), s.desc, s.restore); err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %s", s.desc, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (s *snapshot) restore() error {
+	current, err := s.rc.Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.rc.Update(restoredObject(s.live, current))
+	return err
+}
+
+// serverMetadataFields are metadata fields which are set by the server.
+var serverMetadataFields = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"generation",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// restoredObject returns the update which restores an object to its state
+// before it was applied. Fields set by the server are removed, and the
+// resource version of the current object is used so a concurrent change is
+// detected as a conflict.
+func restoredObject(live, current *unstructured.Unstructured) *unstructured.Unstructured {
+	restored := live.DeepCopy()
+	delete(restored.Object, "status")
+
+	if metadata, ok := restored.Object["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+	}
+
+	restored.SetResourceVersion(current.GetResourceVersion())
+	return restored
+}
+
+// rollbackOnFailure reverts applied objects if rollback is enabled. The returned
+// error describes the original failure and the result of the rollback.
+func rollbackOnFailure(r *rollback, cause error) error {
+	if r == nil {
+		return cause
+	}
+
+	if len(r.snapshots) == 0 {
+		log.Info("Rolling back: no objects were changed")
+		return cause
+	}
+
+	if err := r.run(); err != nil {
+		return fmt.Errorf("%s; rollback failed: %s", cause, err)
+	}
+
+	log.Infof("Rolled back %d objects", len(r.snapshots))
+	return fmt.Errorf("%s; rolled back %d objects", cause, len(r.snapshots))
+}
@@ -0,0 +1,140 @@
+package k8sutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bryanl/woowoo/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testRetry = client.RetryOptions{Retries: 2, Backoff: time.Millisecond}
+
+func configMap(name, value string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"data":       map[string]interface{}{"value": value},
+	}}
+}
+
+func Test_restoredObject(t *testing.T) {
+	live := liveDeployment(t)
+	live.Object["metadata"].(map[string]interface{})["creationTimestamp"] = "2018-03-01T00:00:00Z"
+	live.SetSelfLink("/apis/apps/v1beta2/namespaces/default/deployments/web")
+
+	current := live.DeepCopy()
+	current.SetResourceVersion("9")
+
+	restored := restoredObject(live, current)
+
+	require.Equal(t, "9", restored.GetResourceVersion())
+	require.Empty(t, restored.GetUID())
+	require.Empty(t, restored.GetSelfLink())
+	require.NotContains(t, restored.Object["metadata"], "creationTimestamp")
+	require.Zero(t, restored.GetGeneration())
+	require.NotContains(t, restored.Object, "status")
+	require.Equal(t, live.GetAnnotations(), restored.GetAnnotations())
+	require.Equal(t, live.Object["spec"], restored.Object["spec"])
+
+	require.Equal(t, "5", live.GetResourceVersion())
+	require.Contains(t, live.Object, "status")
+}
+
+func Test_rollback_take(t *testing.T) {
+	rc := newFakeResource("configmaps", configMap("existing", "a"))
+	rc.fail("get", errors.NewServerTimeout(schema.GroupResource{Resource: "configmaps"}, "get", 0))
+
+	r := &rollback{retry: testRetry}
+
+	live, err := r.take(rc, "existing", log.StandardLogger())
+	require.NoError(t, err)
+	require.Equal(t, "existing", live.GetName())
+
+	live, err = r.take(rc, "missing", log.StandardLogger())
+	require.NoError(t, err)
+	require.Nil(t, live)
+}
+
+func Test_rollback_run(t *testing.T) {
+	rc := newFakeResource("configmaps", configMap("updated", "old"))
+	r := &rollback{retry: testRetry}
+
+	live, err := r.take(rc, "updated", log.StandardLogger())
+	require.NoError(t, err)
+	created, err := r.take(rc, "created", log.StandardLogger())
+	require.NoError(t, err)
+
+	_, err = rc.Update(configMap("updated", "new"))
+	require.NoError(t, err)
+	_, err = rc.Create(configMap("created", "new"))
+	require.NoError(t, err)
+
+	r.record("configmap updated", "updated", rc, live)
+	r.record("configmap created", "created", rc, created)
+
+	// The first restore conflicts with a concurrent change and is retried.
+	rc.fail("update", errors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "updated", fmt.Errorf("changed")))
+	rc.calls = nil
+
+	require.NoError(t, r.run())
+
+	require.Nil(t, rc.object("created"))
+	restored := rc.object("updated")
+	require.Equal(t, map[string]interface{}{"value": "old"}, restored.Object["data"])
+	require.Equal(t, live.GetUID(), restored.GetUID())
+
+	// Objects are rolled back in reverse order.
+	require.Equal(t, []string{
+		"delete created",
+		"get updated", "update updated",
+		"get updated", "update updated",
+	}, rc.calls)
+}
+
+func Test_rollback_run_errors(t *testing.T) {
+	rc := newFakeResource("configmaps", configMap("updated", "new"))
+	r := &rollback{retry: testRetry}
+
+	r.record("configmap updated", "updated", rc, configMap("updated", "old"))
+	r.record("configmap gone", "gone", rc, nil)
+	r.record("configmap forbidden", "forbidden", rc, nil)
+
+	// Deleting an object which is already gone isn't an error.
+	rc.fail("delete", errors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "forbidden", fmt.Errorf("denied")))
+	rc.fail("get", errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "updated"))
+
+	err := r.run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "delete configmap forbidden")
+	require.Contains(t, err.Error(), "restore configmap updated")
+	require.NotContains(t, err.Error(), "configmap gone")
+}
+
+func Test_rollbackOnFailure(t *testing.T) {
+	cause := fmt.Errorf("timed out")
+
+	require.Equal(t, cause, rollbackOnFailure(nil, cause))
+	require.Equal(t, cause, rollbackOnFailure(&rollback{}, cause))
+
+	rc := newFakeResource("configmaps", configMap("created", "new"))
+	r := &rollback{retry: testRetry}
+	r.record("configmap created", "created", rc, nil)
+
+	err := rollbackOnFailure(r, cause)
+	require.EqualError(t, err, "timed out; rolled back 1 objects")
+	require.Nil(t, rc.object("created"))
+
+	r = &rollback{retry: testRetry}
+	r.record("configmap created", "created", rc, nil)
+	rc.fail("delete", fmt.Errorf("boom"))
+
+	err = rollbackOnFailure(r, cause)
+	require.EqualError(t, err, "timed out; rollback failed: delete configmap created: boom")
+}
@@ -0,0 +1,336 @@
+package k8sutil
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bryanl/woowoo/ksutil"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// DefaultWaitTimeout is the default time to wait for objects to become ready.
+	DefaultWaitTimeout = 5 * time.Minute
+
+	// waitInterval is the time between readiness checks.
+	waitInterval = 2 * time.Second
+)
+
+// readyTarget is an applied object whose readiness is being tracked.
+type readyTarget struct {
+	obj *unstructured.Unstructured
+	rc  dynamic.ResourceInterface
+
+	ready   bool
+	message string
+}
+
+// waitForReady polls objects until they are ready or the timeout expires. A progress
+// table is written to out each time the state of an object changes.
+func waitForReady(out io.Writer, targets []*readyTarget, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	var lastRows [][]string
+	for {
+		pending := 0
+		for _, target := range targets {
+			if target.ready {
+				continue
+			}
+
+			live, err := target.rc.Get(target.obj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				target.message = err.Error()
+				pending++
+				continue
+			}
+
+			ready, message, err := objectReady(live)
+			if err != nil {
+				renderReadiness(out, targets)
+				return fmt.Errorf("%s %s failed: %s", live.GetKind(), live.GetName(), err)
+			}
+
+			target.ready = ready
+			target.message = message
+			if !ready {
+				pending++
+			}
+		}
+
+		rows := readinessRows(targets)
+		if !reflect.DeepEqual(rows, lastRows) {
+			renderReadiness(out, targets)
+			lastRows = rows
+		}
+
+		if pending == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			var names []string
+			for _, target := range targets {
+				if !target.ready {
+					names = append(names, fmt.Sprintf("%s %s", target.obj.GetKind(), target.obj.GetName()))
+				}
+			}
+
+			return fmt.Errorf("timed out after %s waiting for %s", timeout, strings.Join(names, ", "))
+		}
+
+		log.Debugf("Waiting for %d objects to become ready", pending)
+		time.Sleep(waitInterval)
+	}
+}
+
+func readinessRows(targets []*readyTarget) [][]string {
+	var rows [][]string
+	for _, target := range targets {
+		status := "waiting"
+		if target.ready {
+			status = "ready"
+		}
+
+		rows = append(rows, []string{target.obj.GetKind(), namespacedName(target.obj), status, target.message})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i][0] != rows[j][0] {
+			return rows[i][0] < rows[j][0]
+		}
+		return rows[i][1] < rows[j][1]
+	})
+
+	return rows
+}
+
+func renderReadiness(out io.Writer, targets []*readyTarget) {
+	table := ksutil.NewTable(out)
+	table.SetHeader([]string{"kind", "name", "status", "message"})
+	table.AppendBulk(readinessRows(targets))
+	table.Render()
+	fmt.Fprintln(out)
+}
+
+// namespacedName returns the namespaced name of an object.
+func namespacedName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+
+	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+}
+
+// objectReady reports if an object is ready. Kinds without a notion of readiness
+// are ready once they exist. An error is returned if the object can never become
+// ready, e.g. a failed Job.
+func objectReady(obj *unstructured.Unstructured) (bool, string, error) {
+	switch obj.GetKind() {
+	case "Deployment":
+		return deploymentReady(obj)
+	case "StatefulSet":
+		return statefulSetReady(obj)
+	case "DaemonSet":
+		return daemonSetReady(obj)
+	case "Job":
+		return jobReady(obj)
+	case "CustomResourceDefinition":
+		if conditionTrue(obj, "Established") {
+			return true, "", nil
+		}
+		return false, "waiting for CRD to be established", nil
+	default:
+		return true, "", nil
+	}
+}
+
+func deploymentReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if !generationObserved(obj) {
+		return false, "waiting for rollout to be observed", nil
+	}
+
+	if condition(obj, "Progressing") == "False" {
+		return false, "", fmt.Errorf("rollout stalled: %s", conditionReason(obj, "Progressing"))
+	}
+
+	replicas := nestedInt(obj, 1, "spec", "replicas")
+	updated := nestedInt(obj, 0, "status", "updatedReplicas")
+	current := nestedInt(obj, 0, "status", "replicas")
+	available := nestedInt(obj, 0, "status", "availableReplicas")
+
+	switch {
+	case updated < replicas:
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+	case current > updated:
+		return false, fmt.Sprintf("%d old replicas pending termination", current-updated), nil
+	case available < updated:
+		return false, fmt.Sprintf("%d of %d updated replicas available", available, updated), nil
+	}
+
+	return true, fmt.Sprintf("%d replicas available", available), nil
+}
+
+func statefulSetReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if !generationObserved(obj) {
+		return false, "waiting for rollout to be observed", nil
+	}
+
+	replicas := nestedInt(obj, 1, "spec", "replicas")
+	ready := nestedInt(obj, 0, "status", "readyReplicas")
+	if ready < replicas {
+		return false, fmt.Sprintf("%d of %d replicas ready", ready, replicas), nil
+	}
+
+	currentRevision := nestedString(obj, "status", "currentRevision")
+	updateRevision := nestedString(obj, "status", "updateRevision")
+	if updateRevision != "" && currentRevision != updateRevision {
+		return false, fmt.Sprintf("waiting for revision %s", updateRevision), nil
+	}
+
+	return true, fmt.Sprintf("%d replicas ready", ready), nil
+}
+
+func daemonSetReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if !generationObserved(obj) {
+		return false, "waiting for rollout to be observed", nil
+	}
+
+	desired := nestedInt(obj, 0, "status", "desiredNumberScheduled")
+	updated := nestedInt(obj, 0, "status", "updatedNumberScheduled")
+	available := nestedInt(obj, 0, "status", "numberAvailable")
+
+	switch {
+	case updated < desired:
+		return false, fmt.Sprintf("%d of %d pods updated", updated, desired), nil
+	case available < desired:
+		return false, fmt.Sprintf("%d of %d pods available", available, desired), nil
+	}
+
+	return true, fmt.Sprintf("%d pods available", available), nil
+}
+
+func jobReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if conditionTrue(obj, "Failed") {
+		return false, "", fmt.Errorf("job failed: %s", conditionReason(obj, "Failed"))
+	}
+
+	if conditionTrue(obj, "Complete") {
+		return true, "complete", nil
+	}
+
+	succeeded := nestedInt(obj, 0, "status", "succeeded")
+	completions := nestedInt(obj, 1, "spec", "completions")
+	return false, fmt.Sprintf("%d of %d completions", succeeded, completions), nil
+}
+
+// generationObserved reports if the controller has observed the latest generation.
+func generationObserved(obj *unstructured.Unstructured) bool {
+	return nestedInt(obj, 0, "status", "observedGeneration") >= obj.GetGeneration()
+}
+
+func conditionTrue(obj *unstructured.Unstructured, conditionType string) bool {
+	return condition(obj, conditionType) == "True"
+}
+
+// condition returns the status of a condition. It returns an empty string if
+// the condition does not exist.
+func condition(obj *unstructured.Unstructured, conditionType string) string {
+	if c := findCondition(obj, conditionType); c != nil {
+		status, _ := c["status"].(string)
+		return status
+	}
+
+	return ""
+}
+
+func conditionReason(obj *unstructured.Unstructured, conditionType string) string {
+	c := findCondition(obj, conditionType)
+	if c == nil {
+		return ""
+	}
+
+	if message, ok := c["message"].(string); ok && message != "" {
+		return message
+	}
+
+	reason, _ := c["reason"].(string)
+	return reason
+}
+
+func findCondition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	status, ok := obj.Object["status"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	conditions, ok := status["conditions"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, item := range conditions {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if t, _ := c["type"].(string); t == conditionType {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func nestedField(obj *unstructured.Unstructured, fields ...string) (interface{}, bool) {
+	var cur interface{} = obj.Object
+	for _, field := range fields {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if cur, ok = m[field]; !ok {
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+// nestedInt returns the integer at a path in an object, or a default if it does not exist.
+func nestedInt(obj *unstructured.Unstructured, defaultValue int64, fields ...string) int64 {
+	v, ok := nestedField(obj, fields...)
+	if !ok {
+		return defaultValue
+	}
+
+	switch t := v.(type) {
+	case int64:
+		return t
+	case int:
+		return int64(t)
+	case float64:
+		return int64(t)
+	default:
+		return defaultValue
+	}
+}
+
+func nestedString(obj *unstructured.Unstructured, fields ...string) string {
+	v, _ := nestedField(obj, fields...)
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,210 @@
+package k8sutil
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// workload creates an object from JSON with a kind and name prepended. Numbers
+// are decoded as int64, as they are by the dynamic client.
+func workload(t *testing.T, kind, fields string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, json.Unmarshal([]byte(`{"apiVersion": "apps/v1beta2", "kind": "`+kind+`", `+fields+`}`), &obj.Object))
+	obj.SetName("web")
+	return obj
+}
+
+func Test_objectReady(t *testing.T) {
+	cases := []struct {
+		name    string
+		kind    string
+		fields  string
+		ready   bool
+		message string
+		err     string
+	}{
+		{
+			name:    "deployment ready",
+			kind:    "Deployment",
+			fields:  `"metadata": {"generation": 2}, "spec": {"replicas": 3}, "status": {"observedGeneration": 2, "replicas": 3, "updatedReplicas": 3, "availableReplicas": 3}`,
+			ready:   true,
+			message: "3 replicas available",
+		},
+		{
+			name:    "deployment generation not observed",
+			kind:    "Deployment",
+			fields:  `"metadata": {"generation": 3}, "spec": {"replicas": 3}, "status": {"observedGeneration": 2, "replicas": 3, "updatedReplicas": 3, "availableReplicas": 3}`,
+			message: "waiting for rollout to be observed",
+		},
+		{
+			name:    "deployment replicas updating",
+			kind:    "Deployment",
+			fields:  `"metadata": {"generation": 2}, "spec": {"replicas": 3}, "status": {"observedGeneration": 2, "replicas": 3, "updatedReplicas": 1, "availableReplicas": 3}`,
+			message: "1 of 3 replicas updated",
+		},
+		{
+			name:    "deployment old replicas terminating",
+			kind:    "Deployment",
+			fields:  `"metadata": {"generation": 2}, "spec": {"replicas": 3}, "status": {"observedGeneration": 2, "replicas": 4, "updatedReplicas": 3, "availableReplicas": 3}`,
+			message: "1 old replicas pending termination",
+		},
+		{
+			name:    "deployment replicas unavailable",
+			kind:    "Deployment",
+			fields:  `"metadata": {"generation": 2}, "spec": {"replicas": 3}, "status": {"observedGeneration": 2, "replicas": 3, "updatedReplicas": 3, "availableReplicas": 2}`,
+			message: "2 of 3 updated replicas available",
+		},
+		{
+			name:    "deployment default replicas",
+			kind:    "Deployment",
+			fields:  `"metadata": {"generation": 1}, "spec": {}, "status": {"observedGeneration": 1}`,
+			message: "0 of 1 replicas updated",
+		},
+		{
+			name: "deployment stalled",
+			kind: "Deployment",
+			fields: `"metadata": {"generation": 1}, "spec": {"replicas": 1}, "status": {"observedGeneration": 1,
+				"conditions": [{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded", "message": "web has timed out progressing"}]}`,
+			err: "rollout stalled: web has timed out progressing",
+		},
+		{
+			name:    "statefulset ready",
+			kind:    "StatefulSet",
+			fields:  `"metadata": {"generation": 1}, "spec": {"replicas": 2}, "status": {"observedGeneration": 1, "readyReplicas": 2, "currentRevision": "web-1", "updateRevision": "web-1"}`,
+			ready:   true,
+			message: "2 replicas ready",
+		},
+		{
+			name:    "statefulset generation not observed",
+			kind:    "StatefulSet",
+			fields:  `"metadata": {"generation": 2}, "spec": {"replicas": 2}, "status": {"observedGeneration": 1, "readyReplicas": 2}`,
+			message: "waiting for rollout to be observed",
+		},
+		{
+			name:    "statefulset replicas not ready",
+			kind:    "StatefulSet",
+			fields:  `"metadata": {"generation": 1}, "spec": {"replicas": 2}, "status": {"observedGeneration": 1, "readyReplicas": 1}`,
+			message: "1 of 2 replicas ready",
+		},
+		{
+			name:    "statefulset revision updating",
+			kind:    "StatefulSet",
+			fields:  `"metadata": {"generation": 1}, "spec": {"replicas": 2}, "status": {"observedGeneration": 1, "readyReplicas": 2, "currentRevision": "web-1", "updateRevision": "web-2"}`,
+			message: "waiting for revision web-2",
+		},
+		{
+			name:    "daemonset ready",
+			kind:    "DaemonSet",
+			fields:  `"metadata": {"generation": 1}, "status": {"observedGeneration": 1, "desiredNumberScheduled": 3, "updatedNumberScheduled": 3, "numberAvailable": 3}`,
+			ready:   true,
+			message: "3 pods available",
+		},
+		{
+			name:    "daemonset generation not observed",
+			kind:    "DaemonSet",
+			fields:  `"metadata": {"generation": 2}, "status": {"observedGeneration": 1, "desiredNumberScheduled": 3, "updatedNumberScheduled": 3, "numberAvailable": 3}`,
+			message: "waiting for rollout to be observed",
+		},
+		{
+			name:    "daemonset pods updating",
+			kind:    "DaemonSet",
+			fields:  `"metadata": {"generation": 1}, "status": {"observedGeneration": 1, "desiredNumberScheduled": 3, "updatedNumberScheduled": 2, "numberAvailable": 3}`,
+			message: "2 of 3 pods updated",
+		},
+		{
+			name:    "daemonset pods unavailable",
+			kind:    "DaemonSet",
+			fields:  `"metadata": {"generation": 1}, "status": {"observedGeneration": 1, "desiredNumberScheduled": 3, "updatedNumberScheduled": 3, "numberAvailable": 1}`,
+			message: "1 of 3 pods available",
+		},
+		{
+			name:    "job complete",
+			kind:    "Job",
+			fields:  `"status": {"succeeded": 1, "conditions": [{"type": "Complete", "status": "True"}]}`,
+			ready:   true,
+			message: "complete",
+		},
+		{
+			name:    "job running",
+			kind:    "Job",
+			fields:  `"spec": {"completions": 3}, "status": {"succeeded": 1}`,
+			message: "1 of 3 completions",
+		},
+		{
+			name:   "job failed",
+			kind:   "Job",
+			fields: `"status": {"failed": 6, "conditions": [{"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded"}]}`,
+			err:    "job failed: BackoffLimitExceeded",
+		},
+		{
+			name:    "crd established",
+			kind:    "CustomResourceDefinition",
+			fields:  `"status": {"conditions": [{"type": "Established", "status": "True"}]}`,
+			ready:   true,
+			message: "",
+		},
+		{
+			name:    "crd not established",
+			kind:    "CustomResourceDefinition",
+			fields:  `"status": {"conditions": [{"type": "Established", "status": "False"}]}`,
+			message: "waiting for CRD to be established",
+		},
+		{
+			name:   "kind without readiness",
+			kind:   "ConfigMap",
+			fields: `"data": {}`,
+			ready:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ready, message, err := objectReady(workload(t, tc.kind, tc.fields))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				require.False(t, ready)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.ready, ready)
+			require.Equal(t, tc.message, message)
+		})
+	}
+}
+
+func Test_waitForReady(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		job := workload(t, "Job", `"status": {"conditions": [{"type": "Complete", "status": "True"}]}`)
+		rc := newFakeResource("jobs", job)
+
+		var out bytes.Buffer
+		err := waitForReady(&out, []*readyTarget{{obj: job, rc: rc}}, time.Second)
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "complete")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		job := workload(t, "Job", `"status": {"conditions": [{"type": "Failed", "status": "True", "message": "pods failed"}]}`)
+		rc := newFakeResource("jobs", job)
+
+		var out bytes.Buffer
+		err := waitForReady(&out, []*readyTarget{{obj: job, rc: rc}}, time.Second)
+		require.EqualError(t, err, "Job web failed: job failed: pods failed")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		job := workload(t, "Job", `"spec": {"completions": 1}`)
+		rc := newFakeResource("jobs", job)
+
+		var out bytes.Buffer
+		err := waitForReady(&out, []*readyTarget{{obj: job, rc: rc}}, time.Nanosecond)
+		require.EqualError(t, err, "timed out after 1ns waiting for Job web")
+		require.Contains(t, out.String(), "0 of 1 completions")
+	})
+}
@@ -0,0 +1,181 @@
+package k8sutil
+
+import (
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/ksonnet/ksonnet/utils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// DefaultParallelism is the default number of objects applied concurrently.
+	DefaultParallelism = 8
+)
+
+var (
+	// clusterWaveKinds are applied first since other objects depend on them.
+	clusterWaveKinds = map[string]bool{
+		"CustomResourceDefinition": true,
+		"Namespace":                true,
+		"PodSecurityPolicy":        true,
+		"PriorityClass":            true,
+		"StorageClass":             true,
+		"ThirdPartyResource":       true,
+	}
+
+	// workloadWaveKinds start pods, so they are applied last.
+	workloadWaveKinds = map[string]bool{
+		"CronJob":               true,
+		"DaemonSet":             true,
+		"Deployment":            true,
+		"Job":                   true,
+		"Pod":                   true,
+		"ReplicaSet":            true,
+		"ReplicationController": true,
+		"StatefulSet":           true,
+	}
+)
+
+// waveFor returns the dependency wave for an object. Namespaces and CRDs are in the
+// first wave, workloads are in the last wave, and everything else (e.g. RBAC and
+// configuration) is in between.
+func waveFor(obj *unstructured.Unstructured) int {
+	switch kind := obj.GetKind(); {
+	case clusterWaveKinds[kind]:
+		return 0
+	case workloadWaveKinds[kind]:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// dependencyWaves groups objects into dependency waves. Objects within a wave are
+// sorted in dependency order, as they were before waves were used, and otherwise
+// keep their original order.
+func dependencyWaves(objects []*unstructured.Unstructured) [][]*unstructured.Unstructured {
+	sorted := make([]*unstructured.Unstructured, len(objects))
+	copy(sorted, objects)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return waveFor(sorted[i]) < waveFor(sorted[j])
+	})
+
+	var waves [][]*unstructured.Unstructured
+	for i, obj := range sorted {
+		if i == 0 || waveFor(obj) != waveFor(sorted[i-1]) {
+			waves = append(waves, nil)
+		}
+
+		waves[len(waves)-1] = append(waves[len(waves)-1], obj)
+	}
+
+	for _, wave := range waves {
+		sort.Stable(utils.DependencyOrder(wave))
+	}
+
+	return waves
+}
+
+// applyResult is the result of applying an object.
+type applyResult struct {
+	desc   string
+	rc     dynamic.ResourceInterface
+	live   *unstructured.Unstructured
+	newobj metav1.Object
+	plan   *PlanObject
+	err    error
+}
+
+// runWave calls fn for each object using at most workers goroutines. Each call
+// gets its own logger. Log entries are buffered and replayed in object order
+// once the wave completes, so output is deterministic. Results are returned in
+// object order.
+func runWave(objects []*unstructured.Unstructured, workers int, fn func(*unstructured.Unstructured, log.FieldLogger) applyResult) []applyResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]applyResult, len(objects))
+	recorders := make([]*entryRecorder, len(objects))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				logger, recorder := newBufferedLogger()
+				recorders[i] = recorder
+				results[i] = fn(objects[i], logger)
+			}
+		}()
+	}
+
+	for i := range objects {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, recorder := range recorders {
+		recorder.replay()
+	}
+
+	return results
+}
+
+// entryRecorder is a log hook which records entries so they can be replayed
+// through the standard logger.
+type entryRecorder struct {
+	entries []*log.Entry
+}
+
+var _ log.Hook = (*entryRecorder)(nil)
+
+func newBufferedLogger() (*log.Logger, *entryRecorder) {
+	recorder := &entryRecorder{}
+
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	logger.Level = log.GetLevel()
+	logger.Hooks.Add(recorder)
+
+	return logger, recorder
+}
+
+func (r *entryRecorder) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (r *entryRecorder) Fire(entry *log.Entry) error {
+	r.entries = append(r.entries, &log.Entry{
+		Level:   entry.Level,
+		Message: entry.Message,
+		Data:    entry.Data,
+	})
+
+	return nil
+}
+
+func (r *entryRecorder) replay() {
+	for _, entry := range r.entries {
+		e := log.WithFields(entry.Data)
+		switch entry.Level {
+		case log.DebugLevel:
+			e.Debug(entry.Message)
+		case log.InfoLevel:
+			e.Info(entry.Message)
+		case log.WarnLevel:
+			e.Warn(entry.Message)
+		default:
+			e.Error(entry.Message)
+		}
+	}
+}
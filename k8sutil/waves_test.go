@@ -0,0 +1,150 @@
+package k8sutil
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func kindObject(apiVersion, kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	return obj
+}
+
+func objectNames(objects []*unstructured.Unstructured) []string {
+	var names []string
+	for _, obj := range objects {
+		names = append(names, obj.GetName())
+	}
+	return names
+}
+
+func Test_dependencyWaves(t *testing.T) {
+	objects := []*unstructured.Unstructured{
+		kindObject("apps/v1beta2", "Deployment", "apps-deployment"),
+		kindObject("v1", "Service", "service"),
+		kindObject("batch/v1", "Job", "job"),
+		kindObject("extensions/v1beta1", "Deployment", "extensions-deployment"),
+		kindObject("v1", "ConfigMap", "config"),
+		kindObject("storage.k8s.io/v1", "StorageClass", "storage"),
+		kindObject("v1", "Namespace", "namespace"),
+		kindObject("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "crd"),
+	}
+
+	waves := dependencyWaves(objects)
+
+	var got [][]string
+	for _, wave := range waves {
+		got = append(got, objectNames(wave))
+	}
+
+	// Within a wave, kinds known to utils.DependencyOrder keep its order. Other
+	// objects keep their original order.
+	require.Equal(t, [][]string{
+		{"storage", "namespace", "crd"},
+		{"service", "config"},
+		{"apps-deployment", "job", "extensions-deployment"},
+	}, got)
+
+	require.Equal(t, "apps-deployment", objects[0].GetName())
+}
+
+func Test_dependencyWaves_empty(t *testing.T) {
+	require.Nil(t, dependencyWaves(nil))
+}
+
+func Test_runWave(t *testing.T) {
+	var objects []*unstructured.Unstructured
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		objects = append(objects, kindObject("v1", "ConfigMap", name))
+	}
+
+	cases := []struct {
+		name    string
+		workers int
+		max     int
+	}{
+		{name: "limited", workers: 2, max: 2},
+		{name: "serial", workers: 1, max: 1},
+		{name: "invalid worker count", workers: 0, max: 1},
+		{name: "more workers than objects", workers: 10, max: len(objects)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			running, max := 0, 0
+
+			results := runWave(objects, tc.workers, func(obj *unstructured.Unstructured, logger log.FieldLogger) applyResult {
+				mu.Lock()
+				running++
+				if running > max {
+					max = running
+				}
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+
+				return applyResult{desc: obj.GetName()}
+			})
+
+			require.Equal(t, tc.max, max)
+
+			var descs []string
+			for _, result := range results {
+				descs = append(descs, result.desc)
+			}
+			require.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, descs)
+		})
+	}
+}
+
+func Test_runWave_replaysLogs(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	log.SetFormatter(&log.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	defer log.SetFormatter(&log.TextFormatter{})
+
+	objects := []*unstructured.Unstructured{
+		kindObject("v1", "ConfigMap", "slow"),
+		kindObject("v1", "ConfigMap", "fast"),
+	}
+
+	runWave(objects, 2, func(obj *unstructured.Unstructured, logger log.FieldLogger) applyResult {
+		if obj.GetName() == "slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		logger.Infof("applying %s", obj.GetName())
+		logger.WithField("object", obj.GetName()).Warn("applied")
+		return applyResult{}
+	})
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+
+	require.Equal(t, []string{
+		`level=info msg="applying slow"`,
+		`level=warning msg=applied object=slow`,
+		`level=info msg="applying fast"`,
+		`level=warning msg=applied object=fast`,
+	}, lines)
+}
@@ -0,0 +1,74 @@
+package ksutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FprintDiff writes a unified diff between two documents to a writer. It returns
+// true if the documents differ. When colorize is true, added and removed lines are
+// colored.
+func FprintDiff(out io.Writer, from, to, fromName, toName string, colorize bool) (bool, error) {
+	if from == to {
+		return false, nil
+	}
+
+	ud := difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(ud)
+	if err != nil {
+		return false, err
+	}
+
+	for _, line := range splitLines(diff) {
+		line = strings.TrimSuffix(line, "\n")
+		if colorize {
+			line = colorizeDiffLine(line)
+		}
+
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func colorizeDiffLine(line string) string {
+	var c *color.Color
+	switch {
+	case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+		c = color.New(color.Bold)
+	case strings.HasPrefix(line, "@@"):
+		c = color.New(color.FgCyan)
+	case strings.HasPrefix(line, "+"):
+		c = color.New(color.FgGreen)
+	case strings.HasPrefix(line, "-"):
+		c = color.New(color.FgRed)
+	default:
+		return line
+	}
+
+	c.EnableColor()
+	return c.Sprint(line)
+}
+
+// splitLines splits a string into lines while keeping the line endings.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,42 @@
+package ksutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFprintDiff(t *testing.T) {
+	from := "a: 1\nb: 2\nc: 3\n"
+	to := "a: 1\nb: 3\nc: 3\nd: 4\n"
+
+	var buf bytes.Buffer
+	changed, err := FprintDiff(&buf, from, to, "live", "desired", false)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	b, err := ioutil.ReadFile("testdata/diff/diff.txt")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(b), buf.String())
+}
+
+func TestFprintDiff_same(t *testing.T) {
+	var buf bytes.Buffer
+	changed, err := FprintDiff(&buf, "a: 1\n", "a: 1\n", "live", "desired", true)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Empty(t, buf.String())
+}
+
+func TestFprintDiff_color(t *testing.T) {
+	var buf bytes.Buffer
+	changed, err := FprintDiff(&buf, "a: 1\n", "a: 2\n", "live", "desired", true)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Contains(t, buf.String(), "\x1b[31m-a: 1\x1b[0m")
+	require.Contains(t, buf.String(), "\x1b[32m+a: 2\x1b[0m")
+}
@@ -0,0 +1,143 @@
+package ksutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// OutputJSON prints data as JSON.
+	OutputJSON = "json"
+	// OutputYAML prints data as YAML.
+	OutputYAML = "yaml"
+
+	outputJSONPath   = "jsonpath="
+	outputGoTemplate = "go-template="
+)
+
+// Printer prints data as a table or in a machine readable format. Machine
+// readable formats use the data's JSON field names.
+type Printer struct {
+	w        io.Writer
+	format   string
+	template string
+}
+
+// NewPrinter creates a printer for an output format. Valid formats are blank
+// for a table, json, yaml, jsonpath=<template> and go-template=<template>.
+// tableFormats are additional formats which are printed as tables, e.g. wide.
+func NewPrinter(w io.Writer, output string, tableFormats ...string) (*Printer, error) {
+	p := &Printer{w: w}
+
+	switch {
+	case output == "", output == OutputJSON, output == OutputYAML:
+		p.format = output
+	case strings.HasPrefix(output, outputJSONPath):
+		p.format = outputJSONPath
+		p.template = strings.TrimPrefix(output, outputJSONPath)
+	case strings.HasPrefix(output, outputGoTemplate):
+		p.format = outputGoTemplate
+		p.template = strings.TrimPrefix(output, outputGoTemplate)
+	default:
+		for _, format := range tableFormats {
+			if output == format {
+				p.format = output
+				return p, nil
+			}
+		}
+
+		return nil, fmt.Errorf("invalid output option %q", output)
+	}
+
+	if (p.format == outputJSONPath || p.format == outputGoTemplate) && p.template == "" {
+		return nil, fmt.Errorf("output option %q requires a template", output)
+	}
+
+	return p, nil
+}
+
+// Format is the printer's output format. Template formats are returned without
+// their template.
+func (p *Printer) Format() string {
+	return p.format
+}
+
+// IsTable reports whether the printer prints tables.
+func (p *Printer) IsTable() bool {
+	switch p.format {
+	case OutputJSON, OutputYAML, outputJSONPath, outputGoTemplate:
+		return false
+	default:
+		return true
+	}
+}
+
+// Print prints v. If the printer prints tables, table is called to render v
+// instead.
+func (p *Printer) Print(v interface{}, table func(w io.Writer) error) error {
+	if p.IsTable() {
+		return table(p.w)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	switch p.format {
+	case OutputJSON:
+		var doc interface{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case OutputYAML:
+		out, err := yaml.JSONToYAML(b)
+		if err != nil {
+			return err
+		}
+
+		_, err = p.w.Write(out)
+		return err
+	case outputJSONPath:
+		var doc interface{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+
+		jp := jsonpath.New("output")
+		if err := jp.Parse(p.template); err != nil {
+			return fmt.Errorf("parse jsonpath template: %v", err)
+		}
+
+		if err := jp.Execute(p.w, doc); err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p.w)
+		return err
+	case outputGoTemplate:
+		var doc interface{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+
+		t, err := template.New("output").Parse(p.template)
+		if err != nil {
+			return fmt.Errorf("parse go-template: %v", err)
+		}
+
+		return t.Execute(p.w, doc)
+	default:
+		return fmt.Errorf("invalid output option %q", p.format)
+	}
+}
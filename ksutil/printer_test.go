@@ -0,0 +1,108 @@
+package ksutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type printerItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestPrinter(t *testing.T) {
+	items := []printerItem{
+		{Name: "a", Count: 1},
+		{Name: "b", Count: 2},
+	}
+
+	cases := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "table",
+			output:   "",
+			expected: "table\n",
+		},
+		{
+			name:     "wide",
+			output:   "wide",
+			expected: "table\n",
+		},
+		{
+			name:   "json",
+			output: "json",
+			expected: `[
+  {
+    "count": 1,
+    "name": "a"
+  },
+  {
+    "count": 2,
+    "name": "b"
+  }
+]
+`,
+		},
+		{
+			name:     "yaml",
+			output:   "yaml",
+			expected: "- count: 1\n  name: a\n- count: 2\n  name: b\n",
+		},
+		{
+			name:     "jsonpath",
+			output:   "jsonpath={[*].name}",
+			expected: "a b\n",
+		},
+		{
+			name:     "go-template",
+			output:   "go-template={{range .}}{{.name}}={{.count}};{{end}}",
+			expected: "a=1;b=2;",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p, err := NewPrinter(&buf, tc.output, "wide")
+			require.NoError(t, err)
+
+			err = p.Print(items, func(w io.Writer) error {
+				_, err := fmt.Fprintln(w, "table")
+				return err
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestNewPrinter_invalid(t *testing.T) {
+	outputs := []string{"wide", "xml", "jsonpath=", "go-template="}
+	for _, output := range outputs {
+		t.Run(output, func(t *testing.T) {
+			_, err := NewPrinter(&bytes.Buffer{}, output)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPrinter_invalid_template(t *testing.T) {
+	outputs := []string{"jsonpath={.name", "go-template={{.name"}
+	for _, output := range outputs {
+		t.Run(output, func(t *testing.T) {
+			p, err := NewPrinter(&bytes.Buffer{}, output)
+			require.NoError(t, err)
+
+			err = p.Print(printerItem{}, nil)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package ksutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortByColumn sorts list, which must be a slice, by a table column. header
+// names the table's columns and row returns the columns for the element at an
+// index. Column names match regardless of case and dashes. Values which are
+// both numbers are compared numerically. The sort is stable, so elements with
+// equal values keep their order. A blank column leaves list unchanged.
+func SortByColumn(list interface{}, header []string, column string, row func(i int) []string) error {
+	if column == "" {
+		return nil
+	}
+
+	col := -1
+	for i := range header {
+		if normalizeColumn(header[i]) == normalizeColumn(column) {
+			col = i
+			break
+		}
+	}
+
+	if col == -1 {
+		var names []string
+		for i := range header {
+			names = append(names, strings.ToLower(header[i]))
+		}
+		return fmt.Errorf("unable to sort by %q; valid columns are %s", column, strings.Join(names, ", "))
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return lessValue(row(i)[col], row(j)[col])
+	})
+
+	return nil
+}
+
+func normalizeColumn(s string) string {
+	return strings.Replace(strings.ToLower(s), "-", "", -1)
+}
+
+func lessValue(a, b string) bool {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return fa < fb
+	}
+
+	return a < b
+}
@@ -0,0 +1,65 @@
+package ksutil
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortByColumn(t *testing.T) {
+	type item struct {
+		name  string
+		index int
+	}
+
+	header := []string{"NAME", "INDEX"}
+
+	cases := []struct {
+		name     string
+		column   string
+		expected []string
+		isErr    bool
+	}{
+		{
+			name:     "blank column",
+			expected: []string{"b/10", "a/2", "c/1", "a/1"},
+		},
+		{
+			name:     "by name",
+			column:   "name",
+			expected: []string{"a/2", "a/1", "b/10", "c/1"},
+		},
+		{
+			name:     "by index numerically",
+			column:   "Index",
+			expected: []string{"c/1", "a/1", "a/2", "b/10"},
+		},
+		{
+			name:   "unknown column",
+			column: "kind",
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			items := []item{{"b", 10}, {"a", 2}, {"c", 1}, {"a", 1}}
+
+			err := SortByColumn(items, header, tc.column, func(i int) []string {
+				return []string{items[i].name, strconv.Itoa(items[i].index)}
+			})
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, item := range items {
+				got = append(got, item.name+"/"+strconv.Itoa(item.index))
+			}
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package node
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+var (
+	// metadataSetters are the metadata setters most objects are created with.
+	metadataSetters = []string{"withNamespace", "withLabels", "withAnnotations"}
+)
+
+// Param is a function parameter.
+type Param struct {
+	Name string
+	// Default is the parameter's default value. It is nil if the parameter is
+	// required or its default isn't a literal. Values in the default which
+	// refer to other parameters are a Ref.
+	Default interface{}
+	// Type is the type of value the constructor's setter for the parameter
+	// expects. It is blank if it isn't known.
+	Type ValueType
+}
+
+// Ref is a value in a parameter's default which refers to another parameter.
+type Ref string
+
+// ValueType is a type of parameter value.
+type ValueType string
+
+const (
+	// ValueTypeArray is an array.
+	ValueTypeArray ValueType = "array"
+	// ValueTypeObject is an object.
+	ValueTypeObject ValueType = "object"
+)
+
+// Setter is a `with` function which sets a value in an object.
+type Setter struct {
+	// Name is the name of the function, e.g. withReplicas.
+	Name string
+	// Path is the path to the object containing the function relative to the
+	// kind, e.g. mixin.spec.
+	Path []string
+	// Param is the name of the value the function sets.
+	Param string
+}
+
+// Constructor describes how an object of a kind is created.
+type Constructor struct {
+	// Path is the path to the kind, e.g. apps.v1beta2.deployment.
+	Path []string
+	// Params are the parameters of the kind's `new` function.
+	Params []Param
+	// Setters are commonly used setters which aren't covered by Params.
+	Setters []Setter
+}
+
+// Constructor finds the `new` function for the kind at path and the setters
+// for its metadata and spec.
+func (n *Node) Constructor(path ...string) (*Constructor, error) {
+	if len(path) == 0 {
+		return nil, errors.New("search path is empty")
+	}
+
+	obj := n.obj
+	for _, name := range path {
+		child, err := Find(obj, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to find %s", strings.Join(path, "."))
+		}
+		obj = child
+	}
+
+	fn := findMethod(obj, "new")
+	if fn == nil {
+		return nil, errors.Errorf("%s does not have a new function", strings.Join(path, "."))
+	}
+
+	c := &Constructor{Path: path}
+
+	names := make(map[string]bool)
+	for _, id := range fn.Parameters.Required {
+		names[string(id)] = true
+	}
+	for _, p := range fn.Parameters.Optional {
+		names[string(p.Name)] = true
+	}
+
+	types := paramTypes(obj, fn.Body)
+
+	for _, id := range fn.Parameters.Required {
+		c.Params = append(c.Params, Param{Name: string(id), Type: types[string(id)]})
+	}
+
+	for _, p := range fn.Parameters.Optional {
+		v, _ := literalValue(p.DefaultArg, names)
+		c.Params = append(c.Params, Param{Name: string(p.Name), Default: v, Type: types[string(p.Name)]})
+	}
+
+	c.addSetters(obj, nil, nil)
+
+	if mixin, err := Find(obj, "mixin"); err == nil {
+		if metadata, err := Find(mixin, "metadata"); err == nil {
+			c.addSetters(metadata, []string{"mixin", "metadata"}, metadataSetters)
+		}
+
+		if spec, err := Find(mixin, "spec"); err == nil {
+			c.addSetters(spec, []string{"mixin", "spec"}, nil)
+		}
+	}
+
+	return c, nil
+}
+
+// addSetters adds the setters in obj. If names is set, only those setters are
+// added. Setters for values which are constructor params are skipped.
+func (c *Constructor) addSetters(obj *astext.Object, path, names []string) {
+	members, err := FindMembers(obj)
+	if err != nil {
+		return
+	}
+
+	fns := append([]string(nil), members.Functions...)
+	sort.Strings(fns)
+
+	for _, id := range fns {
+		if !strings.HasPrefix(id, "with") || strings.HasSuffix(id, "Mixin") {
+			continue
+		}
+
+		if names != nil && !stringInSlice(id, names) {
+			continue
+		}
+
+		fn := findMethod(obj, id)
+		if fn == nil || len(fn.Parameters.Required) != 1 {
+			continue
+		}
+
+		param := string(fn.Parameters.Required[0])
+		if c.hasParam(param) {
+			continue
+		}
+
+		c.Setters = append(c.Setters, Setter{Name: id, Path: path, Param: param})
+	}
+}
+
+func (c *Constructor) hasParam(name string) bool {
+	for _, p := range c.Params {
+		if p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findMethod finds a method by name in an object.
+func findMethod(obj *astext.Object, name string) *ast.Function {
+	for _, of := range obj.Fields {
+		if of.Id != nil && string(*of.Id) == name && of.Method != nil {
+			return of.Method
+		}
+	}
+
+	return nil
+}
+
+// paramTypes finds the types of values params are passed to setters as in body.
+// obj is the kind containing the setters.
+func paramTypes(obj *astext.Object, body ast.Node) map[string]ValueType {
+	types := make(map[string]ValueType)
+
+	walk(body, func(node ast.Node) {
+		apply, ok := node.(*ast.Apply)
+		if !ok || len(apply.Arguments.Positional) != 1 {
+			return
+		}
+
+		arg, ok := apply.Arguments.Positional[0].(*ast.Var)
+		if !ok {
+			return
+		}
+
+		name, path, ok := setterPath(apply.Target)
+		if !ok {
+			return
+		}
+
+		parent := obj
+		for _, id := range path {
+			child, err := Find(parent, id)
+			if err != nil {
+				return
+			}
+			parent = child
+		}
+
+		if t := setterType(parent, name); t != "" {
+			types[string(arg.Id)] = t
+		}
+	})
+
+	return types
+}
+
+// setterPath returns the name of the function target refers to and the path
+// to the object containing it relative to self. Setters return the object
+// they are called on, so chained setters are in the same object.
+func setterPath(target ast.Node) (string, []string, bool) {
+	index, ok := target.(*ast.Index)
+	if !ok || index.Id == nil {
+		return "", nil, false
+	}
+
+	var path []string
+	for cur := index.Target; ; {
+		switch t := cur.(type) {
+		case *ast.Self:
+			return string(*index.Id), path, true
+		case *ast.Index:
+			if t.Id == nil {
+				return "", nil, false
+			}
+			path = append([]string{string(*t.Id)}, path...)
+			cur = t.Target
+		case *ast.Apply:
+			_, parent, ok := setterPath(t.Target)
+			if !ok {
+				return "", nil, false
+			}
+			return string(*index.Id), append(parent, path...), true
+		default:
+			return "", nil, false
+		}
+	}
+}
+
+// setterType returns the type of value a setter expects. Setters for arrays
+// and objects have a mixin variant, and setters for arrays check whether they
+// were passed an array.
+func setterType(obj *astext.Object, name string) ValueType {
+	fn := findMethod(obj, name)
+	if fn == nil || findMethod(obj, name+"Mixin") == nil {
+		return ""
+	}
+
+	t := ValueTypeObject
+	walk(fn.Body, func(node ast.Node) {
+		if s, ok := node.(*ast.LiteralString); ok && s.Value == "array" {
+			t = ValueTypeArray
+		}
+	})
+
+	return t
+}
+
+// walk calls fn for node and the expressions in it. Only expressions which
+// setters and constructors are built from are visited.
+func walk(node ast.Node, fn func(ast.Node)) {
+	if node == nil {
+		return
+	}
+
+	fn(node)
+
+	switch t := node.(type) {
+	case *ast.Apply:
+		walk(t.Target, fn)
+		for _, arg := range t.Arguments.Positional {
+			walk(arg, fn)
+		}
+	case *ast.Binary:
+		walk(t.Left, fn)
+		walk(t.Right, fn)
+	case *ast.Conditional:
+		walk(t.Cond, fn)
+		walk(t.BranchTrue, fn)
+		walk(t.BranchFalse, fn)
+	case *ast.Index:
+		walk(t.Target, fn)
+		walk(t.Index, fn)
+	case *ast.Parens:
+		walk(t.Inner, fn)
+	}
+}
+
+// literalValue converts a node containing only literals to a Go value.
+// Variables which are in params are converted to a Ref.
+func literalValue(node ast.Node, params map[string]bool) (interface{}, bool) {
+	switch t := node.(type) {
+	case *ast.Var:
+		if !params[string(t.Id)] {
+			return nil, false
+		}
+		return Ref(t.Id), true
+	case *ast.LiteralString:
+		return t.Value, true
+	case *ast.LiteralNumber:
+		return t.Value, true
+	case *ast.LiteralBoolean:
+		return t.Value, true
+	case *ast.LiteralNull:
+		return nil, true
+	case *ast.Array:
+		array := make([]interface{}, 0, len(t.Elements))
+		for _, e := range t.Elements {
+			v, ok := literalValue(e, params)
+			if !ok {
+				return nil, false
+			}
+			array = append(array, v)
+		}
+		return array, true
+	case *astext.Object:
+		m := make(map[string]interface{})
+		for _, of := range t.Fields {
+			if of.Method != nil {
+				return nil, false
+			}
+
+			id, err := jsonnetutil.FieldID(of)
+			if err != nil {
+				return nil, false
+			}
+
+			v, ok := literalValue(of.Expr2, params)
+			if !ok {
+				return nil, false
+			}
+			m[id] = v
+		}
+		return m, true
+	default:
+		return nil, false
+	}
+}
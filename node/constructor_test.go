@@ -0,0 +1,91 @@
+package node
+
+import (
+	"testing"
+
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNode_Constructor(t *testing.T) {
+	obj, err := jsonnetutil.Import("testdata/k8s.libsonnet")
+	require.NoError(t, err)
+
+	node := New("root", obj)
+
+	c, err := node.Constructor("apps", "v1beta2", "deployment")
+	require.NoError(t, err)
+
+	expectedParams := []Param{
+		{Name: "name", Default: ""},
+		{Name: "replicas", Default: float64(1)},
+		{Name: "containers", Default: "", Type: ValueTypeArray},
+		{Name: "podLabels", Default: map[string]interface{}{"app": Ref("name")}, Type: ValueTypeObject},
+	}
+	require.Equal(t, expectedParams, c.Params)
+
+	var names []string
+	for _, s := range c.Setters {
+		names = append(names, s.Name)
+	}
+
+	expectedSetters := []string{
+		"withAnnotations",
+		"withLabels",
+		"withNamespace",
+		"withMinReadySeconds",
+		"withPaused",
+		"withProgressDeadlineSeconds",
+		"withRevisionHistoryLimit",
+	}
+	require.Equal(t, expectedSetters, names)
+	require.Equal(t, Setter{Name: "withLabels", Path: []string{"mixin", "metadata"}, Param: "labels"}, c.Setters[1])
+}
+
+func TestNode_Constructor_chained_setters(t *testing.T) {
+	obj, err := jsonnetutil.Import("testdata/k8s.libsonnet")
+	require.NoError(t, err)
+
+	c, err := New("root", obj).Constructor("apps", "v1beta2", "statefulSet")
+	require.NoError(t, err)
+
+	types := make(map[string]ValueType)
+	for _, p := range c.Params {
+		types[p.Name] = p.Type
+	}
+
+	expected := map[string]ValueType{
+		"name":         "",
+		"replicas":     "",
+		"containers":   ValueTypeArray,
+		"volumeClaims": ValueTypeArray,
+		"podLabels":    ValueTypeObject,
+	}
+	require.Equal(t, expected, types)
+}
+
+func TestNode_Constructor_top_level_setters(t *testing.T) {
+	obj, err := jsonnetutil.Import("testdata/k8s.libsonnet")
+	require.NoError(t, err)
+
+	c, err := New("root", obj).Constructor("core", "v1", "configMap")
+	require.NoError(t, err)
+
+	require.Equal(t, Setter{Name: "withData", Param: "data"}, c.Setters[0])
+}
+
+func TestNode_Constructor_invalid(t *testing.T) {
+	obj, err := jsonnetutil.Import("testdata/k8s.libsonnet")
+	require.NoError(t, err)
+
+	node := New("root", obj)
+
+	_, err = node.Constructor()
+	require.Error(t, err)
+
+	_, err = node.Constructor("apps", "v1beta2", "unknown")
+	require.Error(t, err)
+
+	_, err = node.Constructor("apps", "v1beta2")
+	require.Error(t, err)
+}
@@ -394,7 +394,7 @@
       // DEPRECATED - This group version of Deployment is deprecated by apps/v1beta2/Deployment. See the release notes for more information. Deployment enables declarative updates for Pods and ReplicaSets.
       deployment:: {
         local kind = {kind: "Deployment"},
-        new(name="", replicas=1, containers="", podLabels={app: "name"}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
+        new(name="", replicas=1, containers="", podLabels={app: name}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
         mixin:: {
           // Standard object metadata.
           metadata:: {
@@ -819,7 +819,7 @@
       // The StatefulSet guarantees that a given network identity will always map to the same storage identity.
       statefulSet:: {
         local kind = {kind: "StatefulSet"},
-        new(name="", replicas=1, containers="", volumeClaims="", podLabels={app: "name"}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas).withVolumeClaimTemplates(volumeClaims) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
+        new(name="", replicas=1, containers="", volumeClaims="", podLabels={app: name}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas).withVolumeClaimTemplates(volumeClaims) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
         mixin:: {
           metadata:: {
             local __metadataMixin(metadata) = {metadata+: metadata},
@@ -1536,7 +1536,7 @@
       // Deployment enables declarative updates for Pods and ReplicaSets.
       deployment:: {
         local kind = {kind: "Deployment"},
-        new(name="", replicas=1, containers="", podLabels={app: "name"}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
+        new(name="", replicas=1, containers="", podLabels={app: name}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
         mixin:: {
           // Standard object metadata.
           metadata:: {
@@ -2236,7 +2236,7 @@
       // The StatefulSet guarantees that a given network identity will always map to the same storage identity.
       statefulSet:: {
         local kind = {kind: "StatefulSet"},
-        new(name="", replicas=1, containers="", volumeClaims="", podLabels={app: "name"}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas).withVolumeClaimTemplates(volumeClaims) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
+        new(name="", replicas=1, containers="", volumeClaims="", podLabels={app: name}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas).withVolumeClaimTemplates(volumeClaims) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
         mixin:: {
           metadata:: {
             local __metadataMixin(metadata) = {metadata+: metadata},
@@ -7389,7 +7389,7 @@
       // DEPRECATED - This group version of Deployment is deprecated by apps/v1beta2/Deployment. See the release notes for more information. Deployment enables declarative updates for Pods and ReplicaSets.
       deployment:: {
         local kind = {kind: "Deployment"},
-        new(name="", replicas=1, containers="", podLabels={app: "name"}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
+        new(name="", replicas=1, containers="", podLabels={app: name}):: apiVersion + kind + self.mixin.metadata.withName(name) + self.mixin.spec.withReplicas(replicas) + self.mixin.spec.template.metadata.withLabels(podLabels) + self.mixin.spec.template.spec.withContainers(containers),
         mixin:: {
           // Standard object metadata.
           metadata:: {
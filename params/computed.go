@@ -0,0 +1,279 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+const (
+	// envParamsExtVar is the external variable environment params are merged
+	// over.
+	envParamsExtVar = "__ksonnet/params"
+)
+
+var (
+	reParamsImport = regexp.MustCompile(`(?m)import "\.\./\.\./components/params\.libsonnet"`)
+)
+
+// UpgradeImports replaces relative imports of the namespace params in
+// environment params source with the external variable they are merged over.
+func UpgradeImports(src string) string {
+	return reParamsImport.ReplaceAllLiteralString(src, `std.extVar("`+envParamsExtVar+`")`)
+}
+
+// ComputedParams are the paths of params which are set by expressions rather
+// than literal values.
+type ComputedParams [][]string
+
+// Has reports whether the param at path is computed. A param is computed if it,
+// an object containing it, or a value inside it is set by an expression.
+func (c ComputedParams) Has(path []string) bool {
+	for _, p := range c {
+		if hasPathPrefix(path, p) || hasPathPrefix(p, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Child returns the computed paths under key relative to key. If key itself is
+// computed, every path under it is computed.
+func (c ComputedParams) Child(key string) ComputedParams {
+	var out ComputedParams
+	for _, p := range c {
+		if len(p) > 0 && p[0] == key {
+			out = append(out, p[1:])
+		}
+	}
+
+	return out
+}
+
+func hasPathPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ComputedError is returned when setting a param would overwrite an
+// expression.
+type ComputedError struct {
+	Path []string
+}
+
+func (e *ComputedError) Error() string {
+	return fmt.Sprintf("param %q is computed from an expression", strings.Join(e.Path, "."))
+}
+
+// Computed returns the paths of a component's params which are computed.
+// Paths are relative to the component.
+func Computed(componentName, src, root string) (ComputedParams, error) {
+	_, computed, err := toMap(componentName, src, root)
+	return computed, err
+}
+
+// EnvComputed returns the paths of a component's environment overrides which
+// are computed. Paths are relative to the component. If key is blank, paths
+// for all components are returned prefixed by component.
+func EnvComputed(key, envData string) (ComputedParams, error) {
+	_, components, err := parseEnv(envData)
+	if err != nil {
+		return nil, err
+	}
+
+	if components == nil {
+		return nil, nil
+	}
+
+	r := newValueReader(envData)
+	if key == "" {
+		if _, err := r.objectValues(components, []string{envComponentsRoot}); err != nil {
+			return nil, err
+		}
+
+		return r.relative([]string{envComponentsRoot}), nil
+	}
+
+	if _, err := r.overrides(components, key); err != nil {
+		return nil, err
+	}
+
+	return r.relative([]string{envComponentsRoot, key}), nil
+}
+
+// valueReader converts params objects to maps. Fields which aren't literals
+// are resolved by evaluating the params source.
+type valueReader struct {
+	eval     *evaluator
+	computed ComputedParams
+}
+
+func newValueReader(src string) *valueReader {
+	return &valueReader{eval: &evaluator{src: src}}
+}
+
+// objectValues converts obj to a map. path is the location of obj in the
+// params source.
+func (r *valueReader) objectValues(obj *astext.Object, path []string) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+
+	for i := range obj.Fields {
+		if !isValueField(obj.Fields[i]) {
+			continue
+		}
+
+		id, err := jsonnetutil.FieldID(obj.Fields[i])
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := r.value(obj.Fields[i].Expr2, appendPath(path, id))
+		if err != nil {
+			return nil, err
+		}
+		m[id] = v
+	}
+
+	return m, nil
+}
+
+// arrayValues converts array to a slice. path is the location of array in the
+// params source.
+func (r *valueReader) arrayValues(array *ast.Array, path []string) ([]interface{}, error) {
+	out := make([]interface{}, 0)
+	for i := range array.Elements {
+		v, err := r.value(array.Elements[i], appendPath(path, indexElement(i)))
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, v)
+	}
+
+	return out, nil
+}
+
+// value converts node to a Go value. path is the location of node in the
+// params source.
+func (r *valueReader) value(node ast.Node, path []string) (interface{}, error) {
+	switch t := node.(type) {
+	case *ast.LiteralString, *ast.LiteralBoolean, *ast.LiteralNumber, *ast.LiteralNull:
+		return nodeValue(t)
+	case *astext.Object:
+		return r.objectValues(t, path)
+	case *ast.Array:
+		return r.arrayValues(t, path)
+	default:
+		return r.computedValue(path, t)
+	}
+}
+
+func (r *valueReader) computedValue(path []string, node ast.Node) (interface{}, error) {
+	if r.eval == nil {
+		return nil, errors.Errorf("unknown value type %T", node)
+	}
+
+	v, err := r.eval.value(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.computed = append(r.computed, path)
+	return v, nil
+}
+
+// overrides returns the environment overrides for a component.
+func (r *valueReader) overrides(components *astext.Object, key string) (map[string]interface{}, error) {
+	field, err := findField(components, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if field == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	obj, ok := field.Expr2.(*astext.Object)
+	if !ok {
+		return nil, errors.Errorf("overrides for %q are a %T; expected an object", key, field.Expr2)
+	}
+
+	return r.objectValues(obj, []string{envComponentsRoot, key})
+}
+
+// relative returns the computed paths found under prefix with prefix removed.
+func (r *valueReader) relative(prefix []string) ComputedParams {
+	var out ComputedParams
+	for _, p := range r.computed {
+		if hasPathPrefix(p, prefix) {
+			out = append(out, p[len(prefix):])
+		}
+	}
+
+	return out
+}
+
+// evaluator evaluates params source with the Jsonnet VM. The source is only
+// evaluated the first time a value is needed.
+type evaluator struct {
+	src string
+
+	evaluated bool
+	doc       interface{}
+	err       error
+}
+
+// value returns the evaluated value at path.
+func (ev *evaluator) value(path []string) (interface{}, error) {
+	if !ev.evaluated {
+		ev.doc, ev.err = evaluateParams(ev.src)
+		ev.evaluated = true
+	}
+
+	if ev.err != nil {
+		return nil, ev.err
+	}
+
+	v, ok := LookupValue(ev.doc, path)
+	if !ok {
+		return nil, errors.Errorf("unable to evaluate %s", schemaPath(path))
+	}
+
+	return v, nil
+}
+
+// evaluateParams evaluates params source. Environment params are evaluated
+// over empty namespace params, so only their overrides are present.
+func evaluateParams(src string) (interface{}, error) {
+	vm := jsonnet.MakeVM()
+	vm.ExtCode(envParamsExtVar, `{ components: {}, global: {} }`)
+
+	out, err := vm.EvaluateSnippet("params.libsonnet", UpgradeImports(src))
+	if err != nil {
+		return nil, errors.Wrap(err, "evaluate params")
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		return nil, errors.Wrap(err, "decode evaluated params")
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,196 @@
+package params
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToMap_computed(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/computed-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := ToMap("guestbook-ui", string(b), "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"replicas": float64(4),
+		"image":    "gcr.io/heptio-images/ks-guestbook-demo:0.1",
+		"ports":    []interface{}{float64(80), float64(80)},
+		"labels": map[string]interface{}{
+			"app":  "guestbook",
+			"tier": "web",
+		},
+	}
+
+	require.Equal(t, expected, got)
+}
+
+func TestComputed(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/computed-params.libsonnet")
+	require.NoError(t, err)
+
+	computed, err := Computed("guestbook-ui", string(b), "components")
+	require.NoError(t, err)
+
+	require.True(t, computed.Has([]string{"replicas"}))
+	require.True(t, computed.Has([]string{"ports"}))
+	require.True(t, computed.Has([]string{"ports", "[1]"}))
+	require.False(t, computed.Has([]string{"ports", "[0]"}))
+	require.True(t, computed.Has([]string{"labels", "app"}))
+	require.False(t, computed.Has([]string{"image"}))
+}
+
+func TestSet_computed(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/computed-params.libsonnet")
+	require.NoError(t, err)
+	src := string(b)
+
+	cases := []struct {
+		name     string
+		path     []string
+		value    interface{}
+		opts     []SetOpt
+		expected string
+		isErr    bool
+	}{
+		{
+			name:  "computed param",
+			path:  []string{"replicas"},
+			value: 3,
+			isErr: true,
+		},
+		{
+			name:  "inside computed param",
+			path:  []string{"labels", "tier"},
+			value: "db",
+			isErr: true,
+		},
+		{
+			name:     "forced",
+			path:     []string{"replicas"},
+			value:    3,
+			opts:     []SetOpt{WithForce(true)},
+			expected: strings.Replace(src, "replicas: 2 * base,", "replicas: 3,", 1),
+		},
+		{
+			name:     "literal param",
+			path:     []string{"image"},
+			value:    "nginx",
+			expected: strings.Replace(src, `"gcr.io/heptio-images/ks-guestbook-demo:0.1"`, `"nginx"`, 1),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Set(tc.path, src, "guestbook-ui", tc.value, "components", tc.opts...)
+			if tc.isErr {
+				require.Error(t, err)
+				_, ok := err.(*ComputedError)
+				require.True(t, ok)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestSet_import(t *testing.T) {
+	src := `local images = import "images.libsonnet";
+
+{
+  global: {
+  },
+  components: {
+    web: {
+      image: images.web,
+      port: 80,
+    },
+  },
+}
+`
+
+	// Imports can't be evaluated, so the params can't be read. They must not be
+	// replaced.
+	got, err := Set([]string{"replicas"}, src, "web", 3, "components")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "evaluate params")
+	require.Empty(t, got)
+}
+
+func TestSet_missing(t *testing.T) {
+	src := `{
+  global: {
+  },
+  components: {
+    web: {
+      port: 80,
+    },
+  },
+}
+`
+
+	got, err := Set([]string{"replicas"}, src, "db", 3, "components")
+	require.NoError(t, err)
+
+	m, err := ToMap("db", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"replicas": float64(3)}, m)
+
+	m, err = ToMap("web", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"port": float64(80)}, m)
+}
+
+func TestEnvComputed(t *testing.T) {
+	src := `local params = std.extVar("__ksonnet/params");
+local base = 3;
+
+params + {
+  components +: {
+    "guestbook-ui" +: {
+      replicas: base + 1,
+    },
+  },
+}
+`
+
+	got, err := EnvToMap("guestbook-ui", src)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"replicas": float64(4)}, got)
+
+	computed, err := EnvComputed("guestbook-ui", src)
+	require.NoError(t, err)
+	require.True(t, computed.Has([]string{"replicas"}))
+
+	_, err = SetEnv([]string{"replicas"}, src, "guestbook-ui", 2)
+	require.Error(t, err)
+}
+
+func TestEnvComputed_import(t *testing.T) {
+	src := `local params = import "../../components/params.libsonnet";
+local base = 3;
+
+params + {
+  components +: {
+    "guestbook-ui" +: {
+      image: "nginx",
+      replicas: base + 1,
+    },
+  },
+}
+`
+
+	got, err := EnvToMap("guestbook-ui", src)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"image": "nginx", "replicas": float64(4)}, got)
+
+	updated, err := SetEnv([]string{"image"}, src, "guestbook-ui", "redis")
+	require.NoError(t, err)
+	require.Contains(t, updated, `import "../../components/params.libsonnet"`)
+	require.Contains(t, updated, `image: "redis"`)
+}
@@ -0,0 +1,740 @@
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+var (
+	reIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	jsonnetKeywords = map[string]bool{
+		"assert": true, "else": true, "error": true, "false": true, "for": true,
+		"function": true, "if": true, "import": true, "importstr": true, "in": true,
+		"local": true, "null": true, "tailstrict": true, "then": true, "self": true,
+		"super": true, "true": true,
+	}
+)
+
+// editor makes surgical changes to Jsonnet source. Edits are recorded against
+// byte offsets in the original source and applied together, so anything which
+// isn't touched keeps its comments, ordering and formatting.
+type editor struct {
+	src   string
+	lines []int
+	edits []textEdit
+	eval  *evaluator
+}
+
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// newField is a field which will be added to an object.
+type newField struct {
+	key   string
+	value interface{}
+	super bool
+}
+
+// superObject is an object whose fields are rendered as `key +: value`.
+type superObject map[string]interface{}
+
+// compactValue is a value which is rendered on a single line.
+type compactValue struct {
+	value interface{}
+}
+
+func newEditor(src string) *editor {
+	lines := []int{0}
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+
+	return &editor{src: src, lines: lines, eval: &evaluator{src: src}}
+}
+
+// String applies the edits and returns the updated source.
+func (e *editor) String() (string, error) {
+	edits := make([]textEdit, len(e.edits))
+	copy(edits, e.edits)
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+
+	var buf bytes.Buffer
+	pos := 0
+	for _, edit := range edits {
+		if edit.start < pos {
+			// Overlapping deletions remove the union of their text.
+			if edit.text != "" || edit.start == edit.end {
+				return "", errors.New("params edits overlap")
+			}
+
+			if edit.end > pos {
+				pos = edit.end
+			}
+			continue
+		}
+
+		buf.WriteString(e.src[pos:edit.start])
+		buf.WriteString(edit.text)
+		pos = edit.end
+	}
+	buf.WriteString(e.src[pos:])
+
+	return buf.String(), nil
+}
+
+func (e *editor) replace(start, end int, text string) {
+	e.edits = append(e.edits, textEdit{start: start, end: end, text: text})
+}
+
+func (e *editor) offset(loc ast.Location) int {
+	if loc.Line < 1 || loc.Line > len(e.lines) {
+		return len(e.src)
+	}
+
+	return e.lines[loc.Line-1] + loc.Column - 1
+}
+
+func (e *editor) lineStart(off int) int {
+	return strings.LastIndex(e.src[:off], "\n") + 1
+}
+
+// lineEnd returns the offset just past the newline ending the line off is on.
+func (e *editor) lineEnd(off int) int {
+	i := strings.Index(e.src[off:], "\n")
+	if i == -1 {
+		return len(e.src)
+	}
+
+	return off + i + 1
+}
+
+// indentAt returns the leading whitespace of the line off is on.
+func (e *editor) indentAt(off int) string {
+	start := e.lineStart(off)
+	end := start
+	for end < len(e.src) && (e.src[end] == ' ' || e.src[end] == '\t') {
+		end++
+	}
+
+	return e.src[start:end]
+}
+
+// fieldStart finds the offset where a field's key begins.
+func (e *editor) fieldStart(field astext.ObjectField) int {
+	if field.Expr1 != nil {
+		return e.offset(field.Expr1.Loc().Begin)
+	}
+
+	i := e.offset(field.Expr2.Loc().Begin)
+	i = e.skipSpaceBack(i)
+	for i > 0 && strings.IndexByte(":+", e.src[i-1]) != -1 {
+		i--
+	}
+	i = e.skipSpaceBack(i)
+	for i > 0 && isIdentByte(e.src[i-1]) {
+		i--
+	}
+
+	return i
+}
+
+func (e *editor) skipSpaceBack(i int) int {
+	for i > 0 && strings.IndexByte(" \t\r\n", e.src[i-1]) != -1 {
+		i--
+	}
+
+	return i
+}
+
+// afterComma returns the offset after a field's trailing comma and whether
+// the field has one.
+func (e *editor) afterComma(end int) (int, bool) {
+	i := end
+	for i < len(e.src) && strings.IndexByte(" \t\r\n", e.src[i]) != -1 {
+		i++
+	}
+
+	if i < len(e.src) && e.src[i] == ',' {
+		return i + 1, true
+	}
+
+	return end, false
+}
+
+func (e *editor) blank(start, end int) bool {
+	return strings.TrimSpace(e.src[start:end]) == ""
+}
+
+// setPath updates the object at path so its values match props. Objects
+// missing along the path are created. base is the location of obj in the
+// source.
+func (e *editor) setPath(obj *astext.Object, base, path []string, props map[string]interface{}, super bool) error {
+	cur := obj
+	for i, k := range path {
+		field, err := findField(cur, k)
+		if err != nil {
+			return err
+		}
+
+		if field == nil {
+			var v interface{} = props
+			for j := len(path) - 1; j > i; j-- {
+				v = map[string]interface{}{path[j]: v}
+			}
+
+			return e.insertFields(cur, valueFields(cur), []newField{{key: k, value: v, super: super}})
+		}
+
+		child, ok := field.Expr2.(*astext.Object)
+		if !ok {
+			return errors.Errorf("child is not an object at %q", k)
+		}
+
+		cur = child
+	}
+
+	return e.updateObject(cur, appendPath(base, path...), props)
+}
+
+// updateObject changes, adds and removes fields so obj matches props. path is
+// the location of obj in the source.
+func (e *editor) updateObject(obj *astext.Object, path []string, props map[string]interface{}) error {
+	seen := make(map[string]bool)
+	var kept []astext.ObjectField
+
+	for _, field := range obj.Fields {
+		if !isValueField(field) {
+			continue
+		}
+
+		id, err := jsonnetutil.FieldID(field)
+		if err != nil {
+			return err
+		}
+
+		v, ok := props[id]
+		if !ok {
+			e.deleteField(field)
+			continue
+		}
+
+		seen[id] = true
+		kept = append(kept, field)
+
+		if err := e.updateField(field, appendPath(path, id), v); err != nil {
+			return err
+		}
+	}
+
+	var fields []newField
+	for k := range props {
+		if !seen[k] {
+			fields = append(fields, newField{key: k, value: props[k]})
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].key < fields[j].key
+	})
+
+	return e.insertFields(obj, kept, fields)
+}
+
+// updateField replaces a field's value if it has changed.
+func (e *editor) updateField(field astext.ObjectField, path []string, v interface{}) error {
+	return e.updateNode(field.Expr2, e.indentAt(e.fieldStart(field)), path, v)
+}
+
+// updateNode replaces a value if it has changed. Nested objects are updated
+// field by field, and arrays which keep their length are updated element by
+// element. Expressions are compared using their evaluated value, so they are
+// kept unless the value changes. indent is the indentation of the line the
+// value starts on.
+func (e *editor) updateNode(node ast.Node, indent string, path []string, v interface{}) error {
+	switch t := node.(type) {
+	case *astext.Object:
+		if m, ok := v.(map[string]interface{}); ok {
+			return e.updateObject(t, path, m)
+		}
+	case *ast.Array:
+		if a, ok := v.([]interface{}); ok && len(t.Elements) > 0 && len(a) >= len(t.Elements) {
+			for i, elem := range t.Elements {
+				elemIndent := e.indentAt(e.offset(elem.Loc().Begin))
+				if err := e.updateNode(elem, elemIndent, appendPath(path, indexElement(i)), a[i]); err != nil {
+					return err
+				}
+			}
+
+			return e.appendElements(t, a[len(t.Elements):])
+		}
+	}
+
+	cur, err := nodeToValue(node)
+	if err != nil {
+		if cur, err = e.eval.value(path); err != nil {
+			return err
+		}
+	}
+
+	if valuesEqual(cur, v) {
+		return nil
+	}
+
+	loc := node.Loc()
+	start, end := e.offset(loc.Begin), e.offset(loc.End)
+
+	// Values which replace a single line value stay on one line.
+	rendered := v
+	if !strings.Contains(e.src[start:end], "\n") {
+		rendered = compactValue{v}
+	}
+
+	text, err := renderValue(rendered, indent)
+	if err != nil {
+		return err
+	}
+
+	if ls, ok := node.(*ast.LiteralString); ok && ls.Kind == ast.StringSingle {
+		if s, ok := v.(string); ok {
+			text = singleQuote(s)
+		}
+	}
+
+	e.replace(start, end, text)
+
+	return nil
+}
+
+// appendElements adds values to the end of an array which has elements.
+func (e *editor) appendElements(array *ast.Array, values []interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	last := array.Elements[len(array.Elements)-1]
+	end := e.offset(last.Loc().End)
+	closing := e.offset(array.Loc().End) - 1
+	if closing < 0 || closing >= len(e.src) || e.src[closing] != ']' {
+		return errors.New("unable to locate array in params")
+	}
+
+	braceStart := e.lineStart(closing)
+	if !e.blank(braceStart, closing) {
+		var items []string
+		for _, v := range values {
+			s, err := renderCompact(v)
+			if err != nil {
+				return err
+			}
+			items = append(items, s)
+		}
+
+		if after, ok := e.afterComma(end); ok {
+			e.replace(after, after, " "+strings.Join(items, ", ")+",")
+		} else {
+			e.replace(end, end, ", "+strings.Join(items, ", "))
+		}
+
+		return nil
+	}
+
+	if _, ok := e.afterComma(end); !ok {
+		e.replace(end, end, ",")
+	}
+
+	indent := e.indentAt(e.offset(last.Loc().Begin))
+
+	var buf bytes.Buffer
+	for _, v := range values {
+		s, err := renderValue(v, indent)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(indent + s + ",\n")
+	}
+	e.replace(braceStart, braceStart, buf.String())
+
+	return nil
+}
+
+// deleteField removes a field. If the field is on lines of its own, the lines
+// are removed as well.
+func (e *editor) deleteField(field astext.ObjectField) {
+	start := e.fieldStart(field)
+	end, _ := e.afterComma(e.offset(field.Expr2.Loc().End))
+
+	ls := e.lineStart(start)
+	le := e.lineEnd(end)
+	if e.blank(ls, start) && e.blank(end, le) {
+		e.replace(ls, le, "")
+		return
+	}
+
+	end, hasComma := e.afterComma(e.offset(field.Expr2.Loc().End))
+	if !hasComma {
+		// The last field on a line takes the comma before it with it.
+		if i := e.skipSpaceBack(start); i > 0 && e.src[i-1] == ',' {
+			e.replace(i-1, end, "")
+			return
+		}
+	}
+
+	for end < len(e.src) && (e.src[end] == ' ' || e.src[end] == '\t') {
+		end++
+	}
+	e.replace(start, end, "")
+}
+
+// insertFields adds fields to the end of an object. kept are the object's
+// value fields which remain after any deletions.
+func (e *editor) insertFields(obj *astext.Object, kept []astext.ObjectField, fields []newField) error {
+	open := e.offset(obj.Loc().Begin)
+	closing := e.offset(obj.Loc().End) - 1
+	if closing < 0 || closing >= len(e.src) || e.src[closing] != '}' || e.src[open] != '{' {
+		return errors.New("unable to locate object in params")
+	}
+
+	braceStart := e.lineStart(closing)
+	ownLine := e.blank(braceStart, closing) && strings.Contains(e.src[open:closing], "\n")
+
+	indent := e.indentAt(open) + "  "
+	if ownLine {
+		indent = e.src[braceStart:closing] + "  "
+	}
+	if len(kept) > 0 {
+		indent = e.indentAt(e.fieldStart(kept[len(kept)-1]))
+	}
+
+	var last *astext.ObjectField
+	if len(kept) > 0 {
+		last = &kept[len(kept)-1]
+	}
+
+	// Fields added to a single line object stay on that line.
+	inline := !ownLine && last != nil
+
+	var rendered []string
+	for _, f := range fields {
+		field := f
+		if inline {
+			field.value = compactValue{f.value}
+		}
+
+		s, err := renderField(field.key, field.value, indent, field.super)
+		if err != nil {
+			return err
+		}
+
+		rendered = append(rendered, s)
+	}
+
+	switch {
+	case ownLine:
+		if last != nil {
+			end := e.offset(last.Expr2.Loc().End)
+			if _, ok := e.afterComma(end); !ok {
+				e.replace(end, end, ",")
+			}
+		}
+
+		var buf bytes.Buffer
+		for _, s := range rendered {
+			buf.WriteString(indent + s + ",\n")
+		}
+		e.replace(braceStart, braceStart, buf.String())
+	case last == nil:
+		braceIndent := e.indentAt(open)
+		var buf bytes.Buffer
+		buf.WriteString("\n")
+		for _, s := range rendered {
+			buf.WriteString(indent + s + ",\n")
+		}
+		buf.WriteString(braceIndent)
+		e.replace(open+1, closing, buf.String())
+	default:
+		end := e.offset(last.Expr2.Loc().End)
+		after, ok := e.afterComma(end)
+		if ok {
+			e.replace(after, after, " "+strings.Join(rendered, ", ")+",")
+		} else {
+			e.replace(end, end, ", "+strings.Join(rendered, ", "))
+		}
+	}
+
+	return nil
+}
+
+// findField finds the field with id in obj. It returns nil if there isn't one.
+func findField(obj *astext.Object, id string) (*astext.ObjectField, error) {
+	for i := range obj.Fields {
+		if !isValueField(obj.Fields[i]) {
+			continue
+		}
+
+		fieldID, err := jsonnetutil.FieldID(obj.Fields[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if fieldID == id {
+			return &obj.Fields[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// valueFields returns the fields in obj which hold values.
+func valueFields(obj *astext.Object) []astext.ObjectField {
+	var fields []astext.ObjectField
+	for _, field := range obj.Fields {
+		if isValueField(field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+func isValueField(field astext.ObjectField) bool {
+	return field.Kind != ast.ObjectLocal && field.Kind != ast.ObjectAssert && field.Method == nil
+}
+
+func appendPath(path []string, keys ...string) []string {
+	return append(path[:len(path):len(path)], keys...)
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// nodeToValue converts a node containing literal values to a Go value.
+func nodeToValue(node ast.Node) (interface{}, error) {
+	r := &valueReader{}
+	return r.value(node, nil)
+}
+
+func valuesEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeValue(a), normalizeValue(b))
+}
+
+// normalizeValue converts numbers to float64 so values decoded from source
+// and values supplied by callers compare equal.
+func normalizeValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case map[string]interface{}:
+		m := make(map[string]interface{})
+		for k := range t {
+			m[k] = normalizeValue(t[k])
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i := range t {
+			a[i] = normalizeValue(t[i])
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+func renderField(key string, v interface{}, indent string, super bool) (string, error) {
+	value, err := renderValue(v, indent)
+	if err != nil {
+		return "", err
+	}
+
+	sep := ": "
+	if super {
+		sep = " +: "
+	}
+
+	return renderKey(key) + sep + value, nil
+}
+
+// IsIdentifier reports whether a key can be used as a Jsonnet identifier, so
+// it doesn't need to be quoted.
+func IsIdentifier(key string) bool {
+	return reIdentifier.MatchString(key) && !jsonnetKeywords[key]
+}
+
+func renderKey(key string) string {
+	if IsIdentifier(key) {
+		return key
+	}
+
+	return doubleQuote(key)
+}
+
+// renderValue renders a value as Jsonnet. indent is the indentation of the
+// line the value starts on.
+func renderValue(v interface{}, indent string) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return doubleQuote(t), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case int:
+		return strconv.Itoa(t), nil
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case map[string]interface{}:
+		return renderObject(t, indent, false)
+	case superObject:
+		return renderObject(t, indent, true)
+	case []interface{}:
+		return renderArray(t, indent)
+	case compactValue:
+		return renderCompact(t.value)
+	default:
+		return "", errors.Errorf("unable to convert %T to jsonnet", v)
+	}
+}
+
+// renderCompact renders a value as Jsonnet on a single line.
+func renderCompact(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var fields []string
+		for _, k := range keys {
+			s, err := renderCompact(t[k])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, renderKey(k)+": "+s)
+		}
+
+		return "{" + strings.Join(fields, ", ") + "}", nil
+	case []interface{}:
+		var items []string
+		for _, item := range t {
+			s, err := renderCompact(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]", nil
+	default:
+		return renderValue(v, "")
+	}
+}
+
+func renderObject(m map[string]interface{}, indent string, super bool) (string, error) {
+	if len(m) == 0 {
+		return "{}", nil
+	}
+
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for _, k := range keys {
+		s, err := renderField(k, m[k], indent+"  ", super)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(indent + "  " + s + ",\n")
+	}
+	buf.WriteString(indent + "}")
+
+	return buf.String(), nil
+}
+
+func renderArray(a []interface{}, indent string) (string, error) {
+	inline := true
+	for _, v := range a {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			inline = false
+		}
+	}
+
+	var items []string
+	for _, v := range a {
+		childIndent := indent
+		if !inline {
+			childIndent = indent + "  "
+		}
+
+		s, err := renderValue(v, childIndent)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, s)
+	}
+
+	if inline {
+		return "[" + strings.Join(items, ", ") + "]", nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("[\n")
+	for _, s := range items {
+		buf.WriteString(indent + "  " + s + ",\n")
+	}
+	buf.WriteString(indent + "]")
+
+	return buf.String(), nil
+}
+
+func doubleQuote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return strconv.Quote(s)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func singleQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `'`, `\'`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return "'" + s + "'"
+}
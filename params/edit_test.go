@@ -0,0 +1,235 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const editSource = `local image = "nginx";
+
+{
+  global: {},
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'web', // keep me
+      replicas: 1,
+      ports: [80],
+    },
+    other: {a: 1},
+  },
+}
+`
+
+func TestUpdate_surgical(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     []string
+		params   map[string]interface{}
+		expected string
+	}{
+		{
+			name: "change a value",
+			path: []string{"components", "web"},
+			params: map[string]interface{}{
+				"image":    "nginx",
+				"name":     "web",
+				"replicas": 3,
+				"ports":    []interface{}{80},
+			},
+			expected: `local image = "nginx";
+
+{
+  global: {},
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'web', // keep me
+      replicas: 3,
+      ports: [80],
+    },
+    other: {a: 1},
+  },
+}
+`,
+		},
+		{
+			name: "keep quoting style",
+			path: []string{"components", "web"},
+			params: map[string]interface{}{
+				"image":    "nginx",
+				"name":     "frontend",
+				"replicas": 1,
+				"ports":    []interface{}{80},
+			},
+			expected: `local image = "nginx";
+
+{
+  global: {},
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'frontend', // keep me
+      replicas: 1,
+      ports: [80],
+    },
+    other: {a: 1},
+  },
+}
+`,
+		},
+		{
+			name: "remove and add values",
+			path: []string{"components", "web"},
+			params: map[string]interface{}{
+				"image":    "nginx",
+				"name":     "web",
+				"replicas": 1,
+				"labels": map[string]interface{}{
+					"app": "web",
+				},
+			},
+			expected: `local image = "nginx";
+
+{
+  global: {},
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'web', // keep me
+      replicas: 1,
+      labels: {
+        app: "web",
+      },
+    },
+    other: {a: 1},
+  },
+}
+`,
+		},
+		{
+			name: "add to single line object",
+			path: []string{"components", "other"},
+			params: map[string]interface{}{
+				"a": 1,
+				"b": map[string]interface{}{"c": "d"},
+			},
+			expected: `local image = "nginx";
+
+{
+  global: {},
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'web', // keep me
+      replicas: 1,
+      ports: [80],
+    },
+    other: {a: 1, b: {c: "d"}},
+  },
+}
+`,
+		},
+		{
+			name:   "remove from single line object",
+			path:   []string{"components", "other"},
+			params: map[string]interface{}{},
+			expected: `local image = "nginx";
+
+{
+  global: {},
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'web', // keep me
+      replicas: 1,
+      ports: [80],
+    },
+    other: {},
+  },
+}
+`,
+		},
+		{
+			name:   "add to empty object",
+			path:   []string{"global"},
+			params: map[string]interface{}{"env": "prod"},
+			expected: `local image = "nginx";
+
+{
+  global: {
+    env: "prod",
+  },
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'web', // keep me
+      replicas: 1,
+      ports: [80],
+    },
+    other: {a: 1},
+  },
+}
+`,
+		},
+		{
+			name:   "add a component",
+			path:   []string{"components", "new-app"},
+			params: map[string]interface{}{"replicas": 2},
+			expected: `local image = "nginx";
+
+{
+  global: {},
+  components: {
+    // the web app
+    web: {
+      // image to run
+      image: "nginx",
+      name: 'web', // keep me
+      replicas: 1,
+      ports: [80],
+    },
+    other: {a: 1},
+    "new-app": {
+      replicas: 2,
+    },
+  },
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Update(tc.path, editSource, tc.params)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestIsIdentifier(t *testing.T) {
+	for _, key := range []string{"replicas", "_name", "port8080"} {
+		require.True(t, IsIdentifier(key), key)
+	}
+
+	for _, key := range []string{"", "guestbook-ui", "8080", "app.kubernetes.io/name", "local", "self"} {
+		require.False(t, IsIdentifier(key), key)
+	}
+}
@@ -0,0 +1,217 @@
+package params
+
+import (
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/ksonnet/ksonnet/pkg/docparser"
+	"github.com/pkg/errors"
+)
+
+const (
+	// envComponentsRoot is the field environment overrides are stored under.
+	envComponentsRoot = "components"
+)
+
+// SetEnv sets a component param override in an environment params file.
+func SetEnv(path []string, envData, key string, value interface{}, opts ...SetOpt) (string, error) {
+	env, components, err := parseEnv(envData)
+	if err != nil {
+		return "", err
+	}
+
+	var options setOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	props := make(map[string]interface{})
+	r := newValueReader(envData)
+	if components != nil {
+		if props, err = r.overrides(components, key); err != nil {
+			return "", err
+		}
+	}
+
+	computed := r.relative([]string{envComponentsRoot, key})
+	if !options.force && computed.Has(path) {
+		return "", &ComputedError{Path: path}
+	}
+
+	if err = setValue(props, path, value); err != nil {
+		return "", err
+	}
+
+	return updateEnv(envData, env, components, key, props)
+}
+
+// UpdateEnv replaces a component's overrides in an environment params file.
+func UpdateEnv(envData, key string, props map[string]interface{}) (string, error) {
+	env, components, err := parseEnv(envData)
+	if err != nil {
+		return "", err
+	}
+
+	return updateEnv(envData, env, components, key, props)
+}
+
+func updateEnv(envData string, env, components *astext.Object, key string, props map[string]interface{}) (string, error) {
+	var err error
+	e := newEditor(envData)
+	if components == nil {
+		field := newField{key: envComponentsRoot, value: superObject{key: props}, super: true}
+		err = e.insertFields(env, valueFields(env), []newField{field})
+	} else {
+		err = e.setPath(components, []string{envComponentsRoot}, []string{key}, props, true)
+	}
+
+	if err != nil {
+		return "", errors.Wrap(err, "update environment params")
+	}
+
+	return e.String()
+}
+
+// RemoveEnv removes all of a component's overrides from an environment params
+// file. It is not an error if the component has no overrides.
+func RemoveEnv(envData, key string) (string, error) {
+	_, components, err := parseEnv(envData)
+	if err != nil {
+		return "", err
+	}
+
+	if components == nil {
+		return envData, nil
+	}
+
+	field, err := findField(components, key)
+	if err != nil || field == nil {
+		return envData, err
+	}
+
+	e := newEditor(envData)
+	e.deleteField(*field)
+	return e.String()
+}
+
+// DeleteEnv deletes a component param override from an environment params
+// file. The component's entry is removed once it has no overrides left.
+func DeleteEnv(path []string, envData, key string) (string, error) {
+	_, components, err := parseEnv(envData)
+	if err != nil {
+		return "", err
+	}
+
+	if components == nil {
+		return "", errors.Errorf("environment does not override %q", key)
+	}
+
+	props, err := newValueReader(envData).overrides(components, key)
+	if err != nil {
+		return "", err
+	}
+
+	if err = deleteValue(props, path); err != nil {
+		return "", err
+	}
+
+	e := newEditor(envData)
+	if len(props) == 0 {
+		field, err := findField(components, key)
+		if err != nil {
+			return "", err
+		}
+		e.deleteField(*field)
+	} else if err := e.setPath(components, []string{envComponentsRoot}, []string{key}, props, true); err != nil {
+		return "", errors.Wrap(err, "update environment params")
+	}
+
+	return e.String()
+}
+
+// EnvToMap converts the overrides an environment has for a component to a map.
+// If key is blank, overrides for all components are returned keyed by
+// component.
+func EnvToMap(key, envData string) (map[string]interface{}, error) {
+	_, components, err := parseEnv(envData)
+	if err != nil {
+		return nil, err
+	}
+
+	if components == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	r := newValueReader(envData)
+	if key != "" {
+		return r.overrides(components, key)
+	}
+
+	return r.objectValues(components, []string{envComponentsRoot})
+}
+
+// parseEnv parses environment params and locates the object which holds
+// component overrides. Environment params are the namespace params with an
+// object merged over them, e.g. `params + { components +: { ... } }`. The
+// returned components object is nil if the environment has no overrides.
+func parseEnv(envData string) (*astext.Object, *astext.Object, error) {
+	tokens, err := docparser.Lex("params.libsonnet", envData)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "lex environment params")
+	}
+
+	node, err := docparser.Parse(tokens)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parse environment params")
+	}
+
+	env := findEnvObject(node)
+	if env == nil {
+		return nil, nil, errors.New("unable to find environment overrides in params")
+	}
+
+	field, err := findField(env, envComponentsRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if field == nil {
+		return env, nil, nil
+	}
+
+	components, ok := field.Expr2.(*astext.Object)
+	if !ok {
+		return nil, nil, errors.Errorf("environment %s is a %T; expected an object",
+			envComponentsRoot, field.Expr2)
+	}
+
+	return env, components, nil
+}
+
+// findEnvObject finds the first object literal that is merged over another
+// value. Locals are searched before the body they are bound in.
+func findEnvObject(node ast.Node) *astext.Object {
+	switch t := node.(type) {
+	case *ast.Local:
+		for _, bind := range t.Binds {
+			if obj := findEnvObject(bind.Body); obj != nil {
+				return obj
+			}
+		}
+		return findEnvObject(t.Body)
+	case *ast.Binary:
+		if t.Op != ast.BopPlus {
+			return nil
+		}
+		if obj, ok := t.Right.(*astext.Object); ok {
+			return obj
+		}
+		if obj := findEnvObject(t.Right); obj != nil {
+			return obj
+		}
+		return findEnvObject(t.Left)
+	case *astext.Object:
+		return t
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package params
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEnv(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := SetEnv([]string{"image"}, string(b), "guestbook-ui", "gcr.io/heptio-images/ks-guestbook-demo:0.2")
+	require.NoError(t, err)
+
+	got, err = SetEnv([]string{"replicas"}, got, "redis", 2)
+	require.NoError(t, err)
+
+	expected, err := ioutil.ReadFile("testdata/env-params-set.libsonnet")
+	require.NoError(t, err)
+
+	require.Equal(t, string(expected), got)
+}
+
+func TestSetEnv_no_components(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params-empty.libsonnet")
+	require.NoError(t, err)
+
+	got, err := SetEnv([]string{"replicas"}, string(b), "redis", 2)
+	require.NoError(t, err)
+
+	m, err := EnvToMap("redis", got)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{"replicas": float64(2)}, m)
+}
+
+func TestDeleteEnv(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params-set.libsonnet")
+	require.NoError(t, err)
+
+	got, err := DeleteEnv([]string{"image"}, string(b), "guestbook-ui")
+	require.NoError(t, err)
+
+	got, err = DeleteEnv([]string{"replicas"}, got, "redis")
+	require.NoError(t, err)
+
+	expected, err := ioutil.ReadFile("testdata/env-params.libsonnet")
+	require.NoError(t, err)
+
+	m, err := EnvToMap("", got)
+	require.NoError(t, err)
+
+	em, err := EnvToMap("", string(expected))
+	require.NoError(t, err)
+
+	require.Equal(t, em, m)
+
+	_, err = DeleteEnv([]string{"missing"}, got, "guestbook-ui")
+	require.Error(t, err)
+}
+
+func TestRemoveEnv(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params-set.libsonnet")
+	require.NoError(t, err)
+
+	got, err := RemoveEnv(string(b), "redis")
+	require.NoError(t, err)
+
+	m, err := EnvToMap("", got)
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	require.Contains(t, m, "guestbook-ui")
+
+	unchanged, err := RemoveEnv(got, "redis")
+	require.NoError(t, err)
+	require.Equal(t, got, unchanged)
+}
+
+func TestUpdateEnv(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params-empty.libsonnet")
+	require.NoError(t, err)
+
+	props := map[string]interface{}{"replicas": 2, "image": "redis"}
+	got, err := UpdateEnv(string(b), "redis", props)
+	require.NoError(t, err)
+
+	got, err = UpdateEnv(got, "redis", map[string]interface{}{"replicas": 3})
+	require.NoError(t, err)
+
+	m, err := EnvToMap("redis", got)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"replicas": float64(3)}, m)
+}
+
+func TestEnvToMap(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params-set.libsonnet")
+	require.NoError(t, err)
+
+	got, err := EnvToMap("", string(b))
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"guestbook-ui": map[string]interface{}{
+			"image":    "gcr.io/heptio-images/ks-guestbook-demo:0.2",
+			"replicas": float64(3),
+		},
+		"redis": map[string]interface{}{
+			"replicas": float64(2),
+		},
+	}
+
+	require.Equal(t, expected, got)
+
+	got, err = EnvToMap("missing", string(b))
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
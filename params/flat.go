@@ -0,0 +1,365 @@
+package params
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FlatFormatCSV is a CSV file with key and value columns.
+	FlatFormatCSV = "csv"
+	// FlatFormatJSON is a JSON object of keys to values.
+	FlatFormatJSON = "json"
+	// FlatFormatDotenv is a file of `key=value` lines.
+	FlatFormatDotenv = "dotenv"
+)
+
+var flatExtensions = map[string]string{
+	".csv":  FlatFormatCSV,
+	".json": FlatFormatJSON,
+	".env":  FlatFormatDotenv,
+}
+
+// FlatFormatFromPath returns the flat format for a file based on its
+// extension.
+func FlatFormatFromPath(path string) (string, error) {
+	format, ok := flatExtensions[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return "", errors.Errorf("unable to determine the format of %s", path)
+	}
+
+	return format, nil
+}
+
+// FormatPath formats a path so it can be parsed with ParsePath. Keys containing
+// dots, brackets or quotes are quoted.
+func FormatPath(path []string) string {
+	var buf strings.Builder
+	for i, k := range path {
+		if _, ok := pathIndex(k); ok {
+			buf.WriteString(k)
+			continue
+		}
+
+		if i > 0 {
+			buf.WriteString(".")
+		}
+		buf.WriteString(formatKey(k))
+	}
+
+	return buf.String()
+}
+
+// Flatten converts params to a map of keys to values. Keys are prefix and the
+// path of the value formatted with FormatPath, e.g.
+// `components.guestbook-ui.ports[0].containerPort`. Empty objects and arrays
+// are kept as values, but m itself is omitted if it is empty.
+func Flatten(prefix []string, m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k := range m {
+		flatten(m[k], appendPath(prefix, k), out)
+	}
+	return out
+}
+
+func flatten(v interface{}, path []string, out map[string]interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			out[FormatPath(path)] = t
+			return
+		}
+
+		for k := range t {
+			flatten(t[k], appendPath(path, k), out)
+		}
+	case []interface{}:
+		if len(t) == 0 {
+			out[FormatPath(path)] = t
+			return
+		}
+
+		for i := range t {
+			flatten(t[i], appendPath(path, indexElement(i)), out)
+		}
+	default:
+		out[FormatPath(path)] = v
+	}
+}
+
+// FlatEqual reports whether two flattened values are equal. Numbers compare
+// equal regardless of their Go type.
+func FlatEqual(a, b interface{}) bool {
+	return valuesEqual(a, b)
+}
+
+// SortedKeys returns flattened keys in path order. Array indexes are sorted
+// numerically.
+func SortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return ComparePaths(splitFlatKey(keys[i]), splitFlatKey(keys[j])) < 0
+	})
+
+	return keys
+}
+
+// ComparePaths orders two paths. Array indexes are compared numerically.
+func ComparePaths(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+
+		ai, aok := pathIndex(a[i])
+		bi, bok := pathIndex(b[i])
+		if aok && bok {
+			if ai < bi {
+				return -1
+			}
+			return 1
+		}
+
+		if a[i] < b[i] {
+			return -1
+		}
+		return 1
+	}
+
+	return len(a) - len(b)
+}
+
+func splitFlatKey(key string) []string {
+	path, err := ParsePath(key)
+	if err != nil {
+		return []string{key}
+	}
+
+	return path
+}
+
+// WriteFlat writes flattened params in a flat format.
+func WriteFlat(w io.Writer, format string, values map[string]interface{}) error {
+	keys := SortedKeys(values)
+
+	switch format {
+	case FlatFormatJSON:
+		m := make(map[string]interface{})
+		for _, k := range keys {
+			m[k] = values[k]
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(m)
+	case FlatFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"key", "value"}); err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			s, err := encodeFlatValue(values[k])
+			if err != nil {
+				return errors.Wrapf(err, "encode %s", k)
+			}
+
+			if err := cw.Write([]string{k, s}); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	case FlatFormatDotenv:
+		for _, k := range keys {
+			s, err := encodeFlatValue(values[k])
+			if err != nil {
+				return errors.Wrapf(err, "encode %s", k)
+			}
+
+			if strings.ContainsAny(s, " \t#'\\") && !strings.HasPrefix(s, `"`) {
+				s = quoteFlat(s)
+			}
+
+			if _, err := fmt.Fprintf(w, "%s=%s\n", k, s); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return errors.Errorf("unsupported format %q", format)
+	}
+}
+
+// ReadFlat reads flattened params in a flat format. Keys are validated with
+// ParsePath.
+func ReadFlat(r io.Reader, format string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	switch format {
+	case FlatFormatJSON:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		var m map[string]interface{}
+		if err := dec.Decode(&m); err != nil {
+			return nil, errors.Wrap(err, "decode json")
+		}
+
+		for k, v := range m {
+			values[k] = normalizeJSONNumbers(v)
+		}
+	case FlatFormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = 2
+		records, err := cr.ReadAll()
+		if err != nil {
+			return nil, errors.Wrap(err, "read csv")
+		}
+
+		for i, record := range records {
+			if i == 0 && record[0] == "key" && record[1] == "value" {
+				continue
+			}
+
+			v, err := decodeFlatValue(record[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", i+1)
+			}
+			values[record[0]] = v
+		}
+	case FlatFormatDotenv:
+		scanner := bufio.NewScanner(r)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+
+			text = strings.TrimPrefix(text, "export ")
+			i := strings.Index(text, "=")
+			if i < 1 {
+				return nil, errors.Errorf("line %d: expected key=value", line)
+			}
+
+			v, err := decodeFlatValue(strings.TrimSpace(text[i+1:]))
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", line)
+			}
+			values[strings.TrimSpace(text[:i])] = v
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported format %q", format)
+	}
+
+	for k := range values {
+		if _, err := ParsePath(k); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// encodeFlatValue encodes a value for a text format. Strings which would be
+// read back as another type are quoted.
+func encodeFlatValue(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		decoded, err := decodeFlatValue(t)
+		if err != nil || !valuesEqual(decoded, t) {
+			return quoteFlat(t), nil
+		}
+		return t, nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// decodeFlatValue decodes a value from a text format. Quoted values are
+// strings and other values are decoded with DecodeValue.
+func decodeFlatValue(s string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		var str string
+		if err := json.Unmarshal([]byte(s), &str); err != nil {
+			return nil, errors.Errorf("string value is badly quoted: %s", s)
+		}
+		return str, nil
+	case s == "null":
+		return nil, nil
+	case s == "":
+		return "", nil
+	}
+
+	v, err := DecodeValue(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeValue(v), nil
+}
+
+func quoteFlat(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+
+	return string(b)
+}
+
+// normalizeJSONNumbers converts numbers decoded with UseNumber to float64.
+func normalizeJSONNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		f, err := t.Float64()
+		if err != nil {
+			return t.String()
+		}
+		return f
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = normalizeJSONNumbers(t[k])
+		}
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeJSONNumbers(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,192 @@
+package params
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	m := map[string]interface{}{
+		"replicas": 1,
+		"ports": []interface{}{
+			map[string]interface{}{"containerPort": 80},
+		},
+		"labels": map[string]interface{}{},
+		"args":   []interface{}{},
+	}
+
+	expected := map[string]interface{}{
+		"components.guestbook-ui.replicas":               1,
+		"components.guestbook-ui.ports[0].containerPort": 80,
+		"components.guestbook-ui.labels":                 map[string]interface{}{},
+		"components.guestbook-ui.args":                   []interface{}{},
+	}
+
+	require.Equal(t, expected, Flatten([]string{"components", "guestbook-ui"}, m))
+	require.Empty(t, Flatten([]string{"global"}, map[string]interface{}{}))
+}
+
+func TestFormatPath(t *testing.T) {
+	path := []string{"components", "guestbook-ui", "matrix", "[1]", "[12]", "name"}
+	s := FormatPath(path)
+	require.Equal(t, "components.guestbook-ui.matrix[1][12].name", s)
+
+	parsed, err := ParsePath(s)
+	require.NoError(t, err)
+	require.Equal(t, path, parsed)
+}
+
+func TestFlatten_quoted_keys(t *testing.T) {
+	m := map[string]interface{}{
+		"labels": map[string]interface{}{
+			"app.kubernetes.io/name": "web",
+			"tier[0]":                "frontend",
+		},
+	}
+
+	flat := Flatten([]string{"components", "web"}, m)
+	require.Equal(t, map[string]interface{}{
+		`components.web.labels."app.kubernetes.io/name"`: "web",
+		`components.web.labels."tier[0]"`:                "frontend",
+	}, flat)
+
+	for key, v := range flat {
+		path, err := ParsePath(key)
+		require.NoError(t, err)
+		require.Equal(t, key, FormatPath(path))
+
+		got, ok := LookupValue(map[string]interface{}{"components": map[string]interface{}{"web": m}}, path)
+		require.True(t, ok)
+		require.Equal(t, v, got)
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	values := map[string]interface{}{
+		"a.ports[10]": 1,
+		"a.ports[2]":  1,
+		"a.name":      1,
+		"a":           1,
+	}
+
+	expected := []string{"a", "a.name", "a.ports[2]", "a.ports[10]"}
+	require.Equal(t, expected, SortedKeys(values))
+}
+
+func TestFlatFormatFromPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected string
+		isErr    bool
+	}{
+		{path: "params.csv", expected: FlatFormatCSV},
+		{path: "params.JSON", expected: FlatFormatJSON},
+		{path: "prod.env", expected: FlatFormatDotenv},
+		{path: "params.txt", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			format, err := FlatFormatFromPath(tc.path)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, format)
+		})
+	}
+}
+
+func TestWriteFlat_roundTrip(t *testing.T) {
+	values := map[string]interface{}{
+		"components.app.replicas":      float64(3),
+		"components.app.ratio":         1.5,
+		"components.app.enabled":       true,
+		"components.app.image":         "nginx:1.13",
+		"components.app.numeric":       "80",
+		"components.app.boolean":       "false",
+		"components.app.empty":         "",
+		"components.app.spaces":        "hello world # not a comment",
+		"components.app.quoted":        `say "hi"`,
+		"components.app.nothing":       nil,
+		"components.app.labels":        map[string]interface{}{},
+		"components.app.ports[0].name": "http",
+		"global.env":                   "prod",
+	}
+
+	formats := []string{FlatFormatCSV, FlatFormatJSON, FlatFormatDotenv}
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteFlat(&buf, format, values))
+
+			got, err := ReadFlat(&buf, format)
+			require.NoError(t, err)
+
+			require.Len(t, got, len(values))
+			for k, v := range values {
+				require.True(t, FlatEqual(v, got[k]), "%s: expected %#v; got %#v", k, v, got[k])
+			}
+		})
+	}
+}
+
+func TestWriteFlat_csv(t *testing.T) {
+	values := map[string]interface{}{
+		"components.app.replicas": 3,
+		"components.app.port":     "80",
+		"components.app.image":    "nginx",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteFlat(&buf, FlatFormatCSV, values))
+
+	expected := "key,value\n" +
+		"components.app.image,nginx\n" +
+		"components.app.port,\"\"\"80\"\"\"\n" +
+		"components.app.replicas,3\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestReadFlat_dotenv(t *testing.T) {
+	src := `# params
+export components.app.replicas=3
+
+components.app.image = nginx
+`
+
+	got, err := ReadFlat(strings.NewReader(src), FlatFormatDotenv)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"components.app.replicas": float64(3),
+		"components.app.image":    "nginx",
+	}
+	require.Equal(t, expected, got)
+}
+
+func TestReadFlat_invalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		format string
+		src    string
+	}{
+		{name: "invalid key", format: FlatFormatJSON, src: `{"a..b": 1}`},
+		{name: "missing value", format: FlatFormatDotenv, src: "components.app.replicas\n"},
+		{name: "bad quote", format: FlatFormatDotenv, src: `a="unterminated` + "\n"},
+		{name: "extra column", format: FlatFormatCSV, src: "key,value\na,1,2\n"},
+		{name: "unknown format", format: "xml", src: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadFlat(strings.NewReader(tc.src), tc.format)
+			require.Error(t, err)
+		})
+	}
+}
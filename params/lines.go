@@ -0,0 +1,65 @@
+package params
+
+import (
+	"strings"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+// Lines returns the line each entry under root starts on in a params file,
+// keyed by entry. Lines are numbered from 1.
+func Lines(src, root string) (map[string]int, error) {
+	obj, err := parseParams(src)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse jsonnet")
+	}
+
+	field, err := findField(obj, root)
+	if err != nil {
+		return nil, err
+	}
+
+	if field == nil {
+		return make(map[string]int), nil
+	}
+
+	child, ok := field.Expr2.(*astext.Object)
+	if !ok {
+		return nil, errors.Errorf("child is not an object at %q", root)
+	}
+
+	return fieldLines(src, child)
+}
+
+// EnvLines returns the line each component's overrides start on in an
+// environment params file, keyed by component.
+func EnvLines(envData string) (map[string]int, error) {
+	_, components, err := parseEnv(envData)
+	if err != nil {
+		return nil, err
+	}
+
+	if components == nil {
+		return make(map[string]int), nil
+	}
+
+	return fieldLines(envData, components)
+}
+
+func fieldLines(src string, obj *astext.Object) (map[string]int, error) {
+	e := newEditor(src)
+
+	lines := make(map[string]int)
+	for _, field := range valueFields(obj) {
+		id, err := jsonnetutil.FieldID(field)
+		if err != nil {
+			return nil, err
+		}
+
+		lines[id] = strings.Count(src[:e.fieldStart(field)], "\n") + 1
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,37 @@
+package params
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLines(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := Lines(string(b), "components")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"guestbook-ui": 9}, got)
+
+	got, err = Lines(string(b), "missing")
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestEnvLines(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params-set.libsonnet")
+	require.NoError(t, err)
+
+	got, err := EnvLines(string(b))
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"guestbook-ui": 5, "redis": 9}, got)
+
+	b, err = ioutil.ReadFile("testdata/env-params-empty.libsonnet")
+	require.NoError(t, err)
+
+	got, err = EnvLines(string(b))
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
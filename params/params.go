@@ -1,7 +1,6 @@
 package params
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -11,39 +10,63 @@ import (
 
 	"github.com/google/go-jsonnet/ast"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
-	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
-	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/ksonnet/ksonnet/pkg/docparser"
 	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
 )
 
-// Set sets a param value.
-func Set(path []string, paramsData, key string, value interface{}, root string) (string, error) {
-	props, err := ToMap(key, paramsData, root)
+// SetOpt is an option for configuring Set.
+type SetOpt func(*setOptions)
+
+type setOptions struct {
+	schema *Schema
+	force  bool
+}
+
+// WithSchema validates the updated params against a schema.
+func WithSchema(schema *Schema) SetOpt {
+	return func(o *setOptions) {
+		o.schema = schema
+	}
+}
+
+// WithForce allows a param which is computed from an expression to be
+// replaced with a literal value.
+func WithForce(force bool) SetOpt {
+	return func(o *setOptions) {
+		o.force = force
+	}
+}
+
+// Set sets a param value. Params which are computed from expressions are not
+// replaced unless forced.
+func Set(path []string, paramsData, key string, value interface{}, root string, opts ...SetOpt) (string, error) {
+	var options setOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	props, computed, err := toMap(key, paramsData, root)
 	if err != nil {
+		if !IsNotFound(err) {
+			return "", err
+		}
 		props = make(map[string]interface{})
 	}
 
-	changes := make(map[string]interface{})
-	cur := changes
+	if !options.force && computed.Has(path) {
+		return "", &ComputedError{Path: path}
+	}
 
-	for i, k := range path {
-		if i == len(path)-1 {
-			cur[k] = value
-		} else {
-			if _, ok := cur[k]; !ok {
-				m := make(map[string]interface{})
-				cur[k] = m
-				cur = m
-			}
-		}
+	if err = setValue(props, path, value); err != nil {
+		return "", err
 	}
 
-	if err = mergeMaps(props, changes, nil); err != nil {
+	if err = options.schema.ValidateAt(updatePath(root, key), props); err != nil {
 		return "", err
 	}
 
-	return Update([]string{root, key}, paramsData, props)
+	return Update(updatePath(root, key), paramsData, props)
 }
 
 // Delete deletes a param value.
@@ -52,53 +75,116 @@ func Delete(path []string, paramsData, key, root string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	cur := props
 
-	for i, k := range path {
-		if i == len(path)-1 {
-			delete(cur, k)
-		} else {
-			m, ok := cur[k].(map[string]interface{})
-			if !ok {
-				return "", errors.New("path not found")
-			}
+	if err = deleteValue(props, path); err != nil {
+		return "", err
+	}
 
-			cur = m
+	return Update(updatePath(root, key), paramsData, props)
+}
+
+// Remove removes the field at path, e.g. a component's entry, from a params
+// file. It is not an error if the field doesn't exist.
+func Remove(path []string, src string) (string, error) {
+	if len(path) == 0 {
+		return "", errors.New("path is empty")
+	}
+
+	obj, err := parseParams(src)
+	if err != nil {
+		return "", errors.Wrap(err, "parse jsonnet")
+	}
+
+	for _, k := range path[:len(path)-1] {
+		field, err := findField(obj, k)
+		if err != nil || field == nil {
+			return src, err
+		}
+
+		child, ok := field.Expr2.(*astext.Object)
+		if !ok {
+			return "", errors.Errorf("child is not an object at %q", k)
 		}
+		obj = child
+	}
+
+	field, err := findField(obj, path[len(path)-1])
+	if err != nil || field == nil {
+		return src, err
 	}
 
-	return Update([]string{root, key}, paramsData, props)
+	e := newEditor(src)
+	e.deleteField(*field)
+	return e.String()
 }
 
-// Update updates a params file with the params for a component.
+// updatePath is the path to the params for key under root. A blank key refers
+// to root itself.
+func updatePath(root, key string) []string {
+	if key == "" {
+		return []string{root}
+	}
+
+	return []string{root, key}
+}
+
+// Update updates a params file with the params for a component. Only fields
+// which change are rewritten, so comments and formatting elsewhere in the file
+// are preserved.
 func Update(path []string, src string, params map[string]interface{}) (string, error) {
-	obj, err := jsonnetutil.Parse("params.libsonnet", src)
+	obj, err := parseParams(src)
 	if err != nil {
 		return "", errors.Wrap(err, "parse jsonnet")
 	}
 
-	paramsObject, err := nm.KVFromMap(params)
+	e := newEditor(src)
+	if err := e.setPath(obj, nil, path, params, false); err != nil {
+		return "", errors.Wrap(err, "update params")
+	}
+
+	return e.String()
+}
+
+// parseParams parses a params file. Local bindings may precede the params
+// object.
+func parseParams(src string) (*astext.Object, error) {
+	tokens, err := docparser.Lex("params.libsonnet", src)
 	if err != nil {
-		return "", errors.Wrap(err, "convert params to object")
+		return nil, errors.Wrap(err, "lex jsonnet snippet")
 	}
 
-	if err := jsonnetutil.Set(obj, path, paramsObject.Node()); err != nil {
-		return "", errors.Wrap(err, "update params")
+	node, err := docparser.Parse(tokens)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse jsonnet snippet")
 	}
 
-	var buf bytes.Buffer
-	if err := printer.Fprint(&buf, obj); err != nil {
-		return "", errors.Wrap(err, "rebuild params")
+	for {
+		local, ok := node.(*ast.Local)
+		if !ok {
+			break
+		}
+		node = local.Body
+	}
+
+	obj, ok := node.(*astext.Object)
+	if !ok {
+		return nil, errors.New("root was not an object")
 	}
 
-	return buf.String(), nil
+	return obj, nil
 }
 
-// ToMap converts a component's params to a map.
+// ToMap converts a component's params to a map. Params set by expressions are
+// evaluated.
 func ToMap(componentName, src, root string) (map[string]interface{}, error) {
-	obj, err := jsonnetutil.Parse("params.libsonnet", src)
+	m, _, err := toMap(componentName, src, root)
+	return m, err
+}
+
+func toMap(componentName, src, root string) (map[string]interface{}, ComputedParams, error) {
+	obj, err := parseParams(src)
 	if err != nil {
-		return nil, errors.Wrap(err, "parse jsonnet")
+		return nil, nil, errors.Wrap(err, "parse jsonnet")
 	}
 
 	path := make([]string, 0)
@@ -110,31 +196,80 @@ func ToMap(componentName, src, root string) (map[string]interface{}, error) {
 		path = append(path, componentName)
 	}
 
+	found, err := hasField(obj, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !found {
+		return nil, nil, &notFoundError{what: "params for " + strings.Join(path, ".")}
+	}
+
 	child, err := jsonnetutil.FindObject(obj, path)
 	if err != nil {
-		return nil, errors.Wrapf(err, "find child paths for %s", strings.Join(path, "."))
+		return nil, nil, errors.Wrapf(err, "find child paths for %s", strings.Join(path, "."))
 	}
 
-	m, err := findValues(child)
+	parent := path
+	if len(path) > 0 {
+		parent = path[:len(path)-1]
+	}
+
+	r := newValueReader(src)
+	m, err := r.objectValues(child, parent)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if componentName == "" {
-		return m[root].(map[string]interface{}), nil
+		return m[root].(map[string]interface{}), r.relative(path), nil
 	}
 
 	paramsMap, ok := m[componentName].(map[string]interface{})
 	if !ok {
-		return nil, errors.Errorf("could not find %q in components", componentName)
+		return nil, nil, errors.Errorf("could not find %q in components", componentName)
+	}
+
+	return paramsMap, r.relative(path), nil
+}
+
+// notFoundError is returned when params or a param path don't exist.
+type notFoundError struct {
+	what string
+}
+
+func (e *notFoundError) Error() string {
+	return e.what + " not found"
+}
+
+// IsNotFound reports whether err was returned because params or a param path
+// don't exist.
+func IsNotFound(err error) bool {
+	_, ok := errors.Cause(err).(*notFoundError)
+	return ok
+}
+
+// hasField reports whether obj has a value field at path.
+func hasField(obj *astext.Object, path []string) (bool, error) {
+	for i, k := range path {
+		field, err := findField(obj, k)
+		if err != nil || field == nil {
+			return false, err
+		}
+
+		child, ok := field.Expr2.(*astext.Object)
+		if !ok || i == len(path)-1 {
+			return true, nil
+		}
+		obj = child
 	}
 
-	return paramsMap, nil
+	return true, nil
 }
 
 var (
-	reFloat = regexp.MustCompile(`^([0-9]+[.])?[0-9]$`)
-	reInt   = regexp.MustCompile(`^[1-9]{1}[0-9]?$`)
+	reFloat = regexp.MustCompile(`^-?[0-9]+[.][0-9]+$`)
+	reInt   = regexp.MustCompile(`^(0|-?[1-9][0-9]*)$`)
 	reArray = regexp.MustCompile(`^\[`)
 	reMap   = regexp.MustCompile(`^\{`)
 )
@@ -169,44 +304,6 @@ func DecodeValue(s string) (interface{}, error) {
 	}
 }
 
-func findValues(obj *astext.Object) (map[string]interface{}, error) {
-	m := make(map[string]interface{})
-
-	for i := range obj.Fields {
-		id, err := jsonnetutil.FieldID(obj.Fields[i])
-		if err != nil {
-			return nil, err
-		}
-
-		switch t := obj.Fields[i].Expr2.(type) {
-		default:
-			return nil, errors.Errorf("unknown value type %T", t)
-		case *ast.LiteralString, *ast.LiteralBoolean, *ast.LiteralNumber:
-			v, err := nodeValue(t)
-			if err != nil {
-				return nil, err
-			}
-			m[id] = v
-		case *ast.Array:
-			array, err := arrayValues(t)
-			if err != nil {
-				return nil, err
-			}
-			m[id] = array
-		case *astext.Object:
-			child, err := findValues(t)
-			if err != nil {
-				return nil, err
-			}
-
-			m[id] = child
-		}
-
-	}
-
-	return m, nil
-}
-
 func nodeValue(node ast.Node) (interface{}, error) {
 	switch t := node.(type) {
 	default:
@@ -217,23 +314,11 @@ func nodeValue(node ast.Node) (interface{}, error) {
 		return t.Value, nil
 	case *ast.LiteralNumber:
 		return t.Value, nil
+	case *ast.LiteralNull:
+		return nil, nil
 	}
 }
 
-func arrayValues(array *ast.Array) ([]interface{}, error) {
-	out := make([]interface{}, 0)
-	for i := range array.Elements {
-		v, err := nodeValue(array.Elements[i])
-		if err != nil {
-			return nil, errors.Errorf("arrays can't contain at %T", array.Elements[i])
-		}
-
-		out = append(out, v)
-	}
-
-	return out, nil
-}
-
 func mergeMaps(m1 map[string]interface{}, m2 map[string]interface{}, path []string) error {
 	for k := range m2 {
 		_, ok := m1[k]
@@ -35,6 +35,23 @@ func TestUpdate(t *testing.T) {
 	require.Equal(t, string(expected), got)
 }
 
+func TestRemove(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := Remove([]string{"components", "guestbook-ui"}, string(b))
+	require.NoError(t, err)
+
+	m, err := ToMap("", got, "components")
+	require.NoError(t, err)
+	require.Empty(t, m)
+	require.Contains(t, got, "// Each object below should correspond to a component")
+
+	unchanged, err := Remove([]string{"components", "missing"}, got)
+	require.NoError(t, err)
+	require.Equal(t, got, unchanged)
+}
+
 func TestToMap(t *testing.T) {
 	b, err := ioutil.ReadFile("testdata/nested-params.libsonnet")
 	require.NoError(t, err)
@@ -81,6 +98,31 @@ func TestDecodeValue(t *testing.T) {
 			val:      "9",
 			expected: 9,
 		},
+		{
+			name:     "large int",
+			val:      "100",
+			expected: 100,
+		},
+		{
+			name:     "zero",
+			val:      "0",
+			expected: 0,
+		},
+		{
+			name:     "negative int",
+			val:      "-5",
+			expected: -5,
+		},
+		{
+			name:     "multi digit float",
+			val:      "10.25",
+			expected: 10.25,
+		},
+		{
+			name:     "leading zero",
+			val:      "0755",
+			expected: "0755",
+		},
 		{
 			name:     "bool true",
 			val:      "True",
@@ -106,6 +148,22 @@ func TestDecodeValue(t *testing.T) {
 			val:      `[1,2,3]`,
 			expected: []interface{}{1.0, 2.0, 3.0},
 		},
+		{
+			name: "array of objects",
+			val:  `[{"containerPort": 80}, {"containerPort": 443}]`,
+			expected: []interface{}{
+				map[string]interface{}{"containerPort": 80.0},
+				map[string]interface{}{"containerPort": 443.0},
+			},
+		},
+		{
+			name: "nested array",
+			val:  `[[1, 2], ["a"]]`,
+			expected: []interface{}{
+				[]interface{}{1.0, 2.0},
+				[]interface{}{"a"},
+			},
+		},
 		{
 			name: "map",
 			val:  `{"a": "1", "b": "2"}`,
@@ -0,0 +1,280 @@
+package params
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	reIndex     = regexp.MustCompile(`^\[(0|[1-9][0-9]*)\]$`)
+	rePathIndex = regexp.MustCompile(`\[[^\]]*\]`)
+)
+
+// ParsePath parses a param path. Keys are separated by dots and array elements
+// are addressed by index, e.g. `ports[0].containerPort`. Indexes are returned
+// as their own path elements, e.g. `[0]`. Keys containing dots or brackets are
+// quoted, e.g. `labels."app.kubernetes.io/name"`.
+func ParsePath(s string) ([]string, error) {
+	if s == "" {
+		return nil, errors.New("param path is blank")
+	}
+
+	var path []string
+	rest := s
+	for {
+		if strings.HasPrefix(rest, `"`) {
+			end := quoteEnd(rest)
+			if end == -1 {
+				return nil, errors.Errorf("unterminated quote in param path %q", s)
+			}
+
+			key, err := strconv.Unquote(rest[:end])
+			if err != nil {
+				return nil, errors.Errorf("invalid quoted key in param path %q", s)
+			}
+
+			path = append(path, key)
+			rest = rest[end:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+
+			if end == 0 {
+				return nil, errors.Errorf("param path %q has a blank key", s)
+			}
+
+			path = append(path, rest[:end])
+			rest = rest[end:]
+		}
+
+		for strings.HasPrefix(rest, "[") {
+			loc := rePathIndex.FindStringIndex(rest)
+			if loc == nil || loc[0] != 0 || !reIndex.MatchString(rest[:loc[1]]) {
+				return nil, errors.Errorf("invalid index in param path %q", s)
+			}
+
+			path = append(path, rest[:loc[1]])
+			rest = rest[loc[1]:]
+		}
+
+		if rest == "" {
+			return path, nil
+		}
+
+		if rest[0] != '.' {
+			return nil, errors.Errorf("unexpected %q in param path %q", rest, s)
+		}
+		rest = rest[1:]
+	}
+}
+
+// quoteEnd returns the position after the closing quote of the quoted string
+// s starts with, or -1 if it isn't closed.
+func quoteEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// formatKey quotes a key if it can't be parsed by ParsePath as it is.
+func formatKey(k string) string {
+	if k == "" || strings.ContainsAny(k, `."[`) {
+		return strconv.Quote(k)
+	}
+
+	return k
+}
+
+// pathIndex returns the array index a path element refers to.
+func pathIndex(k string) (int, bool) {
+	if !reIndex.MatchString(k) {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(k[1 : len(k)-1])
+	if err != nil {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// HasIndex reports whether a path contains an array index.
+func HasIndex(path []string) bool {
+	for _, k := range path {
+		if _, ok := pathIndex(k); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indexElement(i int) string {
+	return fmt.Sprintf("[%d]", i)
+}
+
+// LookupValue finds the value at path in a params value.
+func LookupValue(v interface{}, path []string) (interface{}, bool) {
+	cur := v
+	for _, k := range path {
+		if i, ok := pathIndex(k); ok {
+			array, ok := cur.([]interface{})
+			if !ok || i >= len(array) {
+				return nil, false
+			}
+
+			cur = array[i]
+			continue
+		}
+
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if cur, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+// setValue sets value at path in m. Objects missing along the path are created
+// and an array can be extended by setting the index after its last element.
+// Objects set over objects are merged.
+func setValue(m map[string]interface{}, path []string, value interface{}) error {
+	if len(path) == 0 {
+		return errors.New("param path is blank")
+	}
+
+	_, err := setIn(m, path, 0, value)
+	return err
+}
+
+func setIn(cur interface{}, path []string, depth int, value interface{}) (interface{}, error) {
+	if depth == len(path) {
+		existing, isMap1 := cur.(map[string]interface{})
+		m, isMap2 := value.(map[string]interface{})
+		if isMap1 && isMap2 {
+			if err := mergeMaps(existing, m, path); err != nil {
+				return nil, err
+			}
+
+			return existing, nil
+		}
+
+		return value, nil
+	}
+
+	k := path[depth]
+	if i, ok := pathIndex(k); ok {
+		array, ok := cur.([]interface{})
+		if !ok {
+			if cur != nil {
+				return nil, errors.Errorf("%s is not an array", schemaPath(path[:depth]))
+			}
+			array = make([]interface{}, 0)
+		}
+
+		if i > len(array) {
+			return nil, errors.Errorf("index %d is out of range for %s", i, schemaPath(path[:depth]))
+		}
+
+		if i == len(array) {
+			array = append(array, nil)
+		}
+
+		v, err := setIn(array[i], path, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		array[i] = v
+
+		return array, nil
+	}
+
+	m, ok := cur.(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{})
+	}
+
+	v, err := setIn(m[k], path, depth+1, value)
+	if err != nil {
+		return nil, err
+	}
+	m[k] = v
+
+	return m, nil
+}
+
+// deleteValue deletes the value at path in m. Deleting an array element
+// shifts the elements after it.
+func deleteValue(m map[string]interface{}, path []string) error {
+	if len(path) == 0 {
+		return errors.New("param path is blank")
+	}
+
+	_, err := deleteIn(m, path, 0)
+	return err
+}
+
+func deleteIn(cur interface{}, path []string, depth int) (interface{}, error) {
+	k := path[depth]
+	last := depth == len(path)-1
+
+	if i, ok := pathIndex(k); ok {
+		array, ok := cur.([]interface{})
+		if !ok || i >= len(array) {
+			return nil, &notFoundError{what: "path"}
+		}
+
+		if last {
+			return append(array[:i:i], array[i+1:]...), nil
+		}
+
+		v, err := deleteIn(array[i], path, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		array[i] = v
+
+		return array, nil
+	}
+
+	m, ok := cur.(map[string]interface{})
+	if !ok {
+		return nil, &notFoundError{what: "path"}
+	}
+
+	if last {
+		if _, ok := m[k]; !ok {
+			return nil, &notFoundError{what: "path"}
+		}
+
+		delete(m, k)
+		return m, nil
+	}
+
+	v, err := deleteIn(m[k], path, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	m[k] = v
+
+	return m, nil
+}
@@ -0,0 +1,224 @@
+package params
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		expected []string
+		isErr    bool
+	}{
+		{
+			name:     "key",
+			path:     "replicas",
+			expected: []string{"replicas"},
+		},
+		{
+			name:     "nested key",
+			path:     "image.tag",
+			expected: []string{"image", "tag"},
+		},
+		{
+			name:     "index",
+			path:     "ports[0].containerPort",
+			expected: []string{"ports", "[0]", "containerPort"},
+		},
+		{
+			name:     "nested index",
+			path:     "matrix[1][12]",
+			expected: []string{"matrix", "[1]", "[12]"},
+		},
+		{
+			name:     "quoted key",
+			path:     `labels."app.kubernetes.io/name"`,
+			expected: []string{"labels", "app.kubernetes.io/name"},
+		},
+		{
+			name:     "quoted key with index",
+			path:     `"items[]"[0]."a\"b"`,
+			expected: []string{"items[]", "[0]", `a"b`},
+		},
+		{
+			name:  "blank",
+			path:  "",
+			isErr: true,
+		},
+		{
+			name:  "unterminated quote",
+			path:  `labels."app`,
+			isErr: true,
+		},
+		{
+			name:  "text after quoted key",
+			path:  `labels."app"name`,
+			isErr: true,
+		},
+		{
+			name:  "blank key",
+			path:  "ports..name",
+			isErr: true,
+		},
+		{
+			name:  "index without key",
+			path:  "[0]",
+			isErr: true,
+		},
+		{
+			name:  "invalid index",
+			path:  "ports[a]",
+			isErr: true,
+		},
+		{
+			name:  "unclosed index",
+			path:  "ports[0",
+			isErr: true,
+		},
+		{
+			name:  "text after index",
+			path:  "ports[0]name",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePath(tc.path)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestToMap_arrays(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/array-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := ToMap("web", string(b), "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"ports": []interface{}{
+			map[string]interface{}{"containerPort": float64(80), "name": "http"},
+			map[string]interface{}{"containerPort": float64(443), "name": "https"},
+		},
+		"matrix": []interface{}{
+			[]interface{}{float64(1), float64(2)},
+			[]interface{}{float64(3)},
+		},
+	}
+
+	require.Equal(t, expected, got)
+}
+
+func TestSet_arrays(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/array-params.libsonnet")
+	require.NoError(t, err)
+	src := string(b)
+
+	cases := []struct {
+		name     string
+		path     string
+		value    interface{}
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "field in array element",
+			path:     "ports[1].containerPort",
+			value:    8443,
+			expected: strings.Replace(src, "containerPort: 443", "containerPort: 8443", 1),
+		},
+		{
+			name:     "nested array element",
+			path:     "matrix[0][1]",
+			value:    5,
+			expected: strings.Replace(src, "[[1, 2], [3]]", "[[1, 5], [3]]", 1),
+		},
+		{
+			name:     "append element",
+			path:     "matrix[2]",
+			value:    []interface{}{4},
+			expected: strings.Replace(src, "[[1, 2], [3]]", "[[1, 2], [3], [4]]", 1),
+		},
+		{
+			name:  "append object",
+			path:  "ports[2]",
+			value: map[string]interface{}{"containerPort": 8080},
+			expected: strings.Replace(src, `        { containerPort: 443, name: "https" },
+`, `        { containerPort: 443, name: "https" },
+        {
+          containerPort: 8080,
+        },
+`, 1),
+		},
+		{
+			name:  "index out of range",
+			path:  "ports[5].name",
+			value: "x",
+			isErr: true,
+		},
+		{
+			name:  "index into object",
+			path:  "ports[0].name[0]",
+			value: "x",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := ParsePath(tc.path)
+			require.NoError(t, err)
+
+			got, err := Set(path, src, "web", tc.value, "components")
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestDelete_arrays(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/array-params.libsonnet")
+	require.NoError(t, err)
+	src := string(b)
+
+	path, err := ParsePath("ports[0].name")
+	require.NoError(t, err)
+
+	got, err := Delete(path, src, "web", "components")
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(src, `{ containerPort: 80, name: "http" }`, `{ containerPort: 80 }`, 1), got)
+
+	path, err = ParsePath("matrix[1]")
+	require.NoError(t, err)
+
+	got, err = Delete(path, src, "web", "components")
+	require.NoError(t, err)
+
+	m, err := ToMap("web", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{[]interface{}{float64(1), float64(2)}}, m["matrix"])
+
+	path, err = ParsePath("ports[3]")
+	require.NoError(t, err)
+
+	_, err = Delete(path, src, "web", "components")
+	require.Error(t, err)
+}
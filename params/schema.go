@@ -0,0 +1,367 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaFile is the name of the optional schema file which lives next to a
+// namespace's params.libsonnet.
+const SchemaFile = "params.schema.json"
+
+// Schema is the subset of JSON Schema used to describe params. A schema
+// describes the params file as a whole, so component params are found under
+// `properties.components.properties.<component>`.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+}
+
+var schemaTypes = map[string]bool{
+	"":        true,
+	"string":  true,
+	"integer": true,
+	"number":  true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+}
+
+// ParseSchema parses a JSON encoded schema.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.Wrap(err, "decode params schema")
+	}
+
+	if err := s.check(nil); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *Schema) check(path []string) error {
+	if !schemaTypes[s.Type] {
+		return errors.Errorf("%s: unsupported type %q", schemaPath(path), s.Type)
+	}
+
+	for name, child := range s.Properties {
+		if child == nil {
+			return errors.Errorf("%s: property %q has no schema", schemaPath(path), name)
+		}
+
+		if err := child.check(append(path, name)); err != nil {
+			return err
+		}
+	}
+
+	if s.Items != nil {
+		return s.Items.check(append(path, "[]"))
+	}
+
+	return nil
+}
+
+// Lookup returns the schema for a path. Array indexes in the path refer to the
+// array's items. It returns nil if the schema does not describe the path.
+func (s *Schema) Lookup(path []string) *Schema {
+	cur := s
+	for _, k := range path {
+		if cur == nil {
+			return nil
+		}
+
+		if _, ok := pathIndex(k); ok {
+			cur = cur.Items
+			continue
+		}
+
+		cur = cur.Properties[k]
+	}
+
+	return cur
+}
+
+// DecodeValue decodes a string to the type the schema declares for path and
+// validates it. Values without a declared type are decoded with DecodeValue.
+func (s *Schema) DecodeValue(path []string, raw string) (interface{}, error) {
+	child := s.Lookup(path)
+	if child == nil {
+		return DecodeValue(raw)
+	}
+
+	var v interface{}
+	var err error
+
+	switch child.Type {
+	case "":
+		v, err = DecodeValue(raw)
+		if err != nil {
+			return nil, err
+		}
+	case "string":
+		v = raw
+	case "integer":
+		v, err = strconv.Atoi(raw)
+	case "number":
+		v, err = strconv.ParseFloat(raw, 64)
+	case "boolean":
+		v, err = strconv.ParseBool(raw)
+	case "object":
+		var obj map[string]interface{}
+		err = json.Unmarshal([]byte(raw), &obj)
+		v = obj
+	case "array":
+		var array []interface{}
+		err = json.Unmarshal([]byte(raw), &array)
+		v = array
+	}
+
+	if err != nil {
+		return nil, errors.Errorf("%s: %q is not a valid %s", schemaPath(path), raw, child.Type)
+	}
+
+	var problems []string
+	child.validate(path, v, &problems)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return v, nil
+}
+
+// ValidationError describes params which do not match their schema.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("params do not match schema: %s", strings.Join(e.Problems, "; "))
+}
+
+// ValidateAt validates a value against the schema found at path. Nothing is
+// validated if the schema does not describe path.
+func (s *Schema) ValidateAt(path []string, v interface{}) error {
+	child := s.Lookup(path)
+	if child == nil {
+		return nil
+	}
+
+	var problems []string
+	child.validate(path, v, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func (s *Schema) validate(path []string, v interface{}, problems *[]string) {
+	if s.Type != "" && !matchesType(s.Type, v) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s",
+			schemaPath(path), s.Type, jsonType(v)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		*problems = append(*problems, fmt.Sprintf("%s: %v is not one of %s",
+			schemaPath(path), v, enumString(s.Enum)))
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := t[name]; ok {
+				continue
+			}
+
+			if child := s.Properties[name]; child != nil && child.Default != nil {
+				continue
+			}
+
+			*problems = append(*problems, fmt.Sprintf("%s: missing required param",
+				schemaPath(append(path, name))))
+		}
+
+		var names []string
+		for name := range t {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if child := s.Properties[name]; child != nil {
+				child.validate(append(path, name), t[name], problems)
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return
+		}
+
+		for i := range t {
+			childPath := append(path[:len(path):len(path)], fmt.Sprintf("[%d]", i))
+			s.Items.validate(childPath, t[i], problems)
+		}
+	}
+}
+
+// ApplyDefaults sets defaults for missing properties of the object found at
+// path.
+func (s *Schema) ApplyDefaults(path []string, v interface{}) {
+	child := s.Lookup(path)
+	if child == nil {
+		return
+	}
+
+	child.applyDefaults(v)
+}
+
+func (s *Schema) applyDefaults(v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for name, child := range s.Properties {
+		if _, ok := m[name]; !ok && child.Default != nil {
+			m[name] = copyValue(child.Default)
+		}
+
+		if cur, ok := m[name]; ok {
+			child.applyDefaults(cur)
+		}
+	}
+}
+
+func matchesType(typ string, v interface{}) bool {
+	switch typ {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "number":
+		switch v.(type) {
+		case int, int64, float64:
+			return true
+		}
+		return false
+	case "integer":
+		switch t := v.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return t == math.Trunc(t)
+		}
+		return false
+	}
+
+	return true
+}
+
+func jsonType(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64:
+		return "integer"
+	case float64:
+		if t == math.Trunc(t) {
+			return "integer"
+		}
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(normalizeNumber(e), normalizeNumber(v)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeNumber(v interface{}) interface{} {
+	switch t := v.(type) {
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	default:
+		return v
+	}
+}
+
+func enumString(enum []interface{}) string {
+	var out []string
+	for _, e := range enum {
+		b, err := json.Marshal(e)
+		if err != nil {
+			out = append(out, fmt.Sprintf("%v", e))
+			continue
+		}
+		out = append(out, string(b))
+	}
+
+	return "[" + strings.Join(out, ", ") + "]"
+}
+
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{})
+		for k := range t {
+			m[k] = copyValue(t[k])
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i := range t {
+			a[i] = copyValue(t[i])
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+func schemaPath(path []string) string {
+	if len(path) == 0 {
+		return "(root)"
+	}
+
+	return strings.Replace(strings.Join(path, "."), ".[", "[", -1)
+}
@@ -0,0 +1,156 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+  "properties": {
+    "components": {
+      "properties": {
+        "guestbook-ui": {
+          "required": ["image"],
+          "properties": {
+            "image": {"type": "string"},
+            "replicas": {"type": "integer", "default": 1},
+            "type": {"type": "string", "enum": ["ClusterIP", "NodePort"]},
+            "ports": {"type": "array", "items": {"type": "integer"}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestParseSchema(t *testing.T) {
+	_, err := ParseSchema([]byte(testSchema))
+	require.NoError(t, err)
+
+	_, err = ParseSchema([]byte(`{"type": "int"}`))
+	require.Error(t, err)
+
+	_, err = ParseSchema([]byte(`{`))
+	require.Error(t, err)
+}
+
+func TestSchema_DecodeValue(t *testing.T) {
+	schema, err := ParseSchema([]byte(testSchema))
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		key      string
+		raw      string
+		expected interface{}
+		isErr    bool
+	}{
+		{name: "integer", key: "replicas", raw: "100", expected: 100},
+		{name: "invalid integer", key: "replicas", raw: "lots", isErr: true},
+		{name: "string stays a string", key: "image", raw: "100", expected: "100"},
+		{name: "enum", key: "type", raw: "NodePort", expected: "NodePort"},
+		{name: "not in enum", key: "type", raw: "External", isErr: true},
+		{name: "array", key: "ports", raw: "[80, 443]", expected: []interface{}{80.0, 443.0}},
+		{name: "array item type", key: "ports", raw: `["80"]`, isErr: true},
+		{name: "array item", key: "ports[1]", raw: "8080", expected: 8080},
+		{name: "invalid array item", key: "ports[0]", raw: "http", isErr: true},
+		{name: "not in schema", key: "other", raw: "true", expected: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParsePath(tc.key)
+			require.NoError(t, err)
+
+			path := append([]string{"components", "guestbook-ui"}, key...)
+			got, err := schema.DecodeValue(path, tc.raw)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestSchema_nil(t *testing.T) {
+	var schema *Schema
+
+	got, err := schema.DecodeValue([]string{"components", "a"}, "5")
+	require.NoError(t, err)
+	require.Equal(t, 5, got)
+
+	require.NoError(t, schema.ValidateAt([]string{"components"}, "anything"))
+}
+
+func TestSchema_ValidateAt(t *testing.T) {
+	schema, err := ParseSchema([]byte(testSchema))
+	require.NoError(t, err)
+
+	path := []string{"components", "guestbook-ui"}
+
+	err = schema.ValidateAt(path, map[string]interface{}{
+		"image":    "nginx",
+		"replicas": float64(2),
+	})
+	require.NoError(t, err)
+
+	err = schema.ValidateAt(path, map[string]interface{}{
+		"replicas": "2",
+		"type":     "External",
+	})
+	require.Error(t, err)
+
+	verr, ok := err.(*ValidationError)
+	require.True(t, ok)
+
+	expected := []string{
+		"components.guestbook-ui.image: missing required param",
+		"components.guestbook-ui.replicas: expected integer, got string",
+		`components.guestbook-ui.type: External is not one of ["ClusterIP", "NodePort"]`,
+	}
+	require.Equal(t, expected, verr.Problems)
+}
+
+func TestSchema_ApplyDefaults(t *testing.T) {
+	schema, err := ParseSchema([]byte(testSchema))
+	require.NoError(t, err)
+
+	m := map[string]interface{}{
+		"guestbook-ui": map[string]interface{}{
+			"image": "nginx",
+		},
+	}
+
+	schema.ApplyDefaults([]string{"components"}, m)
+
+	expected := map[string]interface{}{
+		"guestbook-ui": map[string]interface{}{
+			"image":    "nginx",
+			"replicas": float64(1),
+		},
+	}
+	require.Equal(t, expected, m)
+}
+
+func TestSet_schema(t *testing.T) {
+	schema, err := ParseSchema([]byte(testSchema))
+	require.NoError(t, err)
+
+	src := `{
+  components: {
+    "guestbook-ui": {
+      image: "nginx",
+    },
+  },
+}`
+
+	_, err = Set([]string{"replicas"}, src, "guestbook-ui", 3, "components", WithSchema(schema))
+	require.NoError(t, err)
+
+	_, err = Set([]string{"replicas"}, src, "guestbook-ui", "3", "components", WithSchema(schema))
+	require.Error(t, err)
+}
@@ -0,0 +1,179 @@
+package params
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SecretsFile is the name of the file which holds a namespace's secret
+	// params. It lives next to the namespace's params.libsonnet.
+	SecretsFile = "secrets.libsonnet"
+
+	// SecretMask is displayed in place of a secret param's value.
+	SecretMask = "********"
+
+	// SecretKeySize is the size of the key used to encrypt secret params.
+	SecretKeySize = 32
+
+	// EmptySecrets is the source of a secrets file without any secrets.
+	EmptySecrets = "{\n  components: {\n  },\n}\n"
+
+	secretPrefix = "kscomp:v1:"
+)
+
+// GenerateSecretKey generates a key for encrypting secret params.
+func GenerateSecretKey() ([]byte, error) {
+	key := make([]byte, SecretKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Wrap(err, "generate secret key")
+	}
+
+	return key, nil
+}
+
+// EncodeSecretKey encodes a key so it can be written to a key file.
+func EncodeSecretKey(key []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(key) + "\n")
+}
+
+// DecodeSecretKey decodes the contents of a key file.
+func DecodeSecretKey(data []byte) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, errors.Wrap(err, "decode secret key")
+	}
+
+	if len(key) != SecretKeySize {
+		return nil, errors.Errorf("secret key is %d bytes; expected %d", len(key), SecretKeySize)
+	}
+
+	return key, nil
+}
+
+// IsEncrypted reports whether a value was encrypted with EncryptValue.
+func IsEncrypted(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, secretPrefix)
+}
+
+// EncryptValue encrypts a param value with AES-256-GCM. The value is JSON
+// encoded first so its type is kept.
+func EncryptValue(key []byte, v interface{}) (string, error) {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "encode secret value")
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "generate nonce")
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return secretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue decrypts a value encrypted with EncryptValue.
+func DecryptValue(key []byte, s string) (interface{}, error) {
+	if !IsEncrypted(s) {
+		return nil, errors.New("value is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, secretPrefix))
+	if err != nil {
+		return nil, errors.Wrap(err, "decode secret value")
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("secret value is truncated")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("unable to decrypt secret value; is the secret key correct?")
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(plaintext, &v); err != nil {
+		return nil, errors.Wrap(err, "decode secret value")
+	}
+
+	return v, nil
+}
+
+// DecryptSecrets decrypts the component params in a secrets file. The result
+// is shaped like params, i.e. `{"components": {...}}`.
+func DecryptSecrets(key []byte, src string) (map[string]interface{}, error) {
+	components, err := ToMap("", src, "components")
+	if err != nil {
+		return nil, errors.Wrap(err, "read secrets")
+	}
+
+	decrypted, err := decryptValues(key, components, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"components": decrypted}, nil
+}
+
+func decryptValues(key []byte, v interface{}, path []string) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{})
+		for k := range t {
+			child, err := decryptValues(key, t[k], appendPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			m[k] = child
+		}
+		return m, nil
+	case string:
+		if !IsEncrypted(t) {
+			return nil, errors.Errorf("secret %s is not encrypted", schemaPath(path))
+		}
+
+		child, err := DecryptValue(key, t)
+		if err != nil {
+			return nil, errors.Wrapf(err, "secret %s", schemaPath(path))
+		}
+		return child, nil
+	default:
+		return nil, errors.Errorf("secret %s is not encrypted", schemaPath(path))
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "create cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "create cipher")
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,78 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptValue(t *testing.T) {
+	key, err := GenerateSecretKey()
+	require.NoError(t, err)
+
+	values := []interface{}{"hunter2", float64(5), true, map[string]interface{}{"user": "admin"}}
+	for _, v := range values {
+		encrypted, err := EncryptValue(key, v)
+		require.NoError(t, err)
+		require.True(t, IsEncrypted(encrypted))
+
+		got, err := DecryptValue(key, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	}
+
+	other, err := GenerateSecretKey()
+	require.NoError(t, err)
+
+	encrypted, err := EncryptValue(key, "hunter2")
+	require.NoError(t, err)
+
+	_, err = DecryptValue(other, encrypted)
+	require.Error(t, err)
+
+	_, err = DecryptValue(key, "hunter2")
+	require.Error(t, err)
+}
+
+func TestDecodeSecretKey(t *testing.T) {
+	key, err := GenerateSecretKey()
+	require.NoError(t, err)
+
+	got, err := DecodeSecretKey(EncodeSecretKey(key))
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+
+	_, err = DecodeSecretKey([]byte("c2hvcnQ="))
+	require.Error(t, err)
+}
+
+func TestDecryptSecrets(t *testing.T) {
+	key, err := GenerateSecretKey()
+	require.NoError(t, err)
+
+	encrypted, err := EncryptValue(key, "hunter2")
+	require.NoError(t, err)
+
+	src, err := Set([]string{"db", "password"}, EmptySecrets, "guestbook-ui", encrypted, "components")
+	require.NoError(t, err)
+
+	got, err := DecryptSecrets(key, src)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"components": map[string]interface{}{
+			"guestbook-ui": map[string]interface{}{
+				"db": map[string]interface{}{
+					"password": "hunter2",
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, got)
+
+	src, err = Set([]string{"user"}, src, "guestbook-ui", "admin", "components")
+	require.NoError(t, err)
+
+	_, err = DecryptSecrets(key, src)
+	require.Error(t, err)
+}
@@ -0,0 +1,14 @@
+{
+  global: {
+  },
+  components: {
+    web: {
+      ports: [
+        // http
+        { containerPort: 80, name: "http" },
+        { containerPort: 443, name: "https" },
+      ],
+      matrix: [[1, 2], [3]],
+    },
+  },
+}
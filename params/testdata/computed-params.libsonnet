@@ -0,0 +1,15 @@
+local base = 2;
+
+{
+  global: {
+  },
+  components: {
+    "guestbook-ui": {
+      // scaled from base
+      replicas: 2 * base,
+      image: "gcr.io/heptio-images/ks-guestbook-demo:0.1",
+      ports: [80, base * 40],
+      labels: { app: "guestbook" } + { tier: "web" },
+    },
+  },
+}
@@ -0,0 +1,4 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+}
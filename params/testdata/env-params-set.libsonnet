@@ -0,0 +1,13 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components +: {
+    "guestbook-ui" +: {
+      replicas: 3,
+      image: "gcr.io/heptio-images/ks-guestbook-demo:0.2",
+    },
+    redis +: {
+      replicas: 2,
+    },
+  },
+}
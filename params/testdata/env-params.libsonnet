@@ -0,0 +1,9 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components +: {
+    "guestbook-ui" +: {
+      replicas: 3,
+    },
+  },
+}
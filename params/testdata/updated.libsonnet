@@ -1,5 +1,7 @@
 {
   global: {
+    // User-defined global parameters; accessible to all component and environments, Ex:
+    // replicas: 4,
   },
   // Component-level parameters, defined initially from 'ks prototype use ...'
   // Each object below should correspond to a component in the components/ directory
@@ -13,4 +15,4 @@
       type: "NodePort",
     },
   },
-}
\ No newline at end of file
+}
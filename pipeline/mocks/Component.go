@@ -4,6 +4,7 @@ package mocks
 import app "github.com/ksonnet/ksonnet/metadata/app"
 import component "github.com/bryanl/woowoo/component"
 import mock "github.com/stretchr/testify/mock"
+import params "github.com/bryanl/woowoo/params"
 
 // Component is an autogenerated mock type for the Component type
 type Component struct {
@@ -75,6 +76,50 @@ func (_m *Component) NSResolveParams(ns component.Namespace) (string, error) {
 	return r0, r1
 }
 
+// NSSchema provides a mock function with given fields: ns
+func (_m *Component) NSSchema(ns component.Namespace) (*params.Schema, error) {
+	ret := _m.Called(ns)
+
+	var r0 *params.Schema
+	if rf, ok := ret.Get(0).(func(component.Namespace) *params.Schema); ok {
+		r0 = rf(ns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*params.Schema)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(component.Namespace) error); ok {
+		r1 = rf(ns)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NSSecrets provides a mock function with given fields: ns
+func (_m *Component) NSSecrets(ns component.Namespace) (string, error) {
+	ret := _m.Called(ns)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(component.Namespace) string); ok {
+		r0 = rf(ns)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(component.Namespace) error); ok {
+		r1 = rf(ns)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Namespace provides a mock function with given fields: ksApp, nsName
 func (_m *Component) Namespace(ksApp app.App, nsName string) (component.Namespace, error) {
 	ret := _m.Called(ksApp, nsName)
@@ -118,3 +163,26 @@ func (_m *Component) Namespaces(ksApp app.App, envName string) ([]component.Name
 
 	return r0, r1
 }
+
+// SecretKey provides a mock function with given fields: ksApp
+func (_m *Component) SecretKey(ksApp app.App) ([]byte, error) {
+	ret := _m.Called(ksApp)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(app.App) []byte); ok {
+		r0 = rf(ksApp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(app.App) error); ok {
+		r1 = rf(ksApp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
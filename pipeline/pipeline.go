@@ -2,12 +2,12 @@ package pipeline
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
-	"path/filepath"
-	"regexp"
 
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
 	jsonnet "github.com/google/go-jsonnet"
 	"github.com/ksonnet/ksonnet/metadata/app"
 	"github.com/pkg/errors"
@@ -21,6 +21,14 @@ type Manager interface {
 	Namespaces(ksApp app.App, envName string) ([]component.Namespace, error)
 	Namespace(ksApp app.App, nsName string) (component.Namespace, error)
 	NSResolveParams(ns component.Namespace) (string, error)
+	// NSSchema returns the params schema for a namespace or nil if the
+	// namespace does not have one.
+	NSSchema(ns component.Namespace) (*params.Schema, error)
+	// NSSecrets returns the encrypted secrets for a namespace or an empty
+	// string if the namespace does not have any.
+	NSSecrets(ns component.Namespace) (string, error)
+	// SecretKey returns the key which decrypts secrets.
+	SecretKey(ksApp app.App) ([]byte, error)
 	Components(ns component.Namespace) ([]component.Component, error)
 
 	// EnvParams returns the contents of the params file for an env.
@@ -42,9 +50,20 @@ func (dc *defaultManager) NSResolveParams(ns component.Namespace) (string, error
 	return ns.ResolvedParams()
 }
 
+func (dc *defaultManager) NSSchema(ns component.Namespace) (*params.Schema, error) {
+	return ns.Schema()
+}
+
+func (dc *defaultManager) NSSecrets(ns component.Namespace) (string, error) {
+	return ns.Secrets()
+}
+
+func (dc *defaultManager) SecretKey(ksApp app.App) ([]byte, error) {
+	return component.ReadSecretKey(ksApp)
+}
+
 func (dc *defaultManager) EnvParams(ksApp app.App, envName string) (string, error) {
-	envParamsPath := filepath.Join(ksApp.Root(), "environments", envName, "params.libsonnet")
-	b, err := afero.ReadFile(ksApp.Fs(), envParamsPath)
+	b, err := afero.ReadFile(ksApp.Fs(), component.EnvParamsPath(ksApp, envName))
 	if err != nil {
 		return "", err
 	}
@@ -94,6 +113,7 @@ func (p *Pipeline) Namespaces() ([]component.Namespace, error) {
 }
 
 // EnvParameters creates parameters for a namespace given an environment.
+// Secret params are decrypted and applied before environment overrides.
 func (p *Pipeline) EnvParameters(nsName string) (string, error) {
 	ns, err := p.cm.Namespace(p.app, nsName)
 	if err != nil {
@@ -105,6 +125,23 @@ func (p *Pipeline) EnvParameters(nsName string) (string, error) {
 		return "", err
 	}
 
+	secrets, err := p.cm.NSSecrets(ns)
+	if err != nil {
+		return "", err
+	}
+
+	if secrets != "" {
+		key, err := p.cm.SecretKey(p.app)
+		if err != nil {
+			return "", err
+		}
+
+		paramsStr, err = applySecrets(paramsStr, secrets, key)
+		if err != nil {
+			return "", errors.Wrapf(err, "apply secrets for namespace %q", nsName)
+		}
+	}
+
 	data, err := p.cm.EnvParams(p.app, p.envName)
 	if err != nil {
 		return "", err
@@ -152,6 +189,18 @@ func (p *Pipeline) Objects(filter []string) ([]*unstructured.Unstructured, error
 			return nil, err
 		}
 
+		schema, err := p.cm.NSSchema(ns)
+		if err != nil {
+			return nil, err
+		}
+
+		if schema != nil {
+			paramsStr, err = validateParams(schema, paramsStr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "validate params for namespace %q", ns.Name())
+			}
+		}
+
 		components, err := p.Components(filter)
 		if err != nil {
 			return nil, err
@@ -200,9 +249,58 @@ func filterComponents(filter []string, components []component.Component) []compo
 	return out
 }
 
-var (
-	reParamSwap = regexp.MustCompile(`(?m)import "\.\.\/\.\.\/components\/params\.libsonnet"`)
-)
+// validateParams applies schema defaults to resolved component params and
+// validates them. Globals have already been merged into each component, so only
+// components are checked.
+func validateParams(schema *params.Schema, paramsStr string) (string, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(paramsStr), &doc); err != nil {
+		return "", errors.Wrap(err, "decode params")
+	}
+
+	path := []string{"components"}
+	components, ok := doc["components"]
+	if !ok {
+		return paramsStr, nil
+	}
+
+	schema.ApplyDefaults(path, components)
+	if err := schema.ValidateAt(path, components); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", errors.Wrap(err, "encode params")
+	}
+
+	return string(b), nil
+}
+
+// applySecrets decrypts secrets and merges them over the resolved params.
+func applySecrets(paramsStr, secrets string, key []byte) (string, error) {
+	decrypted, err := params.DecryptSecrets(key, secrets)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(decrypted)
+	if err != nil {
+		return "", errors.Wrap(err, "encode secrets")
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.ExtCode("params", paramsStr)
+	vm.ExtCode("secrets", string(b))
+	return vm.EvaluateSnippet("snippet", snippetApplySecrets)
+}
+
+var snippetApplySecrets = `
+local params = std.extVar("params");
+local secrets = std.extVar("secrets");
+
+std.mergePatch(params, secrets)
+`
 
 // upgradeParams replaces relative params imports with an extVar to handle
 // multiple component namespaces.
@@ -210,7 +308,7 @@ var (
 // ksonnet generates the correct file.
 func upgradeParams(envName, in string) string {
 	logrus.Warnf("rewriting %q environment params to not use relative paths", envName)
-	return reParamSwap.ReplaceAllLiteralString(in, `std.extVar("__ksonnet/params")`)
+	return params.UpgradeImports(in)
 }
 
 func stringInSlice(s string, sl []string) bool {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/bryanl/woowoo/component"
 	cmocks "github.com/bryanl/woowoo/component/mocks"
+	"github.com/bryanl/woowoo/params"
 	"github.com/bryanl/woowoo/pipeline/mocks"
 	appmocks "github.com/ksonnet/ksonnet/metadata/app/mocks"
 	"github.com/stretchr/testify/require"
@@ -33,6 +34,7 @@ func TestPipeline_EnvParameters(t *testing.T) {
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("NSSecrets", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
 
 		got, err := p.EnvParameters("/")
@@ -42,6 +44,31 @@ func TestPipeline_EnvParameters(t *testing.T) {
 	})
 }
 
+func TestPipeline_EnvParameters_secrets(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+		key, err := params.GenerateSecretKey()
+		require.NoError(t, err)
+
+		encrypted, err := params.EncryptValue(key, "hunter2")
+		require.NoError(t, err)
+
+		secrets, err := params.Set([]string{"password"}, params.EmptySecrets, "db", encrypted, "components")
+		require.NoError(t, err)
+
+		ns := component.NewNamespace(p.app, "/")
+		c.On("Namespace", p.app, "/").Return(ns, nil)
+		c.On("NSResolveParams", ns).Return(`{"components": {"db": {"user": "admin"}}}`, nil)
+		c.On("NSSecrets", ns).Return(secrets, nil)
+		c.On("SecretKey", p.app).Return(key, nil)
+		c.On("EnvParams", p.app, "default").Return(`std.extVar("__ksonnet/params")`, nil)
+
+		got, err := p.EnvParameters("/")
+		require.NoError(t, err)
+
+		require.JSONEq(t, `{"components": {"db": {"user": "admin", "password": "hunter2"}}}`, got)
+	})
+}
+
 func TestPipeline_Components(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
 		cpnt := &cmocks.Component{}
@@ -52,6 +79,7 @@ func TestPipeline_Components(t *testing.T) {
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("NSSecrets", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
 		c.On("Components", ns).Return(components, nil)
 
@@ -80,6 +108,7 @@ func TestPipeline_Components_filtered(t *testing.T) {
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("NSSecrets", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
 		c.On("Components", ns).Return(components, nil)
 
@@ -107,7 +136,9 @@ func TestPipeline_Objects(t *testing.T) {
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("NSSecrets", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
+		c.On("NSSchema", ns).Return(nil, nil)
 		c.On("Components", ns).Return(components, nil)
 
 		got, err := p.Objects(nil)
@@ -132,7 +163,9 @@ func TestPipeline_YAML(t *testing.T) {
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("NSSecrets", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
+		c.On("NSSchema", ns).Return(nil, nil)
 		c.On("Components", ns).Return(components, nil)
 
 		r, err := p.YAML(nil)
@@ -147,6 +180,78 @@ func TestPipeline_YAML(t *testing.T) {
 	})
 }
 
+func TestPipeline_Objects_schema(t *testing.T) {
+	schema, err := params.ParseSchema([]byte(`{
+		"properties": {
+			"components": {
+				"properties": {
+					"app": {
+						"required": ["image"],
+						"properties": {
+							"image": {"type": "string"},
+							"replicas": {"type": "integer", "default": 1}
+						}
+					}
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		envParams  string
+		paramsJSON string
+		isErr      bool
+	}{
+		{
+			name:       "defaults applied",
+			envParams:  `{components: {app: {image: "nginx"}}}`,
+			paramsJSON: `{"components":{"app":{"image":"nginx","replicas":1}}}`,
+		},
+		{
+			name:      "missing required param",
+			envParams: `{components: {app: {replicas: 2}}}`,
+			isErr:     true,
+		},
+		{
+			name:      "wrong type",
+			envParams: `{components: {app: {image: "nginx", replicas: "2"}}}`,
+			isErr:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+				u := []*unstructured.Unstructured{{}}
+
+				cpnt := &cmocks.Component{}
+				cpnt.On("Objects", tc.paramsJSON, "default").Return(u, nil)
+				components := []component.Component{cpnt}
+
+				ns := component.NewNamespace(p.app, "/")
+				c.On("Namespaces", p.app, "default").Return([]component.Namespace{ns}, nil)
+				c.On("Namespace", p.app, "/").Return(ns, nil)
+				c.On("NSResolveParams", ns).Return("", nil)
+				c.On("NSSecrets", ns).Return("", nil)
+				c.On("EnvParams", p.app, "default").Return(tc.envParams, nil)
+				c.On("NSSchema", ns).Return(schema, nil)
+				c.On("Components", ns).Return(components, nil)
+
+				got, err := p.Objects(nil)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, u, got)
+			})
+		})
+	}
+}
+
 func Test_upgradeParams(t *testing.T) {
 	in := `local params = import "../../components/params.libsonnet";`
 	expected := `local params = std.extVar("__ksonnet/params");`
@@ -1,5 +1,37 @@
 package client
 
+import "time"
+
+// ApplyStrategy is how objects are updated when they are applied.
+type ApplyStrategy string
+
+const (
+	// ApplyStrategyMerge updates objects with a JSON merge patch. It is the default.
+	ApplyStrategyMerge ApplyStrategy = "merge"
+	// ApplyStrategyStrategic updates objects with a strategic merge patch computed from
+	// the last applied configuration, the live object, and the desired object.
+	ApplyStrategyStrategic ApplyStrategy = "strategic"
+	// ApplyStrategyServerSide updates objects using server-side apply.
+	ApplyStrategyServerSide ApplyStrategy = "server-side"
+
+	// DefaultFieldManager is the field manager used for server-side apply.
+	DefaultFieldManager = "kscomp"
+
+	// DefaultRetries is the default number of times a failed request is retried.
+	DefaultRetries = 5
+	// DefaultRetryBackoff is the default delay before the first retry.
+	DefaultRetryBackoff = 500 * time.Millisecond
+)
+
+// RetryOptions configure retrying requests which fail with transient errors,
+// e.g. throttling, timeouts, and conflicts. The delay doubles after each retry.
+type RetryOptions struct {
+	// Retries is the number of retries after the first attempt. A negative
+	// value uses DefaultRetries.
+	Retries int
+	Backoff time.Duration
+}
+
 // ApplyOptions are options for applying objects to a cluster.
 type ApplyOptions struct {
 	Create bool
@@ -7,10 +39,39 @@ type ApplyOptions struct {
 	SkipGc bool
 	DryRun bool
 	Client *Config
+
+	// Strategy is the strategy used to update objects.
+	Strategy ApplyStrategy
+	// FieldManager is the name of the field manager for server-side apply.
+	FieldManager string
+	// ForceConflicts takes ownership of conflicting fields during server-side apply.
+	ForceConflicts bool
+
+	// Wait waits for applied objects to become ready.
+	Wait bool
+	// WaitTimeout is the maximum time to wait for objects to become ready.
+	WaitTimeout time.Duration
+	// RollbackOnFailure reverts applied objects if applying or waiting fails.
+	RollbackOnFailure bool
+
+	Retry RetryOptions
+
+	// Parallelism is the number of objects in a dependency wave applied concurrently.
+	Parallelism int
+
+	// Inventory records applied objects so they can be pruned without listing
+	// every object in the cluster.
+	Inventory bool
+
+	// Plan is the format (json or yaml) of a plan describing the changes which
+	// would be made. Setting it implies a dry run.
+	Plan string
 }
 
 // DeleteOptions are options for deleting from a cluster.
 type DeleteOptions struct {
 	GracePeriod int64
 	Client      *Config
+
+	Retry RetryOptions
 }
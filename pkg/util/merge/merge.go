@@ -0,0 +1,165 @@
+// Package merge merges Kubernetes objects the way the API server does when
+// they are patched.
+package merge
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// JSON applies a JSON merge patch (RFC 7386) to a target. Lists in the patch
+// replace lists in the target.
+func JSON(target, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetMap, ok := target.(map[string]interface{})
+	if !ok {
+		targetMap = make(map[string]interface{})
+	}
+
+	for k, v := range patchMap {
+		if v == nil {
+			delete(targetMap, k)
+			continue
+		}
+
+		targetMap[k] = JSON(targetMap[k], v)
+	}
+
+	return targetMap
+}
+
+// Strategic applies a strategic merge patch to an object. Lists of items with
+// a merge key, e.g. containers, are merged item by item. Kinds which aren't
+// registered with the client-go scheme, e.g. custom resources, are merged with
+// a JSON merge patch.
+func Strategic(obj, patch map[string]interface{}) (map[string]interface{}, error) {
+	dataStruct, ok := registeredType(obj)
+	if !ok {
+		return JSON(DeepCopy(obj), DeepCopy(patch)).(map[string]interface{}), nil
+	}
+
+	return strategicpatch.StrategicMergeMapPatch(obj, patch, dataStruct)
+}
+
+// ThreeWay returns what current will be after modified is applied to it.
+// original is the configuration which was applied last, and may be nil. Fields
+// in original which aren't in modified are removed. Fields which were set by
+// the server, e.g. defaults inside list items, are kept for registered kinds.
+func ThreeWay(original, modified, current map[string]interface{}) (map[string]interface{}, error) {
+	dataStruct, ok := registeredType(modified)
+	if !ok {
+		expected := DeepCopy(current)
+		if original != nil {
+			pruneRemoved(expected, original, modified)
+		}
+
+		return JSON(expected, DeepCopy(modified)).(map[string]interface{}), nil
+	}
+
+	if original == nil {
+		original = map[string]interface{}{}
+	}
+
+	var data [3][]byte
+	for i, m := range []map[string]interface{}{original, modified, current} {
+		b, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = b
+	}
+
+	patch, err := strategicpatch.CreateThreeWayMergePatch(data[0], data[1], data[2], dataStruct, true)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := strategicpatch.StrategicMergePatch(data[2], patch, dataStruct)
+	if err != nil {
+		return nil, err
+	}
+
+	// Integers are decoded as int64, as they are in objects from the server, so
+	// the result can be compared with current.
+	var expected map[string]interface{}
+	if err := json.Unmarshal(merged, &expected); err != nil {
+		return nil, err
+	}
+
+	return expected, nil
+}
+
+// registeredType returns an instance of the Go type for an object's kind.
+func registeredType(obj map[string]interface{}) (runtime.Object, bool) {
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, false
+	}
+
+	dataStruct, err := scheme.Scheme.New(gv.WithKind(kind))
+	if err != nil {
+		return nil, false
+	}
+
+	return dataStruct, true
+}
+
+// pruneRemoved removes fields from an object which were in the last applied
+// configuration but are no longer in the desired configuration.
+func pruneRemoved(obj, lastApplied, desired map[string]interface{}) {
+	for k, v := range lastApplied {
+		desiredValue, ok := desired[k]
+		if !ok {
+			delete(obj, k)
+			continue
+		}
+
+		lastMap, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		desiredMap, ok := desiredValue.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if objMap, ok := obj[k].(map[string]interface{}); ok {
+			pruneRemoved(objMap, lastMap, desiredMap)
+		}
+	}
+}
+
+// DeepCopy copies a JSON object.
+func DeepCopy(m map[string]interface{}) map[string]interface{} {
+	return deepCopyValue(m).(map[string]interface{})
+}
+
+func deepCopyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			m[k] = deepCopyValue(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, item := range t {
+			s[i] = deepCopyValue(item)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,162 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+func decode(t *testing.T, s string) map[string]interface{} {
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &m))
+	return m
+}
+
+const desiredDeployment = `{
+  "apiVersion": "apps/v1beta2",
+  "kind": "Deployment",
+  "metadata": {"name": "web", "labels": {"app": "web"}},
+  "spec": {
+    "replicas": 2,
+    "template": {
+      "spec": {
+        "containers": [
+          {"name": "web", "image": "nginx:1.13", "ports": [{"containerPort": 80}]}
+        ]
+      }
+    }
+  }
+}`
+
+// liveDeployment is desiredDeployment with fields the server sets.
+const liveDeployment = `{
+  "apiVersion": "apps/v1beta2",
+  "kind": "Deployment",
+  "metadata": {"name": "web", "labels": {"app": "web"}, "uid": "1234", "resourceVersion": "5"},
+  "spec": {
+    "replicas": 2,
+    "template": {
+      "spec": {
+        "containers": [
+          {
+            "name": "web",
+            "image": "nginx:1.13",
+            "imagePullPolicy": "IfNotPresent",
+            "terminationMessagePath": "/dev/termination-log",
+            "ports": [{"containerPort": 80, "protocol": "TCP"}]
+          }
+        ],
+        "restartPolicy": "Always"
+      }
+    }
+  },
+  "status": {"replicas": 2}
+}`
+
+func TestJSON(t *testing.T) {
+	target := decode(t, `{"a": 1, "b": {"c": 2, "d": 3}, "l": [1, 2]}`)
+	patch := decode(t, `{"a": null, "b": {"c": 4}, "l": [3]}`)
+
+	got := JSON(target, patch)
+	require.Equal(t, decode(t, `{"b": {"c": 4, "d": 3}, "l": [3]}`), got)
+}
+
+func TestStrategic(t *testing.T) {
+	cases := []struct {
+		name     string
+		obj      string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "registered kind merges list items",
+			obj:      desiredDeployment,
+			patch:    `{"spec": {"template": {"spec": {"containers": [{"name": "web", "image": "nginx:1.14"}]}}}}`,
+			expected: `[{"name": "web", "image": "nginx:1.14", "ports": [{"containerPort": 80}]}]`,
+		},
+		{
+			name: "unregistered kind replaces lists",
+			obj: `{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "w"},
+				"spec": {"template": {"spec": {"containers": [{"name": "web", "image": "nginx:1.13"}]}}}}`,
+			patch:    `{"spec": {"template": {"spec": {"containers": [{"name": "web", "image": "nginx:1.14"}]}}}}`,
+			expected: `[{"name": "web", "image": "nginx:1.14"}]`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Strategic(decode(t, tc.obj), decode(t, tc.patch))
+			require.NoError(t, err)
+
+			var expected []interface{}
+			require.NoError(t, json.Unmarshal([]byte(tc.expected), &expected))
+
+			spec := got["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+			require.Equal(t, expected, spec["containers"])
+		})
+	}
+}
+
+func TestThreeWay(t *testing.T) {
+	t.Run("unchanged deployment keeps server defaults", func(t *testing.T) {
+		live := decode(t, liveDeployment)
+		got, err := ThreeWay(decode(t, desiredDeployment), decode(t, desiredDeployment), live)
+		require.NoError(t, err)
+		require.Equal(t, decode(t, liveDeployment), got)
+	})
+
+	t.Run("changed field in a list item", func(t *testing.T) {
+		desired := decode(t, desiredDeployment)
+		containers := desired["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+		containers[0].(map[string]interface{})["image"] = "nginx:1.14"
+
+		got, err := ThreeWay(nil, desired, decode(t, liveDeployment))
+		require.NoError(t, err)
+
+		expected := decode(t, liveDeployment)
+		containers = expected["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+		containers[0].(map[string]interface{})["image"] = "nginx:1.14"
+		require.Equal(t, expected, got)
+	})
+
+	t.Run("removed field", func(t *testing.T) {
+		original := decode(t, desiredDeployment)
+		desired := decode(t, desiredDeployment)
+		delete(desired["metadata"].(map[string]interface{}), "labels")
+
+		got, err := ThreeWay(original, desired, decode(t, liveDeployment))
+		require.NoError(t, err)
+
+		expected := decode(t, liveDeployment)
+		delete(expected["metadata"].(map[string]interface{}), "labels")
+		require.Equal(t, expected, got)
+	})
+
+	t.Run("unregistered kind", func(t *testing.T) {
+		original := decode(t, `{"apiVersion": "example.com/v1", "kind": "Widget", "spec": {"a": 1, "b": 2}}`)
+		desired := decode(t, `{"apiVersion": "example.com/v1", "kind": "Widget", "spec": {"a": 3}}`)
+		live := decode(t, `{"apiVersion": "example.com/v1", "kind": "Widget", "spec": {"a": 1, "b": 2, "c": 4}, "status": {}}`)
+
+		got, err := ThreeWay(original, desired, live)
+		require.NoError(t, err)
+		require.Equal(t, decode(t, `{"apiVersion": "example.com/v1", "kind": "Widget", "spec": {"a": 3, "c": 4}, "status": {}}`), got)
+	})
+}
+
+func Test_pruneRemoved(t *testing.T) {
+	obj := decode(t, `{"a": 1, "b": {"c": 2, "d": 3}, "e": 4}`)
+	lastApplied := decode(t, `{"a": 1, "b": {"c": 2, "d": 3}}`)
+	desired := decode(t, `{"b": {"d": 3}}`)
+
+	pruneRemoved(obj, lastApplied, desired)
+	require.Equal(t, decode(t, `{"b": {"d": 3}, "e": 4}`), obj)
+}
+
+func TestDeepCopy(t *testing.T) {
+	m := decode(t, `{"a": {"b": [1, {"c": 2}]}}`)
+	c := DeepCopy(m)
+	c["a"].(map[string]interface{})["b"].([]interface{})[1].(map[string]interface{})["c"] = 3
+
+	require.Equal(t, decode(t, `{"a": {"b": [1, {"c": 2}]}}`), m)
+}